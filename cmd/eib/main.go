@@ -15,6 +15,20 @@ func main() {
 		cmd.NewBuildCommand(build.Run),
 		cmd.NewGenerateCommand(build.Generate),
 		cmd.NewValidateCommand(build.Validate),
+		cmd.NewRenderDefinitionCommand(build.RenderDefinition),
+		cmd.NewPlanCommand(build.Plan),
+		cmd.NewImagesCommand(build.Images),
+		cmd.NewMigrateCommand(build.Migrate),
+		cmd.NewSchemaCommand(build.Schema),
+		cmd.NewInitCommand(build.Init),
+		cmd.NewCacheCommand(cmd.CacheActions{
+			List:   build.CacheList,
+			Prune:  build.CachePrune,
+			Remove: build.CacheRemove,
+			Verify: build.CacheVerify,
+			Export: build.CacheExport,
+			Import: build.CacheImport,
+		}),
 		cmd.NewVersionCommand(build.Version),
 	}
 
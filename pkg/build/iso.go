@@ -3,12 +3,14 @@ package build
 import (
 	_ "embed"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -57,17 +59,7 @@ func (b *Builder) extractIso() error {
 		return fmt.Errorf("creating the ISO extraction script: %w", err)
 	}
 
-	cmd, extractLog, err := b.createIsoCommand(extractIsoLogFile, extractIsoScriptName)
-	if err != nil {
-		return fmt.Errorf("preparing to extract the contents of the ISO: %w", err)
-	}
-	defer func() {
-		if err = extractLog.Close(); err != nil {
-			zap.S().Warnf("failed to close ISO extraction log file properly: %s", err)
-		}
-	}()
-
-	if err = cmd.Run(); err != nil {
+	if err := b.runIsoScript(extractIsoScriptName, extractIsoLogFile); err != nil {
 		return fmt.Errorf("extracting the contents of the ISO: %w", err)
 	}
 
@@ -79,17 +71,7 @@ func (b *Builder) rebuildIso() error {
 		return fmt.Errorf("creating the ISO rebuild script: %w", err)
 	}
 
-	cmd, rebuildLog, err := b.createIsoCommand(rebuildIsoLogFile, rebuildIsoScriptName)
-	if err != nil {
-		return fmt.Errorf("preparing to build the new ISO: %w", err)
-	}
-	defer func() {
-		if err = rebuildLog.Close(); err != nil {
-			zap.S().Warnf("failed to close ISO rebuild log file properly: %s", err)
-		}
-	}()
-
-	if err = cmd.Run(); err != nil {
+	if err := b.runIsoScript(rebuildIsoScriptName, rebuildIsoLogFile); err != nil {
 		return fmt.Errorf("building the new ISO: %w", err)
 	}
 
@@ -133,19 +115,35 @@ func (b *Builder) writeIsoScript(templateContents, outputFilename string) error
 	return nil
 }
 
-func (b *Builder) createIsoCommand(logFilename, scriptName string) (*exec.Cmd, *os.File, error) {
+func (b *Builder) runIsoScript(scriptName, logFilename string) error {
 	fullLogFilename := filepath.Join(b.context.BuildDir, logFilename)
 	logFile, err := os.Create(fullLogFilename)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error opening ISO log file %s: %w", logFilename, err)
+		return fmt.Errorf("error opening ISO log file %s: %w", logFilename, err)
 	}
+	defer func() {
+		if err = logFile.Close(); err != nil {
+			zap.S().Warnf("failed to close ISO log file %s properly: %s", logFilename, err)
+		}
+	}()
+
+	output := log.NewRedactingWriter(logFile)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("failed to flush ISO log file %s properly: %s", logFilename, err)
+		}
+	}()
+
+	return b.createIsoCommand(scriptName, output).Run()
+}
 
+func (b *Builder) createIsoCommand(scriptName string, writer io.Writer) *exec.Cmd {
 	scriptFilename := filepath.Join(b.context.BuildDir, scriptName)
 	cmd := exec.Command(scriptFilename)
-	cmd.Stdout = logFile
-	cmd.Stderr = logFile
+	cmd.Stdout = writer
+	cmd.Stderr = writer
 
-	return cmd, logFile, nil
+	return cmd
 }
 
 func (b *Builder) findExtractedRawImage() (string, error) {
@@ -2,6 +2,7 @@ package build
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -128,16 +129,15 @@ func TestCreateIsoCommand(t *testing.T) {
 	builder := Builder{context: ctx}
 
 	// Test
-	cmd, logFile, err := builder.createIsoCommand("test-log", "test-script")
+	cmd := builder.createIsoCommand("test-script", io.Discard)
 
 	// Verify
-	require.NoError(t, err)
 	require.NotNil(t, cmd)
 
 	expectedCommandPath := filepath.Join(ctx.BuildDir, "test-script")
 	assert.Equal(t, expectedCommandPath, cmd.Path)
-	assert.Equal(t, logFile, cmd.Stdout)
-	assert.Equal(t, logFile, cmd.Stderr)
+	assert.Equal(t, io.Discard, cmd.Stdout)
+	assert.Equal(t, io.Discard, cmd.Stderr)
 }
 
 func TestFindExtractedRawImage(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -16,6 +17,7 @@ import (
 const (
 	copyExec                = "/bin/cp"
 	modifyScriptName        = "modify-raw-image.sh"
+	luksKeyEnv              = "LUKS_KEY"
 	rawBuildLogFile         = "raw-build.log"
 	availableRawDiskSpaceMB = 150
 )
@@ -72,7 +74,14 @@ func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFile
 		}
 	}()
 
-	cmd := b.createModifyCommand(logFile)
+	output := log.NewRedactingWriter(logFile)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Failed to flush raw build log file properly: %s", err)
+		}
+	}()
+
+	cmd := b.createModifyCommand(output)
 	err = cmd.Run()
 	if err != nil {
 		return fmt.Errorf("running the image modification script: %w", err)
@@ -99,7 +108,8 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 
 	expandEncryptedPartition := b.context.ImageDefinition.OperatingSystem.RawConfiguration.ExpandEncryptedPartition
 
-	luksKey := b.context.ImageDefinition.OperatingSystem.RawConfiguration.LUKSKey
+	// The LUKS key is passed to the script via the environment, so that it is not stored in the build directory
+	encrypted := b.context.ImageDefinition.OperatingSystem.RawConfiguration.LUKSKey != ""
 
 	// Assemble the template values
 	values := struct {
@@ -111,7 +121,8 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 		RenameFilesystem         bool
 		DiskSize                 string
 		Arch                     string
-		LUKSKey                  string
+		Encrypted                bool
+		LUKSKeyEnv               string
 		ExpandEncryptedPartition bool
 	}{
 		ImagePath:                imageFilename,
@@ -122,7 +133,8 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 		RenameFilesystem:         renameFilesystem,
 		DiskSize:                 string(b.context.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize),
 		Arch:                     string(b.context.ImageDefinition.Image.Arch),
-		LUKSKey:                  luksKey,
+		Encrypted:                encrypted,
+		LUKSKeyEnv:               luksKeyEnv,
 		ExpandEncryptedPartition: expandEncryptedPartition,
 	}
 
@@ -146,6 +158,10 @@ func (b *Builder) createModifyCommand(writer io.Writer) *exec.Cmd {
 	cmd.Stdout = writer
 	cmd.Stderr = writer
 
+	if luksKey := b.context.ImageDefinition.OperatingSystem.RawConfiguration.LUKSKey; luksKey != "" {
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", luksKeyEnv, luksKey))
+	}
+
 	return cmd
 }
 
@@ -3,6 +3,7 @@ package build
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 )
 
 func TestCreateRawImageCopyCommand(t *testing.T) {
@@ -137,7 +139,7 @@ func TestWriteModifyScript(t *testing.T) {
 				"btrfs filesystem label / INSTALL",
 				"truncate -s 64G",
 				"virt-resize --expand $ROOT_PART",
-				fmt.Sprintf("LUKSFLAG=\"--key all:key:%s\"", luksKey),
+				"LUKSFLAG=\"--key all:key:$LUKS_KEY\"",
 			},
 			expectedMissing: []string{
 				"btrfs filesystem resize max /",
@@ -154,7 +156,7 @@ func TestWriteModifyScript(t *testing.T) {
 				"btrfs filesystem label / INSTALL",
 				"truncate -s 64G",
 				"virt-resize --expand $ROOT_PART",
-				fmt.Sprintf("LUKSFLAG=\"--key all:key:%s\"", luksKey),
+				"LUKSFLAG=\"--key all:key:$LUKS_KEY\"",
 				"btrfs filesystem resize max /",
 			},
 		},
@@ -190,7 +192,8 @@ func TestCreateModifyCommand(t *testing.T) {
 	// Setup
 	builder := Builder{
 		context: &image.Context{
-			BuildDir: "build-dir",
+			BuildDir:        "build-dir",
+			ImageDefinition: &image.Definition{},
 		},
 	}
 	// Test
@@ -203,4 +206,59 @@ func TestCreateModifyCommand(t *testing.T) {
 	assert.Equal(t, expectedPath, cmd.Path)
 	assert.Equal(t, io.Discard, cmd.Stdout)
 	assert.Equal(t, io.Discard, cmd.Stderr)
+	assert.Nil(t, cmd.Env)
+}
+
+func TestModifyRawImage_NoSecretsInBuildDir(t *testing.T) {
+	// Setup
+	const luksKey = "luks-s3cr3t-key"
+	log.RegisterSecrets(luksKey)
+	t.Cleanup(log.ResetSecrets)
+
+	// Stub out guestfish with a script which echoes back the arguments it was provided with
+	binDir := t.TempDir()
+	fakeGuestfish := "#!/bin/sh\necho \"args: $*\"\ncat > /dev/null\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "guestfish"), []byte(fakeGuestfish), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			Arch: image.ArchTypeX86,
+		},
+		OperatingSystem: image.OperatingSystem{
+			RawConfiguration: image.RawConfiguration{
+				LUKSKey: luksKey,
+			},
+		},
+	}
+
+	builder := Builder{context: ctx}
+
+	// Test
+	err := builder.modifyRawImage(filepath.Join(ctx.BuildDir, "image.raw"), true, true)
+
+	// Verify
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.BuildDir, rawBuildLogFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "--key all:key:[REDACTED]")
+
+	err = filepath.WalkDir(ctx.BuildDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		assert.NotContains(t, string(contents), luksKey, "file %s contains the LUKS key", path)
+		return nil
+	})
+	require.NoError(t, err)
 }
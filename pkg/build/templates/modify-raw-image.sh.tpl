@@ -11,7 +11,8 @@ set -euo pipefail
 #  RenameFilesystem          - If true, the filesystem of the image will be renamed (see below for information
 #                            on why this is needed)
 #  Arch                      - The architecture of the image to be built
-#  LUKSKey                   - The key necessary for modifying encrypted raw images
+#  Encrypted                 - If true, the image is encrypted and modified using the LUKS key
+#  LUKSKeyEnv                - Name of the environment variable holding the LUKS key of encrypted images
 #  ExpandEncryptedPartition  - If true, expands the encrypted partition during the build process
 #
 # Guestfish Command Documentation: https://libguestfs.org/guestfish.1.html
@@ -29,8 +30,8 @@ fi
 
 # Set the LUKS key flag for encrypted images
 LUKSFLAG=""
-{{ if .LUKSKey }}
-LUKSFLAG="--key all:key:{{ .LUKSKey }}"
+{{ if .Encrypted }}
+LUKSFLAG="--key all:key:${{ .LUKSKeyEnv }}"
 {{ end }}
 
 # Test the block size of the base image and adapt to suit either 512/4096 byte images
@@ -0,0 +1,383 @@
+package cache
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"go.uber.org/zap"
+)
+
+const (
+	bundleManifestFile = "manifest.json"
+	bundleVersion      = 1
+)
+
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// BundleManifest describes the contents of a cache bundle.
+type BundleManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// EIBVersion is the version of EIB which created the bundle.
+	EIBVersion string `json:"eibVersion,omitempty"`
+	// Definition is the name of the image definition file the bundle was created for.
+	Definition string `json:"definition,omitempty"`
+	// DefinitionDigest is the SHA-256 digest of the image definition in the `sha256:<hex>` format.
+	DefinitionDigest string `json:"definitionDigest,omitempty"`
+	// Entries lists the cache entries in the bundle. The time the entries were last used is not retained.
+	Entries []Entry `json:"entries"`
+}
+
+// Export writes a zstd compressed tarball holding the entries with the given identifiers, verified against
+// their digests, along with a manifest describing them. The entries are locked for the duration of the export.
+//
+// The manifest is populated with the bundled entries and written first, followed by the files stored
+// under `blobs/sha256/`, the same way as in the cache directory.
+func (cache *Cache) Export(w io.Writer, fileIdentifiers []string, manifest *BundleManifest) error {
+	unlock, err := cache.lockAll(fileIdentifiers)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var entries []Entry
+
+	err = cache.withIndex(func(idx *index) error {
+		for _, identifier := range fileIdentifiers {
+			entry, ok := idx.Entries[identifier]
+			if !ok {
+				return fmt.Errorf("searching for identifier '%s' in cache: %w", identifier, fs.ErrNotExist)
+			}
+
+			entry.LastUsed = time.Time{}
+			entries = append(entries, entry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.Identifier, b.Identifier)
+	})
+	entries = slices.CompactFunc(entries, func(a, b Entry) bool {
+		return a.Identifier == b.Identifier
+	})
+
+	manifest.Version = bundleVersion
+	manifest.CreatedAt = time.Now().UTC()
+	manifest.Entries = entries
+
+	zstdWriter, err := zstd.NewWriter(w)
+	if err != nil {
+		return fmt.Errorf("creating zstd writer: %w", err)
+	}
+
+	tarWriter := tar.NewWriter(zstdWriter)
+
+	if err = writeBundleManifest(tarWriter, manifest); err != nil {
+		return err
+	}
+
+	written := map[string]bool{}
+	for _, entry := range entries {
+		if written[entry.Digest] {
+			continue
+		}
+
+		if err = cache.writeBundleBlob(tarWriter, entry); err != nil {
+			return fmt.Errorf("writing identifier '%s' to bundle: %w", entry.Identifier, err)
+		}
+
+		written[entry.Digest] = true
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		return fmt.Errorf("closing tar writer: %w", err)
+	}
+
+	if err = zstdWriter.Close(); err != nil {
+		return fmt.Errorf("closing zstd writer: %w", err)
+	}
+
+	return nil
+}
+
+func writeBundleManifest(tarWriter *tar.Writer, manifest *BundleManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	header := &tar.Header{
+		Name:    bundleManifestFile,
+		Mode:    int64(fileio.NonExecutablePerms),
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}
+
+	if err = tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("writing manifest header: %w", err)
+	}
+
+	if _, err = tarWriter.Write(data); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	return nil
+}
+
+func (cache *Cache) writeBundleBlob(tarWriter *tar.Writer, entry Entry) error {
+	blobPath := cache.blobPath(entry.Digest)
+
+	// Corrupt files are never distributed
+	if err := verifyBlob(blobPath, entry); err != nil {
+		return fmt.Errorf("verifying file: %w", err)
+	}
+
+	file, err := os.Open(blobPath)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	header := &tar.Header{
+		Name:    bundleBlobName(entry.Digest),
+		Mode:    int64(fileio.NonExecutablePerms),
+		Size:    entry.Size,
+		ModTime: entry.FetchedAt,
+	}
+
+	if err = tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("writing file header: %w", err)
+	}
+
+	if _, err = io.Copy(tarWriter, file); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	return nil
+}
+
+// Import verifies the bundle read from the given reader against its manifest and stores its entries.
+// Nothing is stored unless every file in the bundle matches its digest. Entries which are already
+// present are replaced. Returns the manifest of the bundle.
+func (cache *Cache) Import(r io.Reader) (manifest *BundleManifest, err error) {
+	zstdReader, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("creating zstd reader: %w", err)
+	}
+	defer zstdReader.Close()
+
+	tarReader := tar.NewReader(zstdReader)
+
+	manifest, err = readBundleManifest(tarReader)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := map[string]int64{}
+	for _, entry := range manifest.Entries {
+		sizes[entry.Digest] = entry.Size
+	}
+
+	// Verified files are only moved into place once the whole bundle has been read
+	tempPaths := map[string]string{}
+	defer func() {
+		for _, tempPath := range tempPaths {
+			if removeErr := os.Remove(tempPath); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+				zap.S().Warnf("Removing temporary file '%s' failed: %v", tempPath, removeErr)
+			}
+		}
+	}()
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+
+		digest, ok := bundleBlobDigest(header.Name)
+		if !ok || header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected file in bundle: %s", header.Name)
+		}
+
+		size, ok := sizes[digest]
+		if !ok {
+			return nil, fmt.Errorf("file not listed in the bundle manifest: %s", header.Name)
+		}
+
+		if _, ok = tempPaths[digest]; ok {
+			return nil, fmt.Errorf("duplicate file in bundle: %s", header.Name)
+		}
+
+		tempPath, actualDigest, actualSize, err := cache.writeTempBlob(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("extracting %s: %w", header.Name, err)
+		}
+
+		tempPaths[digest] = tempPath
+
+		if actualSize != size {
+			return nil, fmt.Errorf("verifying %s: size mismatch: expected %d bytes, found %d", header.Name, size, actualSize)
+		}
+
+		if actualDigest != digest {
+			return nil, fmt.Errorf("verifying %s: digest mismatch: found %s", header.Name, actualDigest)
+		}
+	}
+
+	for digest := range sizes {
+		if _, ok := tempPaths[digest]; !ok {
+			return nil, fmt.Errorf("file missing from bundle: %s", bundleBlobName(digest))
+		}
+	}
+
+	if err = cache.storeBundleEntries(manifest.Entries, tempPaths); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func readBundleManifest(tarReader *tar.Reader) (*BundleManifest, error) {
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+
+	if header.Name != bundleManifestFile {
+		return nil, fmt.Errorf("invalid bundle: expected %s as the first file, found %s", bundleManifestFile, header.Name)
+	}
+
+	manifest := &BundleManifest{}
+	if err = json.NewDecoder(tarReader).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("parsing bundle manifest: %w", err)
+	}
+
+	if manifest.Version != bundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version: %d", manifest.Version)
+	}
+
+	sizes := map[string]int64{}
+	for _, entry := range manifest.Entries {
+		if entry.Identifier == "" {
+			return nil, fmt.Errorf("invalid bundle manifest: entry without identifier")
+		}
+
+		if !digestRegexp.MatchString(entry.Digest) {
+			return nil, fmt.Errorf("invalid bundle manifest: invalid digest '%s' of identifier '%s'", entry.Digest, entry.Identifier)
+		}
+
+		if size, ok := sizes[entry.Digest]; ok && size != entry.Size {
+			return nil, fmt.Errorf("invalid bundle manifest: conflicting sizes of digest '%s'", entry.Digest)
+		}
+
+		sizes[entry.Digest] = entry.Size
+	}
+
+	return manifest, nil
+}
+
+// storeBundleEntries moves the verified files into place and adds the entries to the index.
+func (cache *Cache) storeBundleEntries(entries []Entry, tempPaths map[string]string) error {
+	identifiers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		identifiers = append(identifiers, entry.Identifier)
+	}
+
+	unlock, err := cache.lockAll(identifiers)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return cache.withIndex(func(idx *index) error {
+		for digest, tempPath := range tempPaths {
+			if err := os.Rename(tempPath, cache.blobPath(digest)); err != nil {
+				return fmt.Errorf("moving file into place: %w", err)
+			}
+		}
+
+		var replaced []string
+
+		now := time.Now().UTC()
+		for _, entry := range entries {
+			if previous, ok := idx.Entries[entry.Identifier]; ok && previous.Digest != entry.Digest {
+				replaced = append(replaced, previous.Digest)
+			}
+
+			entry.LastUsed = now
+			idx.Entries[entry.Identifier] = entry
+		}
+
+		if err := cache.writeIndex(idx); err != nil {
+			return fmt.Errorf("storing bundle entries in cache: %w", err)
+		}
+
+		for _, digest := range replaced {
+			if err := cache.removeUnreferencedBlob(idx, digest); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// lockAll acquires the locks of the given entries in a consistent order.
+func (cache *Cache) lockAll(fileIdentifiers []string) (unlock func(), err error) {
+	identifiers := slices.Clone(fileIdentifiers)
+	slices.Sort(identifiers)
+	identifiers = slices.Compact(identifiers)
+
+	var unlocks []func()
+	unlockAll := func() {
+		for _, u := range unlocks {
+			u()
+		}
+	}
+
+	for _, identifier := range identifiers {
+		u, err := cache.Lock(identifier)
+		if err != nil {
+			unlockAll()
+			return nil, err
+		}
+
+		unlocks = append(unlocks, u)
+	}
+
+	return unlockAll, nil
+}
+
+func bundleBlobName(digest string) string {
+	return path.Join(blobsDir, digestAlgo, strings.TrimPrefix(digest, digestAlgo+":"))
+}
+
+// bundleBlobDigest returns the digest of the file stored under the given name in a bundle.
+func bundleBlobDigest(name string) (string, bool) {
+	hash, found := strings.CutPrefix(name, blobsDir+"/"+digestAlgo+"/")
+	if !found {
+		return "", false
+	}
+
+	digest := digestAlgo + ":" + hash
+	return digest, digestRegexp.MatchString(digest)
+}
@@ -0,0 +1,266 @@
+package cache
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportBundle(t *testing.T, identifiers ...string) (*Cache, []byte) {
+	cache, err := New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	putEntries(t, cache, map[string]string{
+		"v1.30.3+rke2r1/rke2.linux-amd64.tar.gz": "rke2",
+		"images/nginx:1.27-registry.tar.zst":     "nginx",
+		"images/nginx-copy-registry.tar.zst":     "nginx",
+		"unrelated":                              "unrelated",
+	}, nil)
+
+	var bundle bytes.Buffer
+	require.NoError(t, cache.Export(&bundle, identifiers, &BundleManifest{Definition: "definition.yaml"}))
+
+	return cache, bundle.Bytes()
+}
+
+// readBundle returns the names and contents of the files in the bundle, in order.
+func readBundle(t *testing.T, bundle []byte) ([]string, map[string][]byte) {
+	zstdReader, err := zstd.NewReader(bytes.NewReader(bundle))
+	require.NoError(t, err)
+	defer zstdReader.Close()
+
+	var names []string
+	contents := map[string][]byte{}
+
+	tarReader := tar.NewReader(zstdReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+
+		names = append(names, header.Name)
+		contents[header.Name] = data
+	}
+
+	return names, contents
+}
+
+// writeBundle creates a bundle holding the given files, in order.
+func writeBundle(t *testing.T, names []string, contents map[string][]byte) []byte {
+	var bundle bytes.Buffer
+
+	zstdWriter, err := zstd.NewWriter(&bundle)
+	require.NoError(t, err)
+
+	tarWriter := tar.NewWriter(zstdWriter)
+	for _, name := range names {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(contents[name]))}))
+		_, err = tarWriter.Write(contents[name])
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, zstdWriter.Close())
+
+	return bundle.Bytes()
+}
+
+func TestCache_ExportImport(t *testing.T) {
+	// Setup
+	_, bundle := exportBundle(t,
+		"v1.30.3+rke2r1/rke2.linux-amd64.tar.gz",
+		"images/nginx:1.27-registry.tar.zst",
+		"images/nginx-copy-registry.tar.zst",
+	)
+
+	target, err := New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	// Test
+	manifest, err := target.Import(bytes.NewReader(bundle))
+	require.NoError(t, err)
+
+	// Verify
+	assert.Equal(t, "definition.yaml", manifest.Definition)
+	assert.Equal(t, []string{
+		"images/nginx-copy-registry.tar.zst",
+		"images/nginx:1.27-registry.tar.zst",
+		"v1.30.3+rke2r1/rke2.linux-amd64.tar.gz",
+	}, identifiers(manifest.Entries))
+
+	entries, err := target.List()
+	require.NoError(t, err)
+	assert.Equal(t, identifiers(manifest.Entries), identifiers(entries))
+
+	path, err := target.Get("images/nginx:1.27-registry.tar.zst")
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "nginx", string(contents))
+
+	for _, entry := range entries {
+		assert.Equal(t, defaultSource, entry.Source)
+		assert.False(t, entry.LastUsed.IsZero())
+	}
+}
+
+func TestCache_ExportLayout(t *testing.T) {
+	_, bundle := exportBundle(t, "images/nginx:1.27-registry.tar.zst", "images/nginx-copy-registry.tar.zst")
+
+	names, contents := readBundle(t, bundle)
+
+	// Shared contents are only bundled once
+	require.Len(t, names, 2)
+	assert.Equal(t, "manifest.json", names[0])
+	assert.Regexp(t, `^blobs/sha256/[a-f0-9]{64}$`, names[1])
+	assert.Equal(t, "nginx", string(contents[names[1]]))
+
+	var manifest BundleManifest
+	require.NoError(t, json.Unmarshal(contents["manifest.json"], &manifest))
+	assert.Equal(t, 1, manifest.Version)
+	assert.Len(t, manifest.Entries, 2)
+}
+
+func TestCache_ExportMissingEntry(t *testing.T) {
+	cache, err := New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	err = cache.Export(io.Discard, []string{"missing"}, &BundleManifest{})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestCache_ExportCorruptEntry(t *testing.T) {
+	cache, err := New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	putEntries(t, cache, map[string]string{defaultFileIdentifier: defaultFileContents}, nil)
+
+	path, err := cache.Get(defaultFileIdentifier)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("tampered"), 0o600))
+
+	err = cache.Export(io.Discard, []string{defaultFileIdentifier}, &BundleManifest{})
+	assert.ErrorContains(t, err, "verifying file: size mismatch")
+}
+
+func TestCache_ImportInvalidBundle(t *testing.T) {
+	_, bundle := exportBundle(t, "v1.30.3+rke2r1/rke2.linux-amd64.tar.gz", "images/nginx:1.27-registry.tar.zst")
+	names, contents := readBundle(t, bundle)
+
+	tests := map[string]struct {
+		modify        func(names []string, contents map[string][]byte) []string
+		expectedError string
+	}{
+		"Tampered file": {
+			modify: func(names []string, contents map[string][]byte) []string {
+				contents[names[1]] = bytes.ToUpper(contents[names[1]])
+				return names
+			},
+			expectedError: "digest mismatch",
+		},
+		"Truncated file": {
+			modify: func(names []string, contents map[string][]byte) []string {
+				contents[names[1]] = contents[names[1]][:1]
+				return names
+			},
+			expectedError: "size mismatch",
+		},
+		"Missing file": {
+			modify: func(names []string, _ map[string][]byte) []string {
+				return names[:2]
+			},
+			expectedError: "file missing from bundle: blobs/sha256/",
+		},
+		"Unexpected file": {
+			modify: func(names []string, contents map[string][]byte) []string {
+				contents["../../etc/passwd"] = []byte("root")
+				return append(names, "../../etc/passwd")
+			},
+			expectedError: "unexpected file in bundle: ../../etc/passwd",
+		},
+		"Missing manifest": {
+			modify: func(names []string, _ map[string][]byte) []string {
+				return names[1:]
+			},
+			expectedError: "invalid bundle: expected manifest.json as the first file",
+		},
+		"Unsupported version": {
+			modify: func(names []string, contents map[string][]byte) []string {
+				contents["manifest.json"] = []byte(strings.Replace(string(contents["manifest.json"]), `"version": 1`, `"version": 2`, 1))
+				return names
+			},
+			expectedError: "unsupported bundle version: 2",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			modifiedContents := map[string][]byte{}
+			for n, c := range contents {
+				modifiedContents[n] = bytes.Clone(c)
+			}
+			modifiedNames := test.modify(append([]string(nil), names...), modifiedContents)
+
+			cacheDir := t.TempDir()
+			target, err := New(cacheDir, 0)
+			require.NoError(t, err)
+
+			// Test
+			_, err = target.Import(bytes.NewReader(writeBundle(t, modifiedNames, modifiedContents)))
+
+			// Verify
+			assert.ErrorContains(t, err, test.expectedError)
+
+			entries, err := target.List()
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			blobs, err := os.ReadDir(filepath.Join(cacheDir, "blobs", "sha256"))
+			require.NoError(t, err)
+			assert.Empty(t, blobs)
+		})
+	}
+}
+
+func TestCache_ImportReplacesEntries(t *testing.T) {
+	// Setup
+	_, bundle := exportBundle(t, "v1.30.3+rke2r1/rke2.linux-amd64.tar.gz")
+
+	target, err := New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	putEntries(t, target, map[string]string{"v1.30.3+rke2r1/rke2.linux-amd64.tar.gz": "outdated"}, nil)
+
+	outdatedPath, err := target.Get("v1.30.3+rke2r1/rke2.linux-amd64.tar.gz")
+	require.NoError(t, err)
+
+	// Test
+	_, err = target.Import(bytes.NewReader(bundle))
+	require.NoError(t, err)
+
+	// Verify
+	path, err := target.Get("v1.30.3+rke2r1/rke2.linux-amd64.tar.gz")
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "rke2", string(contents))
+	assert.NoFileExists(t, outdatedPath)
+}
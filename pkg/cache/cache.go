@@ -1,111 +1,376 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"hash/fnv"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
-	"strconv"
+	"strings"
+	"time"
 
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"go.uber.org/zap"
 )
 
+const (
+	indexFile       = "index.json"
+	indexVersion    = 1
+	blobsDir        = "blobs"
+	digestAlgo      = "sha256"
+	tempFilePattern = ".tmp-*"
+)
+
+// Entry describes a file stored in the cache.
+type Entry struct {
+	// Identifier is the logical name of the file, e.g. `v1.30.3+rke2r1/rke2-images-core.linux-amd64.tar.zst`.
+	Identifier string `json:"identifier"`
+	// Digest is the SHA-256 digest of the file contents in the `sha256:<hex>` format.
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+	// Source is the location the file was fetched from, if known.
+	Source    string    `json:"source,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	LastUsed  time.Time `json:"lastUsed"`
+}
+
+type index struct {
+	Version int              `json:"version"`
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Cache stores files by the SHA-256 digest of their contents under `blobs/sha256/`,
+// while an index file maps their logical identifiers to the respective digests.
+//
+// The contents of a file are verified against its digest every time it is retrieved.
+// Once the cache exceeds its maximum size, the least recently used entries are evicted.
+//
+// Multiple processes may share the same cache directory. Files are written to temporary
+// locations and moved into place atomically, changes to the index are serialized through
+// an advisory lock, and entries can be locked individually while they are being fetched.
 type Cache struct {
 	cacheDir string
+	// maxSize is the maximum size of the stored files in bytes. Zero means unlimited.
+	maxSize int64
 }
 
-func New(cacheDir string) (*Cache, error) {
-	return &Cache{cacheDir: cacheDir}, nil
+func New(cacheDir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Join(cacheDir, blobsDir, digestAlgo), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("creating blobs directory: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(cacheDir, locksDir), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("creating locks directory: %w", err)
+	}
+
+	return &Cache{cacheDir: cacheDir, maxSize: maxSize}, nil
 }
 
+// Get returns the path to the verified file stored under the given identifier.
+// Entries whose contents no longer match their digest are evicted and reported as missing.
+//
+// Callers are expected to hold the lock of the entry (see Lock) until they are done with the file.
 func (cache *Cache) Get(fileIdentifier string) (path string, err error) {
 	if cache == nil {
 		return "", nil
 	}
 
-	path, err = cache.identifierPath(fileIdentifier)
+	var entry Entry
+	var ok bool
+
+	err = cache.withIndex(func(idx *index) error {
+		entry, ok = idx.Entries[fileIdentifier]
+		return nil
+	})
 	if err != nil {
 		return "", fmt.Errorf("searching for identifier '%s' in cache: %w", fileIdentifier, err)
 	}
 
-	if !exists(path) {
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+
+	path = cache.blobPath(entry.Digest)
+
+	// Verification is performed without holding the index lock since it involves reading the whole file
+	if verifyErr := verifyBlob(path, entry); verifyErr != nil {
+		zap.S().Warnf("Evicting corrupt cache entry with identifier '%s': %v", fileIdentifier, verifyErr)
+
+		err = cache.withIndex(func(idx *index) error {
+			// The entry may have been replaced in the meantime
+			if current, ok := idx.Entries[fileIdentifier]; !ok || current.Digest != entry.Digest {
+				return nil
+			}
+
+			return cache.evict(idx, fileIdentifier)
+		})
+		if err != nil {
+			return "", fmt.Errorf("evicting corrupt entry '%s' from cache: %w", fileIdentifier, err)
+		}
+
 		return "", fs.ErrNotExist
 	}
 
+	err = cache.withIndex(func(idx *index) error {
+		current, ok := idx.Entries[fileIdentifier]
+		if !ok || current.Digest != entry.Digest {
+			return nil
+		}
+
+		current.LastUsed = time.Now().UTC()
+		idx.Entries[fileIdentifier] = current
+
+		return cache.writeIndex(idx)
+	})
+	if err != nil {
+		return "", fmt.Errorf("updating identifier '%s' in cache: %w", fileIdentifier, err)
+	}
+
 	return path, nil
 }
 
-func (cache *Cache) Put(fileIdentifier string, reader io.Reader) error {
+// Put stores the contents of the reader under the given identifier.
+// The source describes where the contents were fetched from and is kept as part of the entry metadata.
+//
+// Callers are expected to hold the lock of the entry (see Lock), so that concurrent
+// builds do not fetch the same contents.
+func (cache *Cache) Put(fileIdentifier, source string, reader io.Reader) error {
 	if cache == nil {
 		return nil
 	}
 
-	path, err := cache.identifierPath(fileIdentifier)
+	var exists bool
+
+	err := cache.withIndex(func(idx *index) error {
+		_, exists = idx.Entries[fileIdentifier]
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("searching for identifier '%s' in cache: %w", fileIdentifier, err)
 	}
 
-	if exists(path) {
+	if exists {
 		zap.S().Warnf("File with identifier '%s' already exists in cache", fileIdentifier)
 		return fs.ErrExist
 	}
 
 	zap.S().Infof("Storing file with identifier '%s' in cache", fileIdentifier)
 
-	file, err := os.Create(path)
+	// The contents are written without holding the index lock and only moved into place once complete
+	tempPath, digest, size, err := cache.writeTempBlob(reader)
 	if err != nil {
-		return fmt.Errorf("creating file: %w", err)
+		return err
 	}
 
-	if _, err = io.Copy(file, reader); err != nil {
-		_ = file.Close()
+	err = cache.withIndex(func(idx *index) error {
+		previous, replaced := idx.Entries[fileIdentifier]
+
+		// Identical contents stored under a different identifier are shared
+		if err := os.Rename(tempPath, cache.blobPath(digest)); err != nil {
+			return fmt.Errorf("moving file into place: %w", err)
+		}
+
+		now := time.Now().UTC()
+		idx.Entries[fileIdentifier] = Entry{
+			Identifier: fileIdentifier,
+			Digest:     digest,
+			Size:       size,
+			Source:     source,
+			FetchedAt:  now,
+			LastUsed:   now,
+		}
+
+		if err := cache.writeIndex(idx); err != nil {
+			return fmt.Errorf("storing identifier '%s' in cache: %w", fileIdentifier, err)
+		}
+
+		// The entry was stored by another build which did not hold the entry lock in the meantime
+		if replaced && previous.Digest != digest {
+			if err := cache.removeUnreferencedBlob(idx, previous.Digest); err != nil {
+				return err
+			}
+		}
+
+		if cache.maxSize > 0 {
+			if _, err := cache.evictLeastRecentlyUsed(idx, cache.maxSize, fileIdentifier); err != nil {
+				return fmt.Errorf("enforcing cache size limit: %w", err)
+			}
+		}
 
-		err = fmt.Errorf("storing file: %w", err)
-		if removeErr := os.Remove(path); removeErr != nil && !errors.Is(err, fs.ErrNotExist) {
-			return errors.Join(
-				err,
-				fmt.Errorf("removing partially downloaded file '%s' from cache: %w", path, removeErr))
+		return nil
+	})
+	if err != nil {
+		if removeErr := os.Remove(tempPath); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+			err = errors.Join(err, fmt.Errorf("removing partially stored file '%s' from cache: %w", tempPath, removeErr))
 		}
 
 		return err
 	}
 
-	return file.Close()
+	return nil
 }
 
-func (cache *Cache) identifierPath(fileIdentifier string) (string, error) {
-	identifier, err := identifierHash(fileIdentifier)
+// writeTempBlob writes the contents of the reader to a temporary file next to the blobs,
+// computing their digest while copying.
+func (cache *Cache) writeTempBlob(reader io.Reader) (tempPath, digest string, size int64, err error) {
+	dir := filepath.Join(cache.cacheDir, blobsDir, digestAlgo)
+
+	file, err := os.CreateTemp(dir, tempFilePattern)
 	if err != nil {
-		return "", err
+		return "", "", 0, fmt.Errorf("creating file: %w", err)
+	}
+
+	path := file.Name()
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if removeErr := os.Remove(path); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+			err = errors.Join(err, fmt.Errorf("removing partially stored file '%s' from cache: %w", path, removeErr))
+		}
+	}()
+
+	hash := sha256.New()
+
+	size, err = io.Copy(io.MultiWriter(file, hash), reader)
+	if err != nil {
+		_ = file.Close()
+		return "", "", 0, fmt.Errorf("storing file: %w", err)
 	}
 
-	return filepath.Join(cache.cacheDir, identifier), nil
+	if err = file.Close(); err != nil {
+		return "", "", 0, fmt.Errorf("closing file: %w", err)
+	}
+
+	if err = os.Chmod(path, fileio.NonExecutablePerms); err != nil {
+		return "", "", 0, fmt.Errorf("adjusting file permissions: %w", err)
+	}
+
+	digest = digestAlgo + ":" + hex.EncodeToString(hash.Sum(nil))
+
+	return path, digest, size, nil
 }
 
-func identifierHash(identifier string) (string, error) {
-	h := fnv.New64()
+// evict removes the entry from the index, along with its blob unless it is shared with other entries.
+// Must be called while holding the index lock.
+func (cache *Cache) evict(idx *index, fileIdentifier string) error {
+	entry := idx.Entries[fileIdentifier]
+	delete(idx.Entries, fileIdentifier)
+
+	if err := cache.writeIndex(idx); err != nil {
+		return err
+	}
+
+	return cache.removeUnreferencedBlob(idx, entry.Digest)
+}
+
+// removeUnreferencedBlob removes the blob with the given digest unless it is referenced by an entry.
+func (cache *Cache) removeUnreferencedBlob(idx *index, digest string) error {
+	for _, e := range idx.Entries {
+		if e.Digest == digest {
+			return nil
+		}
+	}
 
-	if _, err := h.Write([]byte(identifier)); err != nil {
-		return "", err
+	if err := os.Remove(cache.blobPath(digest)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing file: %w", err)
 	}
 
-	hash := strconv.FormatUint(h.Sum64(), 10)
+	return nil
+}
 
-	zap.S().Debugf("Generated hash '%s' from identifier '%s'", hash, identifier)
-	return hash, nil
+func (cache *Cache) blobPath(digest string) string {
+	return filepath.Join(cache.cacheDir, blobsDir, digestAlgo, strings.TrimPrefix(digest, digestAlgo+":"))
 }
 
-func exists(path string) bool {
-	if _, err := os.Stat(path); err != nil {
-		if !errors.Is(err, fs.ErrNotExist) {
-			zap.S().Warnf("Looking for file with identifier failed: %v", err)
+func (cache *Cache) readIndex() (*index, error) {
+	idx := &index{Version: indexVersion, Entries: map[string]Entry{}}
+
+	data, err := os.ReadFile(filepath.Join(cache.cacheDir, indexFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return idx, nil
 		}
 
-		return false
+		return nil, fmt.Errorf("reading index: %w", err)
+	}
+
+	if err = json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("parsing index: %w", err)
+	}
+
+	if idx.Entries == nil {
+		idx.Entries = map[string]Entry{}
+	}
+
+	return idx, nil
+}
+
+func (cache *Cache) writeIndex(idx *index) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding index: %w", err)
+	}
+
+	// The index is replaced atomically so that it is never observed partially written
+	file, err := os.CreateTemp(cache.cacheDir, tempFilePattern)
+	if err != nil {
+		return fmt.Errorf("creating index file: %w", err)
+	}
+
+	tempPath := file.Name()
+
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("writing index: %w", err)
+	}
+
+	if err = file.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("closing index file: %w", err)
+	}
+
+	if err = os.Chmod(tempPath, fileio.NonExecutablePerms); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("adjusting index file permissions: %w", err)
+	}
+
+	if err = os.Rename(tempPath, filepath.Join(cache.cacheDir, indexFile)); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("moving index into place: %w", err)
+	}
+
+	return nil
+}
+
+// verifyBlob checks that the stored file matches the size and digest of the entry.
+func verifyBlob(path string, entry Entry) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	if size != entry.Size {
+		return fmt.Errorf("size mismatch: expected %d bytes, found %d", entry.Size, size)
+	}
+
+	if digest := digestAlgo + ":" + hex.EncodeToString(hash.Sum(nil)); digest != entry.Digest {
+		return fmt.Errorf("digest mismatch: expected %s, found %s", entry.Digest, digest)
 	}
 
-	return true
+	return nil
 }
@@ -1,10 +1,14 @@
 package cache
 
 import (
+	"encoding/json"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,6 +18,7 @@ var (
 	defaultCacheDir       = "test-cache"
 	defaultFileIdentifier = "some-cool-filename"
 	defaultFileContents   = "some-data"
+	defaultSource         = "https://example.com/some-cool-filename"
 )
 
 func setup(t *testing.T, cacheDir string) (cache *Cache, teardown func()) {
@@ -23,7 +28,7 @@ func setup(t *testing.T, cacheDir string) (cache *Cache, teardown func()) {
 
 	assert.NoError(t, os.MkdirAll(cacheDir, os.ModePerm))
 
-	cache, err := New(cacheDir)
+	cache, err := New(cacheDir, 0)
 	require.NoError(t, err)
 
 	return cache, func() {
@@ -38,7 +43,7 @@ func TestCache(t *testing.T) {
 	fileIdentifier := defaultFileIdentifier
 	fileContents := defaultFileContents
 
-	require.NoError(t, cache.Put(fileIdentifier, strings.NewReader(fileContents)))
+	require.NoError(t, cache.Put(fileIdentifier, defaultSource, strings.NewReader(fileContents)))
 
 	path, err := cache.Get(fileIdentifier)
 	require.NoError(t, err)
@@ -59,7 +64,7 @@ func TestCacheNoDir(t *testing.T) {
 	fileContents := defaultFileContents
 
 	// No error because the Put function immediately returns nil when cache is disabled
-	require.NoError(t, cache.Put(fileIdentifier, strings.NewReader(fileContents)))
+	require.NoError(t, cache.Put(fileIdentifier, defaultSource, strings.NewReader(fileContents)))
 
 	// No error because the Get function immediately returns nil when cache is disabled
 	// But we confirm that the File doesn't exist
@@ -90,6 +95,122 @@ func TestCache_DoubleInsert(t *testing.T) {
 	fileIdentifier := "https://raw.githubusercontent.com/suse-edge/edge-image-builder/main/README.md"
 	fileContents := defaultFileContents
 
-	require.NoError(t, cache.Put(fileIdentifier, strings.NewReader(fileContents)))
-	assert.ErrorIs(t, cache.Put(fileIdentifier, strings.NewReader(fileContents)), fs.ErrExist)
+	require.NoError(t, cache.Put(fileIdentifier, defaultSource, strings.NewReader(fileContents)))
+	assert.ErrorIs(t, cache.Put(fileIdentifier, defaultSource, strings.NewReader(fileContents)), fs.ErrExist)
+}
+
+func TestCache_Index(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	before := time.Now().UTC()
+	require.NoError(t, cache.Put(defaultFileIdentifier, defaultSource, strings.NewReader(defaultFileContents)))
+
+	data, err := os.ReadFile(filepath.Join(defaultCacheDir, "index.json"))
+	require.NoError(t, err)
+
+	var idx index
+	require.NoError(t, json.Unmarshal(data, &idx))
+
+	assert.Equal(t, 1, idx.Version)
+	require.Contains(t, idx.Entries, defaultFileIdentifier)
+
+	entry := idx.Entries[defaultFileIdentifier]
+	assert.Equal(t, defaultFileIdentifier, entry.Identifier)
+	// printf "some-data" | sha256sum
+	assert.Equal(t, "sha256:9332d94d5ee69ad17d310e62cd101d70f578024fd5e8d1647f8073f886c894e1", entry.Digest)
+	assert.EqualValues(t, len(defaultFileContents), entry.Size)
+	assert.Equal(t, defaultSource, entry.Source)
+	assert.False(t, entry.FetchedAt.Before(before.Truncate(time.Second)))
+
+	path, err := cache.Get(defaultFileIdentifier)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(defaultCacheDir, "blobs", "sha256", strings.TrimPrefix(entry.Digest, "sha256:")), path)
+}
+
+func TestCache_SharedContents(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	require.NoError(t, cache.Put("v1/artefact", defaultSource, strings.NewReader(defaultFileContents)))
+	require.NoError(t, cache.Put("v2/artefact", defaultSource, strings.NewReader(defaultFileContents)))
+
+	path1, err := cache.Get("v1/artefact")
+	require.NoError(t, err)
+
+	path2, err := cache.Get("v2/artefact")
+	require.NoError(t, err)
+
+	assert.Equal(t, path1, path2)
+}
+
+func TestCache_CorruptEntry(t *testing.T) {
+	tests := map[string]struct {
+		corrupt func(t *testing.T, path string)
+	}{
+		"Tampered contents": {
+			corrupt: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("some-evil"), 0o600))
+			},
+		},
+		"Truncated contents": {
+			corrupt: func(t *testing.T, path string) {
+				require.NoError(t, os.Truncate(path, 4))
+			},
+		},
+		"Missing contents": {
+			corrupt: func(t *testing.T, path string) {
+				require.NoError(t, os.Remove(path))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			cache, teardown := setup(t, defaultCacheDir)
+			defer teardown()
+
+			require.NoError(t, cache.Put(defaultFileIdentifier, defaultSource, strings.NewReader(defaultFileContents)))
+
+			path, err := cache.Get(defaultFileIdentifier)
+			require.NoError(t, err)
+
+			test.corrupt(t, path)
+
+			// Test
+			_, err = cache.Get(defaultFileIdentifier)
+
+			// Verify
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+			assert.NoFileExists(t, path)
+
+			idx, err := cache.readIndex()
+			require.NoError(t, err)
+			assert.NotContains(t, idx.Entries, defaultFileIdentifier)
+
+			// The evicted entry can be stored again
+			require.NoError(t, cache.Put(defaultFileIdentifier, defaultSource, strings.NewReader(defaultFileContents)))
+
+			_, err = cache.Get(defaultFileIdentifier)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCache_FailedPut(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	reader := iotest.ErrReader(fs.ErrClosed)
+
+	err := cache.Put(defaultFileIdentifier, defaultSource, reader)
+	require.ErrorContains(t, err, "storing file: file already closed")
+
+	_, err = cache.Get(defaultFileIdentifier)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	entries, err := os.ReadDir(filepath.Join(defaultCacheDir, "blobs", "sha256"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
 }
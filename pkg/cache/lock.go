@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"go.uber.org/zap"
+)
+
+const (
+	locksDir      = "locks"
+	indexLockFile = "index.lock"
+)
+
+// fileLock is an advisory lock held on a file through flock(2).
+//
+// Locks are associated with the open file description rather than the process,
+// so they exclude concurrent holders within the same process as well as across
+// processes sharing the cache directory, e.g. several containers mounting the same volume.
+type fileLock struct {
+	file *os.File
+}
+
+// errLocked is returned when a non-blocking attempt to acquire a lock fails because it is already held.
+var errLocked = errors.New("lock is held by another process")
+
+// acquireLock acquires an exclusive lock on the file at the given path, creating it if necessary.
+// Blocks until the lock is available unless wait is false, in which case errLocked is returned instead.
+func acquireLock(path string, wait bool) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, fileio.NonExecutablePerms)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	how := syscall.LOCK_EX
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+
+	for {
+		err = syscall.Flock(int(file.Fd()), how)
+		if !errors.Is(err, syscall.EINTR) {
+			break
+		}
+	}
+
+	if err != nil {
+		_ = file.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errLocked
+		}
+
+		return nil, fmt.Errorf("locking file: %w", err)
+	}
+
+	return &fileLock{file: file}, nil
+}
+
+func (l *fileLock) release() {
+	if err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN); err != nil {
+		zap.S().Warnf("Releasing lock '%s' failed: %v", l.file.Name(), err)
+	}
+
+	if err := l.file.Close(); err != nil {
+		zap.S().Warnf("Closing lock file '%s' failed: %v", l.file.Name(), err)
+	}
+}
+
+// Lock acquires an exclusive lock on the entry with the given identifier, blocking while it is held
+// by another build. Callers hold the lock from looking up an entry until they are done with its file,
+// so that concurrent builds wait for an in-flight fetch instead of duplicating it. Locked entries
+// are never evicted. The entry does not need to exist in order to be locked.
+//
+// The returned function releases the lock.
+func (cache *Cache) Lock(fileIdentifier string) (unlock func(), err error) {
+	if cache == nil {
+		return func() {}, nil
+	}
+
+	lock, err := acquireLock(cache.entryLockPath(fileIdentifier), true)
+	if err != nil {
+		return nil, fmt.Errorf("locking identifier '%s' in cache: %w", fileIdentifier, err)
+	}
+
+	return lock.release, nil
+}
+
+// tryLock acquires the lock on the entry with the given identifier if it is not held by another build.
+func (cache *Cache) tryLock(fileIdentifier string) (unlock func(), ok bool, err error) {
+	lock, err := acquireLock(cache.entryLockPath(fileIdentifier), false)
+	if err != nil {
+		if errors.Is(err, errLocked) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("locking identifier '%s' in cache: %w", fileIdentifier, err)
+	}
+
+	return lock.release, true, nil
+}
+
+// withIndex runs the given function while holding the index lock, providing the current index.
+// Changes made to the index are only persisted if the function calls writeIndex.
+func (cache *Cache) withIndex(fn func(idx *index) error) error {
+	lock, err := acquireLock(filepath.Join(cache.cacheDir, indexLockFile), true)
+	if err != nil {
+		return fmt.Errorf("locking index: %w", err)
+	}
+	defer lock.release()
+
+	idx, err := cache.readIndex()
+	if err != nil {
+		return err
+	}
+
+	return fn(idx)
+}
+
+// entryLockPath returns the path of the lock file of the given identifier. Identifiers are hashed
+// since they contain path separators and characters which are not valid in file names.
+func (cache *Cache) entryLockPath(fileIdentifier string) string {
+	hash := sha256.Sum256([]byte(fileIdentifier))
+	return filepath.Join(cache.cacheDir, locksDir, hex.EncodeToString(hash[:])+".lock")
+}
@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fetch mimics a build retrieving an artefact, downloading it only if it is not cached yet.
+func fetch(cache *Cache, identifier string, downloads *atomic.Int32) error {
+	unlock, err := cache.Lock(identifier)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err = cache.Get(identifier); err == nil {
+		return nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	downloads.Add(1)
+	// Keep the entry in flight long enough for the other builds to wait for it
+	time.Sleep(50 * time.Millisecond)
+
+	return cache.Put(identifier, defaultSource, strings.NewReader(defaultFileContents))
+}
+
+func TestCache_LockWaitsForInFlightFetch(t *testing.T) {
+	// Setup
+	cacheDir := t.TempDir()
+
+	// Separate instances share nothing but the cache directory, same as separate processes
+	var caches []*Cache
+	for i := 0; i < 5; i++ {
+		cache, err := New(cacheDir, 0)
+		require.NoError(t, err)
+
+		caches = append(caches, cache)
+	}
+
+	var downloads atomic.Int32
+	var wg sync.WaitGroup
+	errs := make([]error, len(caches))
+
+	// Test
+	for i, cache := range caches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fetch(cache, defaultFileIdentifier, &downloads)
+		}()
+	}
+	wg.Wait()
+
+	// Verify
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.EqualValues(t, 1, downloads.Load())
+
+	path, err := caches[0].Get(defaultFileIdentifier)
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, defaultFileContents, string(contents))
+}
+
+func TestCache_ConcurrentPuts(t *testing.T) {
+	// Setup
+	cacheDir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+
+	// Test
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cache, err := New(cacheDir, 0)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			errs[i] = cache.Put(fmt.Sprintf("artefact-%02d", i), defaultSource, strings.NewReader(fmt.Sprintf("contents-%d", i)))
+		}()
+	}
+	wg.Wait()
+
+	// Verify
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	cache, err := New(cacheDir, 0)
+	require.NoError(t, err)
+
+	// No update of the index is lost
+	entries, err := cache.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, len(errs))
+
+	verified, err := cache.Verify()
+	require.NoError(t, err)
+	assert.Empty(t, verified)
+
+	// Temporary files are never left behind
+	tempFiles, err := filepath.Glob(filepath.Join(cacheDir, tempFilePattern))
+	require.NoError(t, err)
+	assert.Empty(t, tempFiles)
+}
+
+func TestCache_LockedEntriesAreNotEvicted(t *testing.T) {
+	// Setup
+	cache, err := New(t.TempDir(), 10)
+	require.NoError(t, err)
+
+	putEntries(t, cache, map[string]string{
+		"in-use": "1111",
+		"old":    "2222",
+	}, map[string]int{"in-use": 2, "old": 1})
+
+	unlock, err := cache.Lock("in-use")
+	require.NoError(t, err)
+	defer unlock()
+
+	// Test
+	require.NoError(t, cache.Put("new", defaultSource, strings.NewReader("333333")))
+
+	evicted, err := cache.Prune(time.Hour, 0)
+	require.NoError(t, err)
+
+	corrupt, err := cache.Verify()
+	require.NoError(t, err)
+
+	// Verify
+	assert.Empty(t, evicted)
+	assert.Empty(t, corrupt)
+
+	entries, err := cache.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"in-use", "new"}, identifiers(entries))
+}
+
+func TestCache_RemoveWaitsForLock(t *testing.T) {
+	// Setup
+	cache, err := New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	putEntries(t, cache, map[string]string{defaultFileIdentifier: defaultFileContents}, nil)
+
+	unlock, err := cache.Lock(defaultFileIdentifier)
+	require.NoError(t, err)
+
+	removed := make(chan error)
+
+	// Test
+	go func() {
+		removed <- cache.Remove(defaultFileIdentifier)
+	}()
+
+	// Verify
+	select {
+	case err = <-removed:
+		require.FailNow(t, "entry removed while locked", "error: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	assert.NoError(t, <-removed)
+
+	_, err = cache.Get(defaultFileIdentifier)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestCache_PruneStaleTempFiles(t *testing.T) {
+	// Setup
+	cacheDir := t.TempDir()
+
+	cache, err := New(cacheDir, 0)
+	require.NoError(t, err)
+
+	staleBlob := filepath.Join(cacheDir, "blobs", "sha256", ".tmp-stale")
+	staleIndex := filepath.Join(cacheDir, ".tmp-stale")
+	inFlightBlob := filepath.Join(cacheDir, "blobs", "sha256", ".tmp-in-flight")
+
+	for _, path := range []string{staleBlob, staleIndex, inFlightBlob} {
+		require.NoError(t, os.WriteFile(path, []byte("partial"), 0o600))
+	}
+
+	staleTime := time.Now().Add(-2 * staleTempFileAge)
+	require.NoError(t, os.Chtimes(staleBlob, staleTime, staleTime))
+	require.NoError(t, os.Chtimes(staleIndex, staleTime, staleTime))
+
+	// Test
+	_, err = cache.Prune(0, 0)
+	require.NoError(t, err)
+
+	// Verify
+	assert.NoFileExists(t, staleBlob)
+	assert.NoFileExists(t, staleIndex)
+	assert.FileExists(t, inFlightBlob)
+}
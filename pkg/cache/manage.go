@@ -0,0 +1,366 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	legacyImagesDir = "images"
+
+	// staleTempFileAge is the age after which temporary files are considered abandoned by interrupted builds.
+	staleTempFileAge = 24 * time.Hour
+)
+
+// legacyEntryRegexp matches the files stored by earlier versions of the cache, which were named after
+// an FNV-64 hash of their identifier.
+var legacyEntryRegexp = regexp.MustCompile(`^\d+$`)
+
+// List returns the cache entries ordered by their identifier.
+func (cache *Cache) List() ([]Entry, error) {
+	var entries []Entry
+
+	err := cache.withIndex(func(idx *index) error {
+		entries = sortedEntries(idx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Size returns the total size of the files stored in the cache in bytes.
+// Files shared by multiple entries are only counted once.
+func (cache *Cache) Size() (int64, error) {
+	var size int64
+
+	err := cache.withIndex(func(idx *index) error {
+		size = totalSize(idx)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// Contains reports whether an entry is stored under the given identifier and its file is present
+// with the expected size. Unlike Get, the contents are not verified against their digest
+// and the time the entry was last used is not updated.
+func (cache *Cache) Contains(fileIdentifier string) (bool, error) {
+	var entry Entry
+	var ok bool
+
+	err := cache.withIndex(func(idx *index) error {
+		entry, ok = idx.Entries[fileIdentifier]
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("searching for identifier '%s' in cache: %w", fileIdentifier, err)
+	}
+
+	if !ok {
+		return false, nil
+	}
+
+	info, err := os.Stat(cache.blobPath(entry.Digest))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("describing file of identifier '%s': %w", fileIdentifier, err)
+	}
+
+	return info.Size() == entry.Size, nil
+}
+
+// Remove evicts the entry stored under the given identifier,
+// waiting for builds which are currently using it to release it.
+func (cache *Cache) Remove(fileIdentifier string) error {
+	unlock, err := cache.Lock(fileIdentifier)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return cache.withIndex(func(idx *index) error {
+		if _, ok := idx.Entries[fileIdentifier]; !ok {
+			return fs.ErrNotExist
+		}
+
+		return cache.evict(idx, fileIdentifier)
+	})
+}
+
+// Prune evicts the entries which have not been used for longer than the given duration,
+// followed by the least recently used entries until the cache fits the given size in bytes.
+// Zero values disable the respective criteria. Entries locked by running builds are skipped.
+//
+// Files left behind by earlier versions of the cache or by interrupted writes are removed as well.
+// Returns the evicted entries.
+func (cache *Cache) Prune(olderThan time.Duration, maxSize int64) ([]Entry, error) {
+	var evicted []Entry
+
+	err := cache.withIndex(func(idx *index) error {
+		if olderThan > 0 {
+			threshold := time.Now().Add(-olderThan)
+
+			for _, entry := range sortedEntries(idx) {
+				if entry.LastUsed.After(threshold) {
+					continue
+				}
+
+				ok, err := cache.evictUnlessLocked(idx, entry.Identifier)
+				if err != nil {
+					return fmt.Errorf("evicting entry '%s': %w", entry.Identifier, err)
+				}
+
+				if ok {
+					evicted = append(evicted, entry)
+				}
+			}
+		}
+
+		if maxSize > 0 {
+			lru, err := cache.evictLeastRecentlyUsed(idx, maxSize, "")
+			if err != nil {
+				return err
+			}
+
+			evicted = append(evicted, lru...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cache.removeLegacyEntries(); err != nil {
+		return nil, fmt.Errorf("removing legacy entries: %w", err)
+	}
+
+	if err = cache.removeStaleTempFiles(); err != nil {
+		return nil, fmt.Errorf("removing stale temporary files: %w", err)
+	}
+
+	return evicted, nil
+}
+
+// Verify checks the contents of every entry against its digest and evicts the corrupt ones.
+// Entries locked by running builds are skipped, since they are verified on retrieval.
+// Returns the evicted entries.
+func (cache *Cache) Verify() ([]Entry, error) {
+	entries, err := cache.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var corrupt []Entry
+
+	for _, entry := range entries {
+		ok, err := cache.verifyEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("verifying entry '%s': %w", entry.Identifier, err)
+		}
+
+		if !ok {
+			corrupt = append(corrupt, entry)
+		}
+	}
+
+	return corrupt, nil
+}
+
+// verifyEntry checks the contents of the entry, evicting it if corrupt. Reports false if the entry was evicted.
+func (cache *Cache) verifyEntry(entry Entry) (bool, error) {
+	unlock, ok, err := cache.tryLock(entry.Identifier)
+	if err != nil {
+		return false, err
+	}
+
+	if !ok {
+		zap.S().Infof("Skipping verification of cache entry with identifier '%s' which is in use", entry.Identifier)
+		return true, nil
+	}
+	defer unlock()
+
+	verifyErr := verifyBlob(cache.blobPath(entry.Digest), entry)
+	if verifyErr == nil {
+		return true, nil
+	}
+
+	zap.S().Warnf("Evicting corrupt cache entry with identifier '%s': %v", entry.Identifier, verifyErr)
+
+	err = cache.withIndex(func(idx *index) error {
+		// The entry may have been replaced since it was listed
+		if current, ok := idx.Entries[entry.Identifier]; !ok || current.Digest != entry.Digest {
+			return nil
+		}
+
+		return cache.evict(idx, entry.Identifier)
+	})
+
+	return false, err
+}
+
+// evictLeastRecentlyUsed evicts entries, starting from the least recently used one, until the total size
+// of the cache does not exceed the given size in bytes. The entry with the given identifier is retained,
+// as well as the ones locked by running builds. Must be called while holding the index lock.
+func (cache *Cache) evictLeastRecentlyUsed(idx *index, maxSize int64, retain string) ([]Entry, error) {
+	entries := sortedEntries(idx)
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return a.LastUsed.Compare(b.LastUsed)
+	})
+
+	var evicted []Entry
+
+	for _, entry := range entries {
+		if totalSize(idx) <= maxSize {
+			return evicted, nil
+		}
+
+		if entry.Identifier == retain {
+			continue
+		}
+
+		ok, err := cache.evictUnlessLocked(idx, entry.Identifier)
+		if err != nil {
+			return nil, fmt.Errorf("evicting entry '%s': %w", entry.Identifier, err)
+		}
+
+		if ok {
+			zap.S().Infof("Evicted least recently used cache entry with identifier '%s'", entry.Identifier)
+			evicted = append(evicted, entry)
+		}
+	}
+
+	if size := totalSize(idx); size > maxSize {
+		zap.S().Warnf("Cache size of %d bytes exceeds the configured limit of %d bytes", size, maxSize)
+	}
+
+	return evicted, nil
+}
+
+// evictUnlessLocked evicts the entry unless it is locked by a running build. Reports whether the entry was evicted.
+// Must be called while holding the index lock.
+func (cache *Cache) evictUnlessLocked(idx *index, fileIdentifier string) (bool, error) {
+	unlock, ok, err := cache.tryLock(fileIdentifier)
+	if err != nil {
+		return false, err
+	}
+
+	if !ok {
+		zap.S().Infof("Skipping eviction of cache entry with identifier '%s' which is in use", fileIdentifier)
+		return false, nil
+	}
+	defer unlock()
+
+	if err = cache.evict(idx, fileIdentifier); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// removeStaleTempFiles removes the temporary files left behind by builds which were interrupted while
+// writing to the cache. Recently modified files are kept since they may belong to a running build.
+func (cache *Cache) removeStaleTempFiles() error {
+	dirs := []string{cache.cacheDir, filepath.Join(cache.cacheDir, blobsDir, digestAlgo)}
+
+	for _, dir := range dirs {
+		tempFiles, err := filepath.Glob(filepath.Join(dir, tempFilePattern))
+		if err != nil {
+			return fmt.Errorf("searching for temporary files: %w", err)
+		}
+
+		for _, tempFile := range tempFiles {
+			info, err := os.Stat(tempFile)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+
+				return fmt.Errorf("describing '%s': %w", tempFile, err)
+			}
+
+			if time.Since(info.ModTime()) < staleTempFileAge {
+				continue
+			}
+
+			zap.S().Infof("Removing stale temporary cache file '%s'", tempFile)
+
+			if err = os.Remove(tempFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("removing '%s': %w", tempFile, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cache *Cache) removeLegacyEntries() error {
+	dirEntries, err := os.ReadDir(cache.cacheDir)
+	if err != nil {
+		return fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	for _, dirEntry := range dirEntries {
+		legacyFile := !dirEntry.IsDir() && legacyEntryRegexp.MatchString(dirEntry.Name())
+		legacyImages := dirEntry.IsDir() && dirEntry.Name() == legacyImagesDir
+
+		if !legacyFile && !legacyImages {
+			continue
+		}
+
+		zap.S().Infof("Removing legacy cache entry '%s'", dirEntry.Name())
+
+		if err = os.RemoveAll(filepath.Join(cache.cacheDir, dirEntry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing '%s': %w", dirEntry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func sortedEntries(idx *index) []Entry {
+	entries := make([]Entry, 0, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		entries = append(entries, entry)
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.Identifier, b.Identifier)
+	})
+
+	return entries
+}
+
+func totalSize(idx *index) int64 {
+	var size int64
+
+	counted := map[string]bool{}
+	for _, entry := range idx.Entries {
+		if counted[entry.Digest] {
+			continue
+		}
+
+		counted[entry.Digest] = true
+		size += entry.Size
+	}
+
+	return size
+}
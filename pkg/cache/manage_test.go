@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// putEntries stores the given contents and marks each entry as last used the given number of hours ago.
+func putEntries(t *testing.T, cache *Cache, contents map[string]string, hoursAgo map[string]int) {
+	for identifier, data := range contents {
+		require.NoError(t, cache.Put(identifier, defaultSource, strings.NewReader(data)))
+	}
+
+	idx, err := cache.readIndex()
+	require.NoError(t, err)
+
+	for identifier, hours := range hoursAgo {
+		entry := idx.Entries[identifier]
+		entry.LastUsed = time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
+		idx.Entries[identifier] = entry
+	}
+
+	require.NoError(t, cache.writeIndex(idx))
+}
+
+func identifiers(entries []Entry) []string {
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.Identifier)
+	}
+
+	return ids
+}
+
+func TestCache_ListAndSize(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	putEntries(t, cache, map[string]string{
+		"v2/artefact": "12345",
+		"v1/artefact": "123",
+		"v1/copy":     "123",
+	}, nil)
+
+	entries, err := cache.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1/artefact", "v1/copy", "v2/artefact"}, identifiers(entries))
+
+	// Shared contents are only counted once
+	size, err := cache.Size()
+	require.NoError(t, err)
+	assert.EqualValues(t, 8, size)
+}
+
+func TestCache_GetUpdatesLastUsed(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	putEntries(t, cache, map[string]string{defaultFileIdentifier: defaultFileContents}, map[string]int{defaultFileIdentifier: 48})
+
+	_, err := cache.Get(defaultFileIdentifier)
+	require.NoError(t, err)
+
+	entries, err := cache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.WithinDuration(t, time.Now(), entries[0].LastUsed, time.Minute)
+}
+
+func TestCache_Contains(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	putEntries(t, cache, map[string]string{"v1/artefact": "123", "v1/truncated": "12345"}, map[string]int{"v1/artefact": 48})
+
+	path, err := cache.Get("v1/truncated")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("1"), 0o600))
+
+	found, err := cache.Contains("v1/artefact")
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = cache.Contains("v1/truncated")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = cache.Contains("v1/missing")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// Looking up entries does not count as using them
+	entries, err := cache.List()
+	require.NoError(t, err)
+	assert.Less(t, entries[0].LastUsed, time.Now().Add(-time.Hour))
+}
+
+func TestCache_Remove(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	putEntries(t, cache, map[string]string{"v1/artefact": "123", "v1/copy": "123"}, nil)
+
+	path, err := cache.Get("v1/artefact")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Remove("v1/artefact"))
+
+	// The contents are still referenced by the other entry
+	assert.FileExists(t, path)
+
+	require.NoError(t, cache.Remove("v1/copy"))
+	assert.NoFileExists(t, path)
+
+	assert.ErrorIs(t, cache.Remove("v1/copy"), fs.ErrNotExist)
+}
+
+func TestCache_MaxSize(t *testing.T) {
+	require.NoError(t, os.MkdirAll(defaultCacheDir, os.ModePerm))
+	defer func() {
+		assert.NoError(t, os.RemoveAll(defaultCacheDir))
+	}()
+
+	cache, err := New(defaultCacheDir, 10)
+	require.NoError(t, err)
+
+	putEntries(t, cache, map[string]string{
+		"old":    "1111",
+		"recent": "2222",
+	}, map[string]int{"old": 2, "recent": 1})
+
+	require.NoError(t, cache.Put("new", defaultSource, strings.NewReader("333333")))
+
+	entries, err := cache.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new", "recent"}, identifiers(entries))
+
+	// Entries exceeding the limit on their own are retained
+	require.NoError(t, cache.Put("huge", defaultSource, strings.NewReader("44444444444")))
+
+	entries, err = cache.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"huge"}, identifiers(entries))
+}
+
+func TestCache_Prune(t *testing.T) {
+	tests := map[string]struct {
+		olderThan       time.Duration
+		maxSize         int64
+		expectedEvicted []string
+		expectedKept    []string
+	}{
+		"Older than": {
+			olderThan:       24 * time.Hour,
+			expectedEvicted: []string{"unused", "very-old"},
+			expectedKept:    []string{"recent"},
+		},
+		"Max size": {
+			maxSize:         4,
+			expectedEvicted: []string{"very-old", "unused"},
+			expectedKept:    []string{"recent"},
+		},
+		"Both criteria": {
+			olderThan:       100 * time.Hour,
+			maxSize:         4,
+			expectedEvicted: []string{"very-old", "unused"},
+			expectedKept:    []string{"recent"},
+		},
+		"No criteria": {
+			expectedKept: []string{"recent", "unused", "very-old"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			cache, teardown := setup(t, defaultCacheDir)
+			defer teardown()
+
+			putEntries(t, cache, map[string]string{
+				"recent":   "1111",
+				"unused":   "2222",
+				"very-old": "3333",
+			}, map[string]int{"recent": 1, "unused": 48, "very-old": 240})
+
+			legacyFile := filepath.Join(defaultCacheDir, "12345678901234567890")
+			require.NoError(t, os.WriteFile(legacyFile, []byte("legacy"), 0o600))
+
+			legacyImage := filepath.Join(defaultCacheDir, "images", "hello-world:latest-registry.tar.zst")
+			require.NoError(t, os.MkdirAll(filepath.Dir(legacyImage), os.ModePerm))
+			require.NoError(t, os.WriteFile(legacyImage, []byte("legacy"), 0o600))
+
+			// Test
+			evicted, err := cache.Prune(test.olderThan, test.maxSize)
+			require.NoError(t, err)
+
+			// Verify
+			assert.Equal(t, test.expectedEvicted, identifiers(evicted))
+
+			entries, err := cache.List()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedKept, identifiers(entries))
+
+			assert.NoFileExists(t, legacyFile)
+			assert.NoDirExists(t, filepath.Dir(legacyImage))
+			assert.FileExists(t, filepath.Join(defaultCacheDir, "index.json"))
+		})
+	}
+}
+
+func TestCache_Verify(t *testing.T) {
+	cache, teardown := setup(t, defaultCacheDir)
+	defer teardown()
+
+	putEntries(t, cache, map[string]string{"intact": "1111", "corrupt": "2222"}, nil)
+
+	path, err := cache.Get("corrupt")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("2223"), 0o600))
+
+	corrupt, err := cache.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"corrupt"}, identifiers(corrupt))
+
+	entries, err := cache.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"intact"}, identifiers(entries))
+	assert.NoFileExists(t, path)
+}
@@ -15,7 +15,6 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/version"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
-	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -72,19 +71,37 @@ func Run(_ *cli.Context) error {
 		zap.S().Fatalf("Failed to create combustion directories: %s", err)
 	}
 
-	artifactSources, err := parseArtifactSources()
+	artifactSources, err := parseArtifactSources(args.ConfigDir, args.ArtifactSources)
 	if err != nil {
 		log.Auditf("Loading artifact sources metadata failed. %s", checkBuildLogMessage)
 		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
 	}
 
+	if err = configureTLS(args.CABundle, artifactSources, buildDir); err != nil {
+		log.Auditf("Configuring the TLS settings failed. %s", checkBuildLogMessage)
+		zap.S().Fatalf("Configuring TLS failed: %v", err)
+	}
+
 	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, cacheDir, imageDefinition, artifactSources)
 
-	if cmdErr = validateImageDefinition(ctx); cmdErr != nil {
+	// The size has already been validated along with the rest of the flags
+	ctx.CacheMaxSize, _ = cmd.ParseSize(args.CacheMaxSize)
+	ctx.DownloadParallelism = args.DownloadParallelism
+
+	if cmdErr = validateImageDefinition(ctx, false); cmdErr != nil {
 		cmd.LogError(cmdErr, checkBuildLogMessage)
 		os.Exit(1)
 	}
 
+	if err = resolveKubernetesVersion(ctx, args.KubernetesReleaseIndex, args.Offline); err != nil {
+		log.Auditf("Resolving the Kubernetes version failed. %s", checkBuildLogMessage)
+		zap.S().Fatalf("Resolving Kubernetes version failed: %v", err)
+	}
+
+	if args.Offline {
+		enableOfflineMode(ctx)
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			log.Auditf("Build failed unexpectedly. %s", checkBuildLogMessage)
@@ -119,6 +136,41 @@ func imageConfigDirExists(configDir string) *cmd.Error {
 }
 
 func parseDefinitionFile(configDir, definitionFile string) (*image.Definition, *cmd.Error) {
+	configData, cmdErr := composeDefinitionFile(configDir, definitionFile)
+	if cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	return parseDefinitionData(configDir, definitionFile, configData)
+}
+
+func parseDefinitionData(configDir, definitionFile string, configData []byte) (*image.Definition, *cmd.Error) {
+	definitionFilePath := filepath.Join(configDir, definitionFile)
+
+	imageDefinition, err := image.ParseDefinition(configData, configDir)
+	if err != nil {
+		if errors.Is(err, image.ErrorInvalidSchemaVersion) {
+			m := "Invalid schema version specified. This version of Edge Image Builder supports the following schema versions: %s"
+			msg := fmt.Sprintf(m, strings.Join(version.SupportedSchemaVersions, ", "))
+			return nil, &cmd.Error{
+				UserMessage: msg,
+				LogMessage:  msg,
+			}
+		}
+
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The image definition file '%s' could not be parsed.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Parsing definition file failed: %v", err),
+		}
+	}
+
+	log.RegisterSecrets(imageDefinition.Secrets()...)
+
+	return imageDefinition, nil
+}
+
+// composeDefinitionFile reads the definition file and merges it over the definitions it extends, if any.
+func composeDefinitionFile(configDir, definitionFile string) ([]byte, *cmd.Error) {
 	definitionFilePath := filepath.Join(configDir, definitionFile)
 
 	configData, err := os.ReadFile(definitionFilePath)
@@ -135,27 +187,20 @@ func parseDefinitionFile(configDir, definitionFile string) (*image.Definition, *
 		}
 	}
 
-	imageDefinition, err := image.ParseDefinition(configData)
+	composedData, err := image.ComposeDefinition(configData, configDir)
 	if err != nil {
-		if errors.Is(err, image.ErrorInvalidSchemaVersion) {
-			m := "Invalid schema version specified. This version of Edge Image Builder supports the following schema versions: %s"
-			msg := fmt.Sprintf(m, strings.Join(version.SupportedSchemaVersions, ", "))
-			return nil, &cmd.Error{
-				UserMessage: msg,
-				LogMessage:  msg,
-			}
-		}
-
 		return nil, &cmd.Error{
-			UserMessage: fmt.Sprintf("The image definition file '%s' could not be parsed.", definitionFilePath),
-			LogMessage:  fmt.Sprintf("Parsing definition file failed: %v", err),
+			UserMessage: fmt.Sprintf("The image definition file '%s' could not be composed from the definitions it extends.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Composing definition file failed: %v", err),
 		}
 	}
 
-	return imageDefinition, nil
+	return composedData, nil
 }
 
-func parseArtifactSources() (*image.ArtifactSources, error) {
+// parseArtifactSources reads the built-in artifact sources and merges the overlay over them. The overlay is read
+// from the given path if specified, otherwise from the image configuration directory if present there.
+func parseArtifactSources(configDir, overlayPath string) (*image.ArtifactSources, error) {
 	const artifactsConfigFile = "artifacts.yaml"
 
 	b, err := os.ReadFile(artifactsConfigFile)
@@ -167,12 +212,38 @@ func parseArtifactSources() (*image.ArtifactSources, error) {
 		return nil, fmt.Errorf("reading artifact sources file: %w", err)
 	}
 
-	var sources image.ArtifactSources
-	if err = yaml.Unmarshal(b, &sources); err != nil {
-		return nil, fmt.Errorf("decoding artifacts sources: %w", err)
+	var overlays [][]byte
+
+	if overlayPath == "" {
+		overlayPath = filepath.Join(configDir, artifactsConfigFile)
+		if _, err = os.Stat(overlayPath); errors.Is(err, fs.ErrNotExist) {
+			overlayPath = ""
+		}
+	}
+
+	if overlayPath != "" {
+		overlay, err := os.ReadFile(overlayPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading artifact sources overlay: %w", err)
+		}
+
+		overlays = append(overlays, overlay)
+	}
+
+	sources, err := image.ParseArtifactSources(b, overlays...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = image.ValidateArtifactSources(sources); err != nil {
+		return nil, fmt.Errorf("validating artifact sources: %w", err)
+	}
+
+	if overlayPath != "" {
+		log.AuditInfof("Artifact sources are overridden by '%s'.", overlayPath)
 	}
 
-	return &sources, nil
+	return sources, nil
 }
 
 // Assembles the image build context with user-provided values and implementation defaults.
@@ -0,0 +1,352 @@
+package build
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+func CacheList(_ *cli.Context) error {
+	c := openCache()
+
+	entries, err := c.List()
+	if err != nil {
+		exitCacheError("The cache entries could not be listed.", err)
+	}
+
+	size, err := c.Size()
+	if err != nil {
+		exitCacheError("The cache size could not be calculated.", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IDENTIFIER\tSIZE\tLAST USED")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Identifier, formatSize(entry.Size), entry.LastUsed.Local().Format(time.DateTime))
+	}
+
+	if err = w.Flush(); err != nil {
+		exitCacheError("The cache entries could not be listed.", err)
+	}
+
+	fmt.Printf("\n%d entries, %s in total\n", len(entries), formatSize(size))
+
+	return nil
+}
+
+func CachePrune(c *cli.Context) error {
+	// The values have already been validated along with the rest of the flags
+	olderThan, _ := cmd.ParseAge(c.String("older-than"))
+	maxSize, _ := cmd.ParseSize(c.String("max-size"))
+
+	evicted, err := openCache().Prune(olderThan, maxSize)
+	if err != nil {
+		exitCacheError("The cache could not be pruned.", err)
+	}
+
+	var freed int64
+	for _, entry := range evicted {
+		fmt.Printf("Removed %s (%s)\n", entry.Identifier, formatSize(entry.Size))
+		freed += entry.Size
+	}
+
+	fmt.Printf("Removed %d entries, freeing up to %s\n", len(evicted), formatSize(freed))
+
+	return nil
+}
+
+func CacheRemove(c *cli.Context) error {
+	identifier := c.Args().First()
+	if identifier == "" {
+		logRenderError(&cmd.Error{UserMessage: "The identifier of the cache entry to remove must be specified."})
+		os.Exit(1)
+	}
+
+	if err := openCache().Remove(identifier); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			logRenderError(&cmd.Error{UserMessage: fmt.Sprintf("The cache entry '%s' could not be found.", identifier)})
+			os.Exit(1)
+		}
+
+		exitCacheError(fmt.Sprintf("The cache entry '%s' could not be removed.", identifier), err)
+	}
+
+	fmt.Printf("Removed %s\n", identifier)
+
+	return nil
+}
+
+func CacheVerify(_ *cli.Context) error {
+	corrupt, err := openCache().Verify()
+	if err != nil {
+		exitCacheError("The cache could not be verified.", err)
+	}
+
+	if len(corrupt) == 0 {
+		fmt.Println("All cache entries are intact.")
+		return nil
+	}
+
+	for _, entry := range corrupt {
+		fmt.Printf("Removed corrupt entry %s\n", entry.Identifier)
+	}
+
+	logRenderError(&cmd.Error{
+		UserMessage: fmt.Sprintf("Found %d corrupt cache entries; they will be downloaded again by the next build.", len(corrupt)),
+	})
+	os.Exit(1)
+
+	return nil
+}
+
+const (
+	exportLogFilename     = "eib-cache-export.log"
+	checkExportLogMessage = "Please check the eib-cache-export.log file under the build directory for more information."
+)
+
+func CacheExport(c *cli.Context) error {
+	args := &cmd.CommonArgs
+
+	rootBuildDir := args.RootBuildDir
+	if rootBuildDir == "" {
+		const defaultBuildDir = "_build"
+
+		rootBuildDir = filepath.Join(args.ConfigDir, defaultBuildDir)
+		if err := os.MkdirAll(rootBuildDir, os.ModePerm); err != nil {
+			log.Auditf("The root build directory could not be set up under the configuration directory '%s'.", args.ConfigDir)
+			return err
+		}
+	}
+
+	buildDir, err := eib.SetupBuildDirectory(rootBuildDir)
+	if err != nil {
+		log.Audit("The build directory could not be set up.")
+		return err
+	}
+
+	cacheDir, err := eib.SetupCacheDirectory(rootBuildDir, args.CacheDir)
+	if err != nil {
+		log.Audit("The cache directory could not be set up.")
+		return err
+	}
+
+	// This needs to occur as early as possible so that the subsequent calls can use the log
+	log.ConfigureGlobalLogger(filepath.Join(buildDir, exportLogFilename))
+
+	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
+		cmd.LogError(cmdErr, checkExportLogMessage)
+		os.Exit(1)
+	}
+
+	imageDefinition, cmdErr := parseDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkExportLogMessage)
+		os.Exit(1)
+	}
+
+	combustionDir, artefactsDir, err := eib.SetupCombustionDirectory(buildDir)
+	if err != nil {
+		log.Auditf("Setting up the combustion directory failed. %s", checkExportLogMessage)
+		zap.S().Fatalf("Failed to create combustion directories: %s", err)
+	}
+
+	artifactSources, err := parseArtifactSources(args.ConfigDir, args.ArtifactSources)
+	if err != nil {
+		log.Auditf("Loading artifact sources metadata failed. %s", checkExportLogMessage)
+		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
+	}
+
+	if err = configureTLS(args.CABundle, artifactSources, buildDir); err != nil {
+		log.Auditf("Configuring the TLS settings failed. %s", checkExportLogMessage)
+		zap.S().Fatalf("Configuring TLS failed: %v", err)
+	}
+
+	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, cacheDir, imageDefinition, artifactSources)
+
+	if cmdErr = validateImageDefinition(ctx, false); cmdErr != nil {
+		cmd.LogError(cmdErr, checkExportLogMessage)
+		os.Exit(1)
+	}
+
+	if err = resolveKubernetesVersion(ctx, args.KubernetesReleaseIndex, false); err != nil {
+		log.Auditf("Resolving the Kubernetes version failed. %s", checkExportLogMessage)
+		zap.S().Fatalf("Resolving Kubernetes version failed: %v", err)
+	}
+
+	artefactCache, err := cache.New(cacheDir, 0)
+	if err != nil {
+		log.Auditf("The cache directory '%s' could not be opened. %s", cacheDir, checkExportLogMessage)
+		zap.S().Fatalf("Opening cache failed: %v", err)
+	}
+
+	log.Audit("Retrieving the artifacts required by the image definition...")
+
+	identifiers, err := eib.Prefetch(ctx, artefactCache)
+	if err != nil {
+		log.Auditf("Retrieving the artifacts failed. %s", checkExportLogMessage)
+		zap.S().Fatalf("An error occurred retrieving the artifacts: %s", err)
+	}
+
+	definitionDigest, err := fileDigest(filepath.Join(args.ConfigDir, args.DefinitionFile))
+	if err != nil {
+		log.Auditf("Reading the image definition failed. %s", checkExportLogMessage)
+		zap.S().Fatalf("Calculating the digest of the definition failed: %v", err)
+	}
+
+	manifest := &cache.BundleManifest{
+		EIBVersion:       version.GetEibVersion(),
+		Definition:       args.DefinitionFile,
+		DefinitionDigest: definitionDigest,
+	}
+
+	outputPath := c.String("output")
+	if !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(args.ConfigDir, outputPath)
+	}
+
+	if err = writeBundle(outputPath, func(w io.Writer) error {
+		return artefactCache.Export(w, identifiers, manifest)
+	}); err != nil {
+		log.Auditf("Writing the cache bundle failed. %s", checkExportLogMessage)
+		zap.S().Fatalf("Exporting cache bundle failed: %v", err)
+	}
+
+	for _, entry := range manifest.Entries {
+		log.Auditf("  %s (%s)", entry.Identifier, formatSize(entry.Size))
+	}
+
+	log.Auditf("Cache bundle with %d artifacts (%s) written to: %s", len(manifest.Entries), formatSize(bundleSize(manifest)), outputPath)
+
+	return nil
+}
+
+// writeBundle writes the bundle to a temporary file which is only moved to the given path once complete.
+func writeBundle(path string, export func(w io.Writer) error) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating bundle file: %w", err)
+	}
+
+	tempPath := file.Name()
+
+	if err = export(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tempPath)
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("closing bundle file: %w", err)
+	}
+
+	if err = os.Chmod(tempPath, fileio.NonExecutablePerms); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("adjusting bundle file permissions: %w", err)
+	}
+
+	if err = os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("moving bundle file into place: %w", err)
+	}
+
+	return nil
+}
+
+func fileDigest(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
+
+// bundleSize returns the total size of the files in the bundle. Files shared by multiple entries are only counted once.
+func bundleSize(manifest *cache.BundleManifest) int64 {
+	var size int64
+
+	counted := map[string]bool{}
+	for _, entry := range manifest.Entries {
+		if !counted[entry.Digest] {
+			counted[entry.Digest] = true
+			size += entry.Size
+		}
+	}
+
+	return size
+}
+
+func CacheImport(c *cli.Context) error {
+	bundlePath := c.Args().First()
+	if bundlePath == "" {
+		logRenderError(&cmd.Error{UserMessage: "The path of the bundle to import must be specified."})
+		os.Exit(1)
+	}
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		logRenderError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The bundle '%s' could not be opened.", bundlePath),
+			LogMessage:  fmt.Sprintf("Opening bundle failed: %v", err),
+		})
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	manifest, err := openCache().Import(file)
+	if err != nil {
+		exitCacheError(fmt.Sprintf("The bundle '%s' could not be imported. No artifacts have been loaded into the cache.", bundlePath), err)
+	}
+
+	for _, entry := range manifest.Entries {
+		fmt.Printf("Imported %s (%s)\n", entry.Identifier, formatSize(entry.Size))
+	}
+
+	fmt.Printf("Imported %d entries (%s) exported for the '%s' definition on %s\n",
+		len(manifest.Entries), formatSize(bundleSize(manifest)), manifest.Definition, manifest.CreatedAt.Local().Format(time.DateTime))
+
+	return nil
+}
+
+func openCache() *cache.Cache {
+	cacheDir := cmd.CommonArgs.CacheDir
+
+	if _, err := os.Stat(cacheDir); err != nil {
+		logRenderError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The cache directory '%s' could not be found. Please make sure that it is mounted.", cacheDir),
+			LogMessage:  fmt.Sprintf("Reading cache directory failed: %v", err),
+		})
+		os.Exit(1)
+	}
+
+	c, err := cache.New(cacheDir, 0)
+	if err != nil {
+		exitCacheError(fmt.Sprintf("The cache directory '%s' could not be opened.", cacheDir), err)
+	}
+
+	return c
+}
+
+func exitCacheError(userMessage string, err error) {
+	logRenderError(&cmd.Error{
+		UserMessage: userMessage,
+		LogMessage:  fmt.Sprintf("Managing cache failed: %v", err),
+	})
+	os.Exit(1)
+}
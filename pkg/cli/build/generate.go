@@ -67,20 +67,34 @@ func Generate(c *cli.Context) error {
 		zap.S().Fatalf("Failed to create combustion directories: %s", err)
 	}
 
-	artifactSources, err := parseArtifactSources()
+	artifactSources, err := parseArtifactSources(args.ConfigDir, args.ArtifactSources)
 	if err != nil {
 		log.Auditf("Loading artifact sources metadata failed. %s", checkBuildLogMessage)
 		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
 	}
 
+	if err = configureTLS(args.CABundle, artifactSources, buildDir); err != nil {
+		log.Auditf("Configuring the TLS settings failed. %s", checkBuildLogMessage)
+		zap.S().Fatalf("Configuring TLS failed: %v", err)
+	}
+
 	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, cacheDir, configDriveDefinition, artifactSources)
 	ctx.IsConfigDrive = true
 
-	if cmdErr = validateImageDefinition(ctx); cmdErr != nil {
+	// The size has already been validated along with the rest of the flags
+	ctx.CacheMaxSize, _ = cmd.ParseSize(args.CacheMaxSize)
+	ctx.DownloadParallelism = args.DownloadParallelism
+
+	if cmdErr = validateImageDefinition(ctx, false); cmdErr != nil {
 		cmd.LogError(cmdErr, checkBuildLogMessage)
 		os.Exit(1)
 	}
 
+	if err = resolveKubernetesVersion(ctx, args.KubernetesReleaseIndex, args.Offline); err != nil {
+		log.Auditf("Resolving the Kubernetes version failed. %s", checkBuildLogMessage)
+		zap.S().Fatalf("Resolving Kubernetes version failed: %v", err)
+	}
+
 	// Set the necessary flags for combustion
 	ctx.ImageDefinition.Image.ImageType = outputType
 	ctx.ImageDefinition.Image.OutputImageName = output
@@ -91,6 +105,10 @@ func Generate(c *cli.Context) error {
 		ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
 	}
 
+	if args.Offline {
+		enableOfflineMode(ctx)
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			log.Auditf("Build failed unexpectedly. %s", checkBuildLogMessage)
@@ -0,0 +1,161 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	imagesLogFilename     = "eib-images.log"
+	checkImagesLogMessage = "Please check the eib-images.log file under the build directory for more information."
+)
+
+var imagesOutputFormats = []string{outputText, outputJSON}
+
+func Images(c *cli.Context) error {
+	args := &cmd.CommonArgs
+
+	output := c.String("output")
+	if !slices.Contains(imagesOutputFormats, output) {
+		log.Auditf("Invalid output format '%s'. Options: %s", output, strings.Join(imagesOutputFormats, ", "))
+		return fmt.Errorf("invalid output format: %s", output)
+	}
+
+	rootBuildDir := args.RootBuildDir
+	if rootBuildDir == "" {
+		const defaultBuildDir = "_build"
+
+		rootBuildDir = filepath.Join(args.ConfigDir, defaultBuildDir)
+		if err := os.MkdirAll(rootBuildDir, os.ModePerm); err != nil {
+			log.Auditf("The root build directory could not be set up under the configuration directory '%s'.", args.ConfigDir)
+			return err
+		}
+	}
+
+	buildDir, err := eib.SetupBuildDirectory(rootBuildDir)
+	if err != nil {
+		log.Audit("The build directory could not be set up.")
+		return err
+	}
+
+	var cacheDir string
+	if args.Cache {
+		cacheDir, err = eib.SetupCacheDirectory(rootBuildDir, args.CacheDir)
+		if err != nil {
+			log.Audit("The cache directory could not be set up.")
+			return err
+		}
+	}
+
+	// This needs to occur as early as possible so that the subsequent calls can use the log
+	log.ConfigureGlobalLogger(filepath.Join(buildDir, imagesLogFilename))
+
+	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
+		cmd.LogError(cmdErr, checkImagesLogMessage)
+		os.Exit(1)
+	}
+
+	imageDefinition, cmdErr := parseDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkImagesLogMessage)
+		os.Exit(1)
+	}
+
+	combustionDir, artefactsDir, err := eib.SetupCombustionDirectory(buildDir)
+	if err != nil {
+		log.Auditf("Setting up the combustion directory failed. %s", checkImagesLogMessage)
+		zap.S().Fatalf("Failed to create combustion directories: %s", err)
+	}
+
+	artifactSources, err := parseArtifactSources(args.ConfigDir, args.ArtifactSources)
+	if err != nil {
+		log.Auditf("Loading artifact sources metadata failed. %s", checkImagesLogMessage)
+		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
+	}
+
+	if err = configureTLS(args.CABundle, artifactSources, buildDir); err != nil {
+		log.Auditf("Configuring the TLS settings failed. %s", checkImagesLogMessage)
+		zap.S().Fatalf("Configuring TLS failed: %v", err)
+	}
+
+	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, cacheDir, imageDefinition, artifactSources)
+
+	if cmdErr = validateImageDefinition(ctx, false); cmdErr != nil {
+		cmd.LogError(cmdErr, checkImagesLogMessage)
+		os.Exit(1)
+	}
+
+	if err = resolveKubernetesVersion(ctx, args.KubernetesReleaseIndex, false); err != nil {
+		log.Auditf("Resolving the Kubernetes version failed. %s", checkImagesLogMessage)
+		zap.S().Fatalf("Resolving Kubernetes version failed: %v", err)
+	}
+
+	// JSON output is written to stdout as a whole, so progress is only recorded in the log file
+	if output == outputText {
+		log.Audit("Extracting the container images from the image definition, manifests and Helm charts...")
+	} else {
+		zap.S().Info("Extracting the container images from the image definition, manifests and Helm charts...")
+	}
+
+	references, err := eib.ImageReferences(ctx)
+	if err != nil {
+		log.Auditf("Extracting the container images failed. %s", checkImagesLogMessage)
+		zap.S().Fatalf("An error occurred extracting the container images: %s", err)
+	}
+
+	if output == outputJSON {
+		if err = writeImageReferences(references); err != nil {
+			log.Auditf("Writing the container images failed. %s", checkImagesLogMessage)
+			zap.S().Fatalf("Writing the container images failed: %v", err)
+		}
+
+		return nil
+	}
+
+	printImageReferences(references)
+
+	return nil
+}
+
+func printImageReferences(references []registry.ImageReference) {
+	if len(references) == 0 {
+		log.Audit("\nNo container images would be embedded in the artifact registry.")
+		return
+	}
+
+	for _, reference := range references {
+		log.Auditf("\n%s", reference.Name)
+		for _, reason := range reference.Reasons {
+			log.Auditf("  - %s", reason)
+		}
+	}
+
+	log.Auditf("\n%d container image(s) would be embedded in the artifact registry.", len(references))
+}
+
+func writeImageReferences(references []registry.ImageReference) error {
+	// An empty list is written rather than null
+	if references == nil {
+		references = []registry.ImageReference{}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(references); err != nil {
+		return fmt.Errorf("encoding container images: %w", err)
+	}
+
+	return nil
+}
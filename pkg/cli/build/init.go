@@ -0,0 +1,52 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/scaffold"
+	"github.com/urfave/cli/v2"
+)
+
+func Init(c *cli.Context) error {
+	args := &cmd.CommonArgs
+	definitionFile := c.String("definition-file")
+	templateName := c.String("template")
+	arch := image.Arch(c.String("arch"))
+
+	baseImage := c.String("base-image")
+	if baseImage == "" {
+		baseImage = scaffold.DefaultBaseImage(arch)
+	}
+
+	created, err := scaffold.Scaffold(args.ConfigDir, definitionFile, templateName, arch, baseImage)
+	if err != nil {
+		cmdErr := &cmd.Error{
+			UserMessage: fmt.Sprintf("The image configuration directory '%s' could not be initialized.", args.ConfigDir),
+			LogMessage:  fmt.Sprintf("Initializing image configuration directory failed: %v", err),
+		}
+
+		if errors.Is(err, fs.ErrExist) {
+			cmdErr.UserMessage = fmt.Sprintf("The image configuration directory '%s' is already initialized: %v", args.ConfigDir, err)
+			cmdErr.LogMessage = ""
+		}
+
+		logRenderError(cmdErr)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Initialized the image configuration directory '%s' from the '%s' template:\n", args.ConfigDir, templateName)
+	for _, path := range created {
+		fmt.Printf("  %s\n", path)
+	}
+
+	fmt.Printf("\nCopy the '%s' base image to '%s' and review '%s' before building the image.\n",
+		baseImage, filepath.Join(args.ConfigDir, "base-images"), filepath.Join(args.ConfigDir, definitionFile))
+
+	return nil
+}
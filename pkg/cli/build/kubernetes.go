@@ -0,0 +1,44 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+)
+
+// resolveKubernetesVersion replaces a release channel in the definition, such as `rke2:stable`, with the latest
+// version of the channel. The installed version is recorded in the context so that the build report pins it.
+func resolveKubernetesVersion(ctx *image.Context, indexFile string, offline bool) error {
+	version := ctx.ImageDefinition.Kubernetes.Version
+	if version == "" {
+		return nil
+	}
+
+	if _, _, ok := kubernetes.ParseVersionChannel(version); !ok {
+		ctx.KubernetesRelease = &image.KubernetesRelease{Version: version}
+		return nil
+	}
+
+	if offline && indexFile == "" {
+		log.Auditf("Resolving the Kubernetes release channel '%s' offline requires the --kubernetes-release-index flag.", version)
+		return fmt.Errorf("no release index available offline for '%s'", version)
+	}
+
+	resolved, index, err := kubernetes.ResolveVersionChannel(version, indexFile, ctx.ArtifactSources, ctx.BuildDir)
+	if err != nil {
+		return err
+	}
+
+	ctx.ImageDefinition.Kubernetes.Version = resolved
+	ctx.KubernetesRelease = &image.KubernetesRelease{
+		Version:      resolved,
+		Channel:      version,
+		ReleaseIndex: index,
+	}
+
+	log.AuditInfof("Kubernetes release channel '%s' resolved to version '%s'.", version, resolved)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"github.com/urfave/cli/v2"
+)
+
+func Migrate(c *cli.Context) error {
+	args := &cmd.CommonArgs
+	targetVersion := c.String("to")
+	inPlace := c.Bool("in-place")
+
+	if err := imageConfigDirExists(args.ConfigDir); err != nil {
+		logRenderError(err)
+		os.Exit(1)
+	}
+
+	definitionFilePath := filepath.Join(args.ConfigDir, args.DefinitionFile)
+
+	data, err := os.ReadFile(definitionFilePath)
+	if err != nil {
+		logRenderError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The specified definition file '%s' could not be read.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Reading definition file failed: %v", err),
+		})
+		os.Exit(1)
+	}
+
+	migrated, notes, err := image.MigrateDefinition(data, targetVersion)
+	if err != nil {
+		logRenderError(migrationError(definitionFilePath, err))
+		os.Exit(1)
+	}
+
+	// Migration notes are written to stderr to keep the printed definition usable as is
+	for _, note := range notes {
+		fmt.Fprintln(os.Stderr, note.String())
+	}
+
+	if !inPlace {
+		fmt.Print(string(migrated))
+		return nil
+	}
+
+	if err = os.WriteFile(definitionFilePath, migrated, fileio.NonExecutablePerms); err != nil {
+		logRenderError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The migrated definition could not be written to '%s'.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Writing definition file failed: %v", err),
+		})
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "The definition file '%s' has been migrated to API version %s.\n", definitionFilePath, targetVersion)
+
+	return nil
+}
+
+func migrationError(definitionFilePath string, err error) *cmd.Error {
+	if errors.Is(err, image.ErrorInvalidSchemaVersion) {
+		m := "Invalid schema version specified. This version of Edge Image Builder supports the following schema versions: %s"
+		msg := fmt.Sprintf(m, strings.Join(version.SupportedSchemaVersions, ", "))
+		return &cmd.Error{
+			UserMessage: msg,
+		}
+	}
+
+	return &cmd.Error{
+		UserMessage: fmt.Sprintf("The image definition file '%s' could not be migrated.", definitionFilePath),
+		LogMessage:  fmt.Sprintf("Migrating definition file failed: %v", err),
+	}
+}
@@ -0,0 +1,37 @@
+package build
+
+import (
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
+)
+
+// enableOfflineMode disables network access and verifies that every artifact required by the build
+// is available in the cache, listing the missing ones and exiting before any work starts otherwise.
+func enableOfflineMode(ctx *image.Context) {
+	http.SetOffline(true)
+	log.AuditInfo("Offline mode is enabled. Only cached artifacts and local content will be used.")
+
+	missing, err := eib.MissingArtefacts(ctx)
+	if err != nil {
+		log.Auditf("Checking the availability of the required artifacts failed. %s", checkBuildLogMessage)
+		zap.S().Fatalf("Looking up missing artefacts failed: %v", err)
+	}
+
+	if len(missing) == 0 {
+		return
+	}
+
+	log.Auditf("The build requires %d artifact(s) which are not available offline:", len(missing))
+	for _, artefact := range missing {
+		log.Auditf("  [%s] %s: %s", artefact.Kind, artefact.Source, artefact.Reason)
+	}
+	log.Audit("Please import a cache bundle created with 'eib cache export' on a connected host or build without --offline.")
+
+	zap.S().Errorf("Offline build aborted due to %d missing artefact(s)", len(missing))
+	os.Exit(1)
+}
@@ -0,0 +1,157 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/plan"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	planLogFilename     = "eib-plan.log"
+	checkPlanLogMessage = "Please check the eib-plan.log file under the build directory for more information."
+)
+
+func Plan(_ *cli.Context) error {
+	args := &cmd.CommonArgs
+
+	rootBuildDir := args.RootBuildDir
+	if rootBuildDir == "" {
+		const defaultBuildDir = "_build"
+
+		rootBuildDir = filepath.Join(args.ConfigDir, defaultBuildDir)
+		if err := os.MkdirAll(rootBuildDir, os.ModePerm); err != nil {
+			log.Auditf("The root build directory could not be set up under the configuration directory '%s'.", args.ConfigDir)
+			return err
+		}
+	}
+
+	buildDir, err := eib.SetupBuildDirectory(rootBuildDir)
+	if err != nil {
+		log.Audit("The build directory could not be set up.")
+		return err
+	}
+
+	// This needs to occur as early as possible so that the subsequent calls can use the log
+	log.ConfigureGlobalLogger(filepath.Join(buildDir, planLogFilename))
+
+	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
+		cmd.LogError(cmdErr, checkPlanLogMessage)
+		os.Exit(1)
+	}
+
+	imageDefinition, cmdErr := parseDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkPlanLogMessage)
+		os.Exit(1)
+	}
+
+	combustionDir, artefactsDir, err := eib.SetupCombustionDirectory(buildDir)
+	if err != nil {
+		log.Auditf("Setting up the combustion directory failed. %s", checkPlanLogMessage)
+		zap.S().Fatalf("Failed to create combustion directories: %s", err)
+	}
+
+	artifactSources, err := parseArtifactSources(args.ConfigDir, args.ArtifactSources)
+	if err != nil {
+		log.Auditf("Loading artifact sources metadata failed. %s", checkPlanLogMessage)
+		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
+	}
+
+	// Caching is disabled so that no artefacts are copied while planning
+	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, "", imageDefinition, artifactSources)
+
+	if cmdErr = validateImageDefinition(ctx, false); cmdErr != nil {
+		cmd.LogError(cmdErr, checkPlanLogMessage)
+		os.Exit(1)
+	}
+
+	if err = resolveKubernetesVersion(ctx, args.KubernetesReleaseIndex, false); err != nil {
+		log.Auditf("Resolving the Kubernetes version failed. %s", checkPlanLogMessage)
+		zap.S().Fatalf("Resolving Kubernetes version failed: %v", err)
+	}
+
+	log.Audit("Planning image customization components...")
+
+	p, err := eib.RunPlan(ctx, plan.NewRecorder(nil))
+	if err != nil {
+		log.Auditf("Planning the build failed. %s", checkPlanLogMessage)
+		zap.S().Fatalf("An error occurred planning the image build: %s", err)
+	}
+
+	printPlan(p)
+	log.Auditf("The planned combustion tree can be found at: %s", buildDir)
+
+	return nil
+}
+
+func printPlan(p *eib.Plan) {
+	log.Audit("\nCombustion scripts (in execution order):")
+	for i, script := range p.Scripts {
+		log.Auditf("  %d. %s", i+1, script)
+	}
+
+	log.Audit("\nCombustion directory files:")
+	for _, file := range p.CombustionFiles {
+		log.Auditf("  %s", file)
+	}
+
+	log.Audit("\nArtefacts directory files:")
+	for _, file := range p.ArtefactFiles {
+		log.Auditf("  %s", file)
+	}
+
+	log.Audit("\nArtefacts to be downloaded:")
+
+	var total int64
+	var unknown int
+
+	for _, artefact := range p.Artefacts {
+		log.Auditf("  [%s] %s (%s)", artefact.Kind, artefact.Source, formatSize(artefact.Size))
+
+		if artefact.Size == plan.UnknownSize {
+			unknown++
+		} else {
+			total += artefact.Size
+		}
+	}
+
+	summary := fmt.Sprintf("\nEstimated download size: %s", formatSize(total))
+	if unknown != 0 {
+		summary = fmt.Sprintf("%s, excluding %d artefact(s) of unknown size", summary, unknown)
+	}
+
+	log.Audit(summary)
+
+	if len(p.Unresolved) != 0 {
+		log.Audit("\nUnknown until the manifests and Helm charts are retrieved:")
+		for _, entry := range p.Unresolved {
+			log.Auditf("  %s", entry)
+		}
+	}
+}
+
+func formatSize(size int64) string {
+	if size == plan.UnknownSize {
+		return "unknown size"
+	}
+
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
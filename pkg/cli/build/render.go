@@ -0,0 +1,44 @@
+package build
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+)
+
+func RenderDefinition(_ *cli.Context) error {
+	args := &cmd.CommonArgs
+
+	if err := imageConfigDirExists(args.ConfigDir); err != nil {
+		logRenderError(err)
+		os.Exit(1)
+	}
+
+	definitionData, err := composeDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	if err != nil {
+		logRenderError(err)
+		os.Exit(1)
+	}
+
+	// Ensure the composed definition is a valid one before printing it
+	if _, err = parseDefinitionData(args.ConfigDir, args.DefinitionFile, definitionData); err != nil {
+		logRenderError(err)
+		os.Exit(1)
+	}
+
+	fmt.Print(string(definitionData))
+
+	return nil
+}
+
+// Rendering does not set up a log file, so any details are displayed directly to the user.
+func logRenderError(err *cmd.Error) {
+	log.Audit(err.UserMessage)
+
+	if err.LogMessage != "" && err.LogMessage != err.UserMessage {
+		log.Audit(err.LogMessage)
+	}
+}
@@ -0,0 +1,40 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"github.com/urfave/cli/v2"
+)
+
+func Schema(c *cli.Context) error {
+	apiVersion := c.String("api-version")
+
+	schema, err := validation.DefinitionSchema(apiVersion)
+	if err != nil {
+		m := "Invalid API version specified. This version of Edge Image Builder supports the following schema versions: %s"
+		logRenderError(&cmd.Error{
+			UserMessage: fmt.Sprintf(m, strings.Join(version.SupportedSchemaVersions, ", ")),
+			LogMessage:  fmt.Sprintf("Generating definition schema failed: %v", err),
+		})
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err = encoder.Encode(schema); err != nil {
+		logRenderError(&cmd.Error{
+			UserMessage: "The definition schema could not be printed.",
+			LogMessage:  fmt.Sprintf("Encoding definition schema failed: %v", err),
+		})
+		os.Exit(1)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package build
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+)
+
+// configureTLS applies the given CA bundle along with the per-host TLS settings of the artifact sources
+// to every outbound connection of the build.
+func configureTLS(caBundle string, sources *image.ArtifactSources, buildDir string) error {
+	settings, err := tlsSettings(caBundle, sources)
+	if err != nil {
+		return err
+	}
+
+	if err = http.ConfigureTLS(settings, buildDir); err != nil {
+		return err
+	}
+
+	if caBundle != "" {
+		log.AuditInfof("Trusting the certificate authorities in '%s' for all outbound connections.", caBundle)
+	}
+
+	for _, host := range slices.Sorted(maps.Keys(settings.Hosts)) {
+		if settings.Hosts[host].InsecureSkipVerify {
+			log.AuditInfof("Certificate verification is disabled for host '%s'.", host)
+		}
+	}
+
+	return nil
+}
+
+func tlsSettings(caBundle string, sources *image.ArtifactSources) (http.TLSSettings, error) {
+	settings := http.TLSSettings{
+		CABundle: caBundle,
+		Hosts:    map[string]http.HostTLSSettings{},
+	}
+
+	for _, hostTLS := range sources.TLS {
+		host := strings.ToLower(hostTLS.Host)
+
+		switch {
+		case host == "":
+			return http.TLSSettings{}, fmt.Errorf("TLS settings must specify a host")
+		case strings.ContainsAny(host, ":/"):
+			return http.TLSSettings{}, fmt.Errorf("TLS settings host '%s' must be a host name without scheme, port or path", hostTLS.Host)
+		case hostTLS.CAFile != "" && hostTLS.InsecureSkipVerify:
+			return http.TLSSettings{}, fmt.Errorf("TLS settings for host '%s' cannot specify both 'caFile' and 'insecureSkipVerify'", hostTLS.Host)
+		}
+
+		if _, exists := settings.Hosts[host]; exists {
+			return http.TLSSettings{}, fmt.Errorf("duplicate TLS settings for host '%s'", hostTLS.Host)
+		}
+
+		settings.Hosts[host] = http.HostTLSSettings{
+			CAFile:             hostTLS.CAFile,
+			InsecureSkipVerify: hostTLS.InsecureSkipVerify,
+		}
+	}
+
+	return settings, nil
+}
@@ -12,16 +12,34 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
 )
 
 const (
 	checkValidationLogMessage = "Please check the log file under the validation directory for more information."
+
+	outputText  = "text"
+	outputJSON  = "json"
+	outputSARIF = "sarif"
+
+	// definitionComponent groups the failures preventing the definition from being validated at all.
+	definitionComponent = "Definition"
 )
 
+var validationOutputFormats = []string{outputText, outputJSON, outputSARIF}
+
 func Validate(c *cli.Context) error {
 	args := &cmd.CommonArgs
 	isConfigDrive := c.Bool("config-drive")
+	strict := c.Bool("strict")
+
+	output := c.String("output")
+	if !slices.Contains(validationOutputFormats, output) {
+		log.Auditf("Invalid output format '%s'. Options: %s", output, strings.Join(validationOutputFormats, ", "))
+		return fmt.Errorf("invalid output format: %s", output)
+	}
 
 	validationDir := filepath.Join(args.ConfigDir, "_validation")
 	if err := os.MkdirAll(validationDir, os.ModePerm); err != nil {
@@ -34,19 +52,28 @@ func Validate(c *cli.Context) error {
 	logFilename := filepath.Join(validationDir, fmt.Sprintf("eib-validate-%s.log", timestamp))
 	log.ConfigureGlobalLogger(logFilename)
 
-	log.AuditInfo("Checking config dir...")
+	// Structured output is written to stdout as a whole, so progress is only recorded in the log file
+	auditInfo := log.AuditInfo
+	if output != outputText {
+		auditInfo = func(message string) { zap.S().Info(message) }
+	}
+
+	auditInfo("Checking config dir...")
 
 	if err := imageConfigDirExists(args.ConfigDir); err != nil {
-		cmd.LogError(err, checkValidationLogMessage)
-		os.Exit(1)
+		exitValidation(err, "definition/config-dir-unavailable", output)
 	}
 
-	log.AuditInfo("Parsing definition...")
+	auditInfo("Parsing definition...")
 
-	imageDefinition, err := parseDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	definitionData, err := composeDefinitionFile(args.ConfigDir, args.DefinitionFile)
 	if err != nil {
-		cmd.LogError(err, checkValidationLogMessage)
-		os.Exit(1)
+		exitValidation(err, "definition/unavailable", output)
+	}
+
+	imageDefinition, err := parseDefinitionData(args.ConfigDir, args.DefinitionFile, definitionData)
+	if err != nil {
+		exitValidation(err, "definition/invalid", output)
 	}
 
 	ctx := &image.Context{
@@ -56,12 +83,34 @@ func Validate(c *cli.Context) error {
 	}
 
 	if isConfigDrive {
-		log.AuditInfo("Validating config drive definition...")
+		auditInfo("Validating config drive definition...")
 	} else {
-		log.AuditInfo("Validating image definition...")
+		auditInfo("Validating image definition...")
 	}
 
-	if err = validateImageDefinition(ctx); err != nil {
+	if output != outputText {
+		failedValidations := validation.ValidateDefinition(ctx)
+		if strict {
+			validation.TreatWarningsAsErrors(failedValidations)
+		}
+
+		// Findings are located in the definition file as written rather than in the composed definition.
+		// Should the file no longer be readable, the findings are reported without a position.
+		fileData, _ := os.ReadFile(filepath.Join(args.ConfigDir, args.DefinitionFile))
+
+		report := validation.NewReport(failedValidations, args.DefinitionFile, fileData, definitionData)
+		if writeErr := writeValidationReport(report, output); writeErr != nil {
+			return writeErr
+		}
+
+		if !report.Valid {
+			os.Exit(1)
+		}
+
+		return nil
+	}
+
+	if err = validateImageDefinition(ctx, strict); err != nil {
 		cmd.LogError(err, checkValidationLogMessage)
 		os.Exit(1)
 	}
@@ -71,18 +120,81 @@ func Validate(c *cli.Context) error {
 	return nil
 }
 
-func validateImageDefinition(ctx *image.Context) *cmd.Error {
+// exitValidation reports a failure which prevents the definition from being validated and exits.
+// Structured output formats receive it as a single finding so that the output remains parsable.
+func exitValidation(err *cmd.Error, ruleID, output string) {
+	if output == outputText {
+		cmd.LogError(err, checkValidationLogMessage)
+		os.Exit(1)
+	}
+
+	if err.LogMessage != "" {
+		zap.S().Error(err.LogMessage)
+	}
+
+	failures := map[string][]validation.FailedValidation{
+		definitionComponent: {
+			{
+				UserMessage: err.UserMessage,
+				RuleID:      ruleID,
+			},
+		},
+	}
+
+	report := validation.NewReport(failures, cmd.CommonArgs.DefinitionFile, nil, nil)
+	if writeErr := writeValidationReport(report, output); writeErr != nil {
+		zap.S().Errorf("Writing validation report failed: %v", writeErr)
+	}
+
+	os.Exit(1)
+}
+
+func writeValidationReport(report *validation.Report, output string) error {
+	switch output {
+	case outputJSON:
+		return report.WriteJSON(os.Stdout)
+	case outputSARIF:
+		return report.WriteSARIF(os.Stdout, version.GetEibVersion())
+	default:
+		return fmt.Errorf("unsupported output format: %s", output)
+	}
+}
+
+// validateImageDefinition displays any warnings found in the definition and
+// returns an error if it contains failures preventing the image from being built.
+func validateImageDefinition(ctx *image.Context, strict bool) *cmd.Error {
 	failedValidations := validation.ValidateDefinition(ctx)
-	if len(failedValidations) == 0 {
+	if strict {
+		validation.TreatWarningsAsErrors(failedValidations)
+	}
+
+	if userMessage, logMessage := formatFailures(failedValidations, validation.SeverityInfo); userMessage != "" {
+		log.Audit("Image definition validation found the following notices:\n" + userMessage)
+		zap.S().Info("Image definition validation notices:\n" + logMessage)
+	}
+
+	if userMessage, logMessage := formatFailures(failedValidations, validation.SeverityWarning); userMessage != "" {
+		log.Audit("Image definition validation found the following warnings:\n" + userMessage)
+		zap.S().Warn("Image definition validation warnings:\n" + logMessage)
+	}
+
+	userMessage, logMessage := formatFailures(failedValidations, validation.SeverityError)
+	if userMessage == "" {
 		return nil
 	}
 
+	return &cmd.Error{
+		UserMessage: "Image definition validation found the following errors:\n" + userMessage,
+		LogMessage:  "Image definition validation failures:\n" + logMessage,
+	}
+}
+
+// formatFailures lists the failures of the given severity grouped by component for the user,
+// along with a log message which also contains the underlying errors.
+func formatFailures(failedValidations map[string][]validation.FailedValidation, severity validation.Severity) (userMessage, logMessage string) {
 	logMessageBuilder := strings.Builder{}
 	userMessageBuilder := strings.Builder{}
 
-	userMessageBuilder.WriteString("Image definition validation found the following errors:\n")
-	logMessageBuilder.WriteString("Image definition validation failures:\n")
-
 	orderedComponentNames := make([]string, 0, len(failedValidations))
 	for c := range failedValidations {
 		orderedComponentNames = append(orderedComponentNames, c)
@@ -90,9 +202,18 @@ func validateImageDefinition(ctx *image.Context) *cmd.Error {
 	slices.Sort(orderedComponentNames)
 
 	for _, componentName := range orderedComponentNames {
-		userMessageBuilder.WriteString("  " + componentName + "\n")
+		var componentHeaderWritten bool
 
 		for _, cf := range failedValidations[componentName] {
+			if cf.Level() != severity {
+				continue
+			}
+
+			if !componentHeaderWritten {
+				userMessageBuilder.WriteString("  " + componentName + "\n")
+				componentHeaderWritten = true
+			}
+
 			userMessageBuilder.WriteString("    " + cf.UserMessage + "\n")
 			logMessageBuilder.WriteString("  " + cf.UserMessage + "\n")
 			if cf.Error != nil {
@@ -101,8 +222,5 @@ func validateImageDefinition(ctx *image.Context) *cmd.Error {
 		}
 	}
 
-	return &cmd.Error{
-		UserMessage: userMessageBuilder.String(),
-		LogMessage:  logMessageBuilder.String(),
-	}
+	return userMessageBuilder.String(), logMessageBuilder.String()
 }
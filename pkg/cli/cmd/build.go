@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/urfave/cli/v2"
@@ -11,14 +12,56 @@ func validateBuildFlags(c *cli.Context) error {
 	cacheDirFlag := strings.ToLower(c.String("cache-dir"))
 	cacheEnabledFlag := c.Bool("cache")
 
-	return validateCache(cacheDirFlag, cacheEnabledFlag)
+	if err := validateCache(cacheDirFlag, cacheEnabledFlag, c.String("cache-max-size"), c.Bool("offline")); err != nil {
+		return err
+	}
+
+	if err := validateDownloadParallelism(c.Int("download-parallelism")); err != nil {
+		return err
+	}
+
+	return validateCABundle(c.String("ca-bundle"))
 }
 
-func validateCache(cacheDir string, cacheEnabled bool) error {
+func validateCache(cacheDir string, cacheEnabled bool, cacheMaxSize string, offline bool) error {
 	if !cacheEnabled {
+		if offline {
+			return fmt.Errorf("`offline` cannot be specified when `cache` is set to false")
+		}
+
 		if cacheDir != "/eib-cache" {
 			return fmt.Errorf("`cache-dir` cannot be specified when `cache` is set to false")
 		}
+
+		if cacheMaxSize != "" {
+			return fmt.Errorf("`cache-max-size` cannot be specified when `cache` is set to false")
+		}
+	}
+
+	_, err := ParseSize(cacheMaxSize)
+	return err
+}
+
+func validateDownloadParallelism(parallelism int) error {
+	if parallelism < 1 {
+		return fmt.Errorf("`download-parallelism` must be at least 1")
+	}
+
+	return nil
+}
+
+func validateCABundle(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("`ca-bundle` '%s' could not be read: %w", path, err)
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("`ca-bundle` '%s' must be a file", path)
 	}
 
 	return nil
@@ -37,6 +80,12 @@ func NewBuildCommand(action func(*cli.Context) error) *cli.Command {
 			BuildDirFlag,
 			CacheDirFlag,
 			CacheFlag,
+			CacheMaxSizeFlag,
+			OfflineFlag,
+			DownloadParallelismFlag,
+			CABundleFlag,
+			ArtifactSourcesFlag,
+			KubernetesReleaseIndexFlag,
 		},
 	}
 }
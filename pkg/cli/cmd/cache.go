@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/urfave/cli/v2"
+)
+
+type CacheActions struct {
+	List   func(*cli.Context) error
+	Prune  func(*cli.Context) error
+	Remove func(*cli.Context) error
+	Verify func(*cli.Context) error
+	Export func(*cli.Context) error
+	Import func(*cli.Context) error
+}
+
+func validatePruneFlags(c *cli.Context) error {
+	if _, err := ParseAge(c.String("older-than")); err != nil {
+		return err
+	}
+
+	_, err := ParseSize(c.String("max-size"))
+	return err
+}
+
+func validateExportFlags(c *cli.Context) error {
+	return validateCABundle(c.String("ca-bundle"))
+}
+
+func NewCacheCommand(actions CacheActions) *cli.Command {
+	return &cli.Command{
+		Name:      "cache",
+		Usage:     "Manage the artifact cache",
+		UsageText: fmt.Sprintf("%s cache COMMAND [OPTIONS]", appName),
+		Subcommands: []*cli.Command{
+			{
+				Name:      "list",
+				Usage:     "List the cached artifacts",
+				UsageText: fmt.Sprintf("%s cache list [OPTIONS]", appName),
+				Action:    actions.List,
+				Flags:     []cli.Flag{CacheDirFlag},
+			},
+			{
+				Name:      "prune",
+				Usage:     "Remove the least recently used artifacts",
+				UsageText: fmt.Sprintf("%s cache prune [OPTIONS]", appName),
+				Before:    validatePruneFlags,
+				Action:    actions.Prune,
+				Flags: []cli.Flag{
+					CacheDirFlag,
+					&cli.StringFlag{
+						Name:  "older-than",
+						Usage: "Remove the artifacts which have not been used for the given duration, e.g. '30d' or '12h'",
+					},
+					&cli.StringFlag{
+						Name:  "max-size",
+						Usage: "Remove the least recently used artifacts until the cache fits the given size, e.g. '100G'",
+					},
+				},
+			},
+			{
+				Name:      "remove",
+				Usage:     "Remove a cached artifact",
+				UsageText: fmt.Sprintf("%s cache remove [OPTIONS] IDENTIFIER", appName),
+				Action:    actions.Remove,
+				Flags:     []cli.Flag{CacheDirFlag},
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify the integrity of the cached artifacts and remove the corrupt ones",
+				UsageText: fmt.Sprintf("%s cache verify [OPTIONS]", appName),
+				Action:    actions.Verify,
+				Flags:     []cli.Flag{CacheDirFlag},
+			},
+			{
+				Name:      "export",
+				Usage:     "Download the artifacts required by an image definition and package them into a bundle",
+				UsageText: fmt.Sprintf("%s cache export --definition-file DEFINITION --output BUNDLE [OPTIONS]", appName),
+				Action:    actions.Export,
+				Before:    validateExportFlags,
+				Flags: []cli.Flag{
+					CacheDirFlag,
+					ConfigDirFlag,
+					BuildDirFlag,
+					CABundleFlag,
+					ArtifactSourcesFlag,
+					KubernetesReleaseIndexFlag,
+					&cli.StringFlag{
+						Name:        "definition-file",
+						Aliases:     []string{"definition"},
+						Usage:       "Name of the image definition file",
+						Destination: &CommonArgs.DefinitionFile,
+						Required:    true,
+					},
+					&cli.StringFlag{
+						Name:     "output",
+						Aliases:  []string{"o"},
+						Usage:    "Path of the bundle to create, relative to the image configuration directory, e.g. 'bundle.tar.zst'",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "Verify a bundle created by the export command and load its artifacts into the cache",
+				UsageText: fmt.Sprintf("%s cache import [OPTIONS] BUNDLE", appName),
+				Action:    actions.Import,
+				Flags:     []cli.Flag{CacheDirFlag},
+			},
+		},
+	}
+}
+
+// ParseSize converts sizes such as `500M`, `100G` or `1T` to bytes. An empty value is converted to zero.
+func ParseSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	size := image.DiskSize(value)
+	if !size.IsValid() {
+		return 0, fmt.Errorf("invalid size '%s': must be an integer followed by a suffix of either 'M', 'G', or 'T'", value)
+	}
+
+	return size.ToMB() * 1024 * 1024, nil
+}
+
+// ParseAge converts durations such as `30d` or `12h` to a time.Duration. An empty value is converted to zero.
+func ParseAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid duration '%s'", value)
+	}
+
+	return age, nil
+}
@@ -1,13 +1,22 @@
 package cmd
 
-import "github.com/urfave/cli/v2"
+import (
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/urfave/cli/v2"
+)
 
 type CommonFlags struct {
-	Cache          bool
-	CacheDir       string
-	DefinitionFile string
-	ConfigDir      string
-	RootBuildDir   string
+	Cache                  bool
+	CacheDir               string
+	CacheMaxSize           string
+	DefinitionFile         string
+	ConfigDir              string
+	RootBuildDir           string
+	Offline                bool
+	DownloadParallelism    int
+	CABundle               string
+	ArtifactSources        string
+	KubernetesReleaseIndex string
 }
 
 var CommonArgs CommonFlags
@@ -25,6 +34,11 @@ var (
 		Value:       "/eib-cache",
 		Destination: &CommonArgs.CacheDir,
 	}
+	CacheMaxSizeFlag = &cli.StringFlag{
+		Name:        "cache-max-size",
+		Usage:       "Maximum size of the cache directory, e.g. '100G'. Least recently used artifacts are evicted once exceeded",
+		Destination: &CommonArgs.CacheMaxSize,
+	}
 	DefinitionFileFlag = &cli.StringFlag{
 		Name:        "definition-file",
 		Usage:       "Name of the image definition file",
@@ -36,6 +50,32 @@ var (
 		Value:       "/eib",
 		Destination: &CommonArgs.ConfigDir,
 	}
+	OfflineFlag = &cli.BoolFlag{
+		Name:        "offline",
+		Usage:       "Only use cached artifacts and local content. Missing artifacts are listed before the build starts",
+		Destination: &CommonArgs.Offline,
+	}
+	DownloadParallelismFlag = &cli.IntFlag{
+		Name:        "download-parallelism",
+		Usage:       "Maximum number of artifacts downloaded concurrently",
+		Value:       http.DefaultParallelism,
+		Destination: &CommonArgs.DownloadParallelism,
+	}
+	CABundleFlag = &cli.StringFlag{
+		Name:        "ca-bundle",
+		Usage:       "Full path to a PEM file with additional certificate authorities trusted for all outbound connections",
+		Destination: &CommonArgs.CABundle,
+	}
+	ArtifactSourcesFlag = &cli.StringFlag{
+		Name:        "artifact-sources",
+		Usage:       "Full path to an artifact sources file merged over the built-in sources. Defaults to 'artifacts.yaml' in the image configuration directory if present",
+		Destination: &CommonArgs.ArtifactSources,
+	}
+	KubernetesReleaseIndexFlag = &cli.StringFlag{
+		Name:        "kubernetes-release-index",
+		Usage:       "Full path to a JSON release index used to resolve Kubernetes release channels instead of fetching it from the channels URL",
+		Destination: &CommonArgs.KubernetesReleaseIndex,
+	}
 	BuildDirFlag = &cli.StringFlag{
 		Name:        "build-dir",
 		Usage:       "Full path to the directory to store build artifacts",
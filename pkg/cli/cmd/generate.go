@@ -21,12 +21,16 @@ func validateGenerateFlags(c *cli.Context) error {
 
 	cacheDirFlag := strings.ToLower(c.String("cache-dir"))
 	cacheEnabledFlag := c.Bool("cache")
-	err := validateCache(cacheDirFlag, cacheEnabledFlag)
+	err := validateCache(cacheDirFlag, cacheEnabledFlag, c.String("cache-max-size"), c.Bool("offline"))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err = validateDownloadParallelism(c.Int("download-parallelism")); err != nil {
+		return err
+	}
+
+	return validateCABundle(c.String("ca-bundle"))
 }
 
 func NewGenerateCommand(action func(*cli.Context) error) *cli.Command {
@@ -42,6 +46,12 @@ func NewGenerateCommand(action func(*cli.Context) error) *cli.Command {
 			BuildDirFlag,
 			CacheDirFlag,
 			CacheFlag,
+			CacheMaxSizeFlag,
+			OfflineFlag,
+			DownloadParallelismFlag,
+			CABundleFlag,
+			ArtifactSourcesFlag,
+			KubernetesReleaseIndexFlag,
 			&cli.StringFlag{
 				Name:     "output-type",
 				Usage:    "The desired output type",
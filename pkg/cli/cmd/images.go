@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+func validateImagesFlags(c *cli.Context) error {
+	if err := validateCache(strings.ToLower(c.String("cache-dir")), c.Bool("cache"), "", false); err != nil {
+		return err
+	}
+
+	return validateCABundle(c.String("ca-bundle"))
+}
+
+func NewImagesCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "images",
+		Usage:     "List the container images embedded in the artifact registry and the reasons for embedding them",
+		UsageText: fmt.Sprintf("%s images [OPTIONS]", appName),
+		Before:    validateImagesFlags,
+		Action:    action,
+		Flags: []cli.Flag{
+			DefinitionFileFlag,
+			ConfigDirFlag,
+			BuildDirFlag,
+			CacheFlag,
+			CacheDirFlag,
+			CABundleFlag,
+			ArtifactSourcesFlag,
+			KubernetesReleaseIndexFlag,
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Format of the image list. Options: text, json",
+				Value: "text",
+			},
+		},
+	}
+}
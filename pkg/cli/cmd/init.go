@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/scaffold"
+	"github.com/urfave/cli/v2"
+)
+
+func validateInitFlags(c *cli.Context) error {
+	templateName := c.String("template")
+	if !slices.Contains(scaffold.Templates(), templateName) {
+		return fmt.Errorf("invalid template '%s': must be one of: %s", templateName, strings.Join(scaffold.Templates(), ", "))
+	}
+
+	arch := c.String("arch")
+	if arch != string(image.ArchTypeX86) && arch != string(image.ArchTypeARM) {
+		return fmt.Errorf("invalid arch '%s': must be either '%s' or '%s'", arch, image.ArchTypeX86, image.ArchTypeARM)
+	}
+
+	return nil
+}
+
+func NewInitCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "Create an image configuration directory from a template",
+		UsageText: fmt.Sprintf("%s init [OPTIONS]", appName),
+		Action:    action,
+		Before:    validateInitFlags,
+		Flags: []cli.Flag{
+			ConfigDirFlag,
+			&cli.StringFlag{
+				Name:  "definition-file",
+				Usage: "Name of the image definition file to create",
+				Value: "definition.yaml",
+			},
+			&cli.StringFlag{
+				Name:  "template",
+				Usage: fmt.Sprintf("Template of the image definition, one of: %s", strings.Join(scaffold.Templates(), ", ")),
+				Value: scaffold.TemplateOSOnly,
+			},
+			&cli.StringFlag{
+				Name:  "arch",
+				Usage: "Architecture of the base image",
+				Value: string(image.ArchTypeX86),
+			},
+			&cli.StringFlag{
+				Name:  "base-image",
+				Usage: "Name of the base image file under the 'base-images' directory (defaults to the SL Micro self install ISO)",
+			},
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"github.com/urfave/cli/v2"
+)
+
+func NewMigrateCommand(action func(*cli.Context) error) *cli.Command {
+	latestVersion := version.SupportedSchemaVersions[len(version.SupportedSchemaVersions)-1]
+
+	return &cli.Command{
+		Name:      "migrate",
+		Usage:     "Upgrade the definition to a newer API version",
+		UsageText: fmt.Sprintf("%s migrate [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			DefinitionFileFlag,
+			ConfigDirFlag,
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "API version to migrate the definition to",
+				Value: latestVersion,
+			},
+			&cli.BoolFlag{
+				Name:  "in-place",
+				Usage: "If specified, overwrites the definition file instead of printing the migrated definition",
+			},
+		},
+	}
+}
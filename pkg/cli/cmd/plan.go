@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func NewPlanCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "plan",
+		Usage:     "Show what building the image would produce without performing any downloads",
+		UsageText: fmt.Sprintf("%s plan [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			DefinitionFileFlag,
+			ConfigDirFlag,
+			BuildDirFlag,
+			ArtifactSourcesFlag,
+			KubernetesReleaseIndexFlag,
+		},
+	}
+}
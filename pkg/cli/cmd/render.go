@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func NewRenderDefinitionCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "render-definition",
+		Usage:     "Print the definition composed from the definitions it extends",
+		UsageText: fmt.Sprintf("%s render-definition [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			DefinitionFileFlag,
+			ConfigDirFlag,
+		},
+	}
+}
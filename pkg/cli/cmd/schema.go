@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"github.com/urfave/cli/v2"
+)
+
+func NewSchemaCommand(action func(*cli.Context) error) *cli.Command {
+	latestVersion := version.SupportedSchemaVersions[len(version.SupportedSchemaVersions)-1]
+
+	return &cli.Command{
+		Name:      "schema",
+		Usage:     "Print the JSON Schema of the image definition",
+		UsageText: fmt.Sprintf("%s schema [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "api-version",
+				Usage: "API version of the image definition schema",
+				Value: latestVersion,
+			},
+		},
+	}
+}
@@ -19,6 +19,15 @@ func NewValidateCommand(action func(*cli.Context) error) *cli.Command {
 				Name:  "config-drive",
 				Usage: "If specified, validates the input definition for generating a config drive.",
 			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "If specified, warnings are treated as errors.",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Format of the validation results. Options: text, json, sarif",
+				Value: "text",
+			},
 		},
 	}
 }
@@ -58,6 +58,19 @@ type imageDigester interface {
 	ImageDigest(img, arch string) (string, error)
 }
 
+type imageCache interface {
+	Lock(identifier string) (unlock func(), err error)
+	Get(identifier string) (path string, err error)
+	Put(identifier, source string, reader io.Reader) error
+}
+
+type registryStore interface {
+	Login(registry image.Registry, outputWriter io.Writer) error
+	AddImage(containerImage, arch string, outputWriter io.Writer) error
+	Save(imageTarDest string, outputWriter io.Writer) error
+	InstallBinary(destinationPath string) error
+}
+
 type Combustion struct {
 	NetworkConfigGenerator       networkConfigGenerator
 	NetworkConfiguratorInstaller networkConfiguratorInstaller
@@ -66,12 +79,21 @@ type Combustion struct {
 	RPMResolver                  rpmResolver
 	RPMRepoCreator               rpmRepoCreator
 	Registry                     embeddedRegistry
+	RegistryStore                registryStore
 	ImageDigester                imageDigester
+	ImageCache                   imageCache
 }
 
 // Configure iterates over all separate Combustion components and configures them independently.
 // If all of those are successful, the Combustion script is assembled and written to the file system.
 func (c *Combustion) Configure(ctx *image.Context) error {
+	_, err := c.ConfigureScripts(ctx)
+	return err
+}
+
+// ConfigureScripts behaves the same way as Configure and additionally returns
+// the ordered list of component scripts executed by the assembled Combustion script.
+func (c *Combustion) ConfigureScripts(ctx *image.Context) ([]string, error) {
 	var combustionScripts []string
 
 	// EIB Combustion script prefix ranges:
@@ -137,6 +159,10 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     fipsComponentName,
 			runnable: configureFIPS,
 		},
+		{
+			name:     kernelArgsComponentName,
+			runnable: configureKernelArgs,
+		},
 		{
 			name:     elementalComponentName,
 			runnable: configureElemental,
@@ -166,7 +192,7 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 	for _, component := range combustionComponents {
 		scripts, err := component.runnable(ctx)
 		if err != nil {
-			return fmt.Errorf("configuring component %q: %w", component.name, err)
+			return nil, fmt.Errorf("configuring component %q: %w", component.name, err)
 		}
 
 		combustionScripts = append(combustionScripts, scripts...)
@@ -175,7 +201,7 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 	// We manually add the cleanup component as to always make sure it is last
 	s, err := configureCleanup(ctx)
 	if err != nil {
-		return fmt.Errorf("configuring cleanup component %q: %w", cleanupComponentName, err)
+		return nil, fmt.Errorf("configuring cleanup component %q: %w", cleanupComponentName, err)
 	}
 	combustionScripts = append(combustionScripts, s...)
 
@@ -186,15 +212,15 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 
 	script, err := assembleScript(combustionScripts, networkScript)
 	if err != nil {
-		return fmt.Errorf("assembling script: %w", err)
+		return nil, fmt.Errorf("assembling script: %w", err)
 	}
 
 	filename := filepath.Join(ctx.CombustionDir, "script")
 	if err = os.WriteFile(filename, []byte(script), fileio.ExecutablePerms); err != nil {
-		return fmt.Errorf("writing script: %w", err)
+		return nil, fmt.Errorf("writing script: %w", err)
 	}
 
-	return nil
+	return combustionScripts, nil
 }
 
 func generateComponentPath(ctx *image.Context, componentDir string) string {
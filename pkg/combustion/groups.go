@@ -3,10 +3,7 @@ package combustion
 import (
 	_ "embed"
 	"fmt"
-	"os"
-	"path/filepath"
 
-	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
@@ -21,25 +18,31 @@ const (
 var groupsScript string
 
 func configureGroups(ctx *image.Context) ([]string, error) {
+	written, err := writeNodeScripts(ctx, groupsScriptName, renderGroupsScript)
+	if err != nil {
+		log.AuditComponentFailed(groupsComponentName)
+		return nil, fmt.Errorf("writing groups script: %w", err)
+	}
+
 	// Punch out early if there are no groups
-	if len(ctx.ImageDefinition.OperatingSystem.Groups) == 0 {
+	if !written {
 		log.AuditComponentSkipped(groupsComponentName)
 		return nil, nil
 	}
 
-	data, err := template.Parse(groupsScriptName, groupsScript, ctx.ImageDefinition.OperatingSystem.Groups)
-	if err != nil {
-		log.AuditComponentFailed(groupsComponentName)
-		return nil, fmt.Errorf("parsing the group script template: %w", err)
+	log.AuditComponentSuccessful(groupsComponentName)
+	return []string{groupsScriptName}, nil
+}
+
+func renderGroupsScript(_ string, os *image.OperatingSystem) (string, error) {
+	if len(os.Groups) == 0 {
+		return "", nil
 	}
 
-	filename := filepath.Join(ctx.CombustionDir, groupsScriptName)
-	err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms)
+	data, err := template.Parse(groupsScriptName, groupsScript, os.Groups)
 	if err != nil {
-		log.AuditComponentFailed(groupsComponentName)
-		return nil, fmt.Errorf("writing %s to the combustion directory: %w", groupsScriptName, err)
+		return "", fmt.Errorf("parsing the group script template: %w", err)
 	}
 
-	log.AuditComponentSuccessful(groupsComponentName)
-	return []string{groupsScriptName}, nil
+	return data, nil
 }
@@ -22,7 +22,7 @@ func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRep
 	var charts []image.HelmChart
 	var repos []image.HelmRepository
 
-	if ctx.ImageDefinition.Kubernetes.Network.APIVIP4 != "" || ctx.ImageDefinition.Kubernetes.Network.APIVIP6 != "" {
+	if ctx.ImageDefinition.Kubernetes.Network.APIVIPProvider() == image.VIPProviderMetalLB {
 		metalLBChart := image.HelmChart{
 			Name:                  ctx.ArtifactSources.MetalLB.Chart,
 			RepositoryName:        metallbRepositoryName,
@@ -58,3 +58,17 @@ func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRep
 
 	return charts, repos
 }
+
+// ComponentContainerImages returns the container images of the configured components which are served
+// by the embedded artifact registry.
+func ComponentContainerImages(ctx *image.Context) []string {
+	if ctx.ImageDefinition.Kubernetes.Version == "" {
+		return nil
+	}
+
+	if ctx.ImageDefinition.Kubernetes.Network.APIVIPProvider() == image.VIPProviderKubeVIP {
+		return []string{ctx.ArtifactSources.KubeVIP.Image}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package combustion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestComponentHelmCharts_VIPProvider(t *testing.T) {
+	tests := map[string]struct {
+		network        image.Network
+		expectedCharts []string
+		expectedImages []string
+	}{
+		"No VIP": {
+			network: image.Network{
+				VIPProvider: image.VIPProviderKubeVIP,
+			},
+		},
+		"Default": {
+			network: image.Network{
+				APIVIP4: "192.168.122.100",
+			},
+			expectedCharts: []string{"metallb", "endpoint-copier-operator"},
+		},
+		"MetalLB": {
+			network: image.Network{
+				APIVIP6:     "fd12:3456:789a::21",
+				VIPProvider: image.VIPProviderMetalLB,
+			},
+			expectedCharts: []string{"metallb", "endpoint-copier-operator"},
+		},
+		"kube-vip": {
+			network: image.Network{
+				APIVIP4:     "192.168.122.100",
+				VIPProvider: image.VIPProviderKubeVIP,
+			},
+			expectedImages: []string{"ghcr.io/kube-vip/kube-vip:v0.8.9"},
+		},
+		"None": {
+			network: image.Network{
+				APIVIP4:     "192.168.122.100",
+				VIPProvider: image.VIPProviderNone,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sources := &image.ArtifactSources{}
+			sources.MetalLB.Chart = "metallb"
+			sources.EndpointCopierOperator.Chart = "endpoint-copier-operator"
+			sources.KubeVIP.Image = "ghcr.io/kube-vip/kube-vip:v0.8.9"
+
+			ctx := &image.Context{
+				ImageDefinition: &image.Definition{
+					Kubernetes: image.Kubernetes{
+						Version: "v1.30.3+rke2r1",
+						Network: test.network,
+					},
+				},
+				ArtifactSources: sources,
+			}
+
+			charts, repos := ComponentHelmCharts(ctx)
+
+			var chartNames []string
+			for _, chart := range charts {
+				chartNames = append(chartNames, chart.Name)
+			}
+
+			assert.Equal(t, test.expectedCharts, chartNames)
+			assert.Len(t, repos, len(test.expectedCharts))
+			assert.Equal(t, test.expectedImages, ComponentContainerImages(ctx))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	kernelArgsComponentName = "node kernel args"
+	kernelArgsScriptName    = "16-kernel-args.sh"
+)
+
+//go:embed templates/16-kernel-args.sh.tpl
+var kernelArgsScript string
+
+// configureKernelArgs applies the node specific kernel arguments. The kernel arguments
+// shared between all nodes are configured when building the image instead.
+func configureKernelArgs(ctx *image.Context) ([]string, error) {
+	written, err := writeNodeScripts(ctx, kernelArgsScriptName, renderKernelArgsScript(ctx))
+	if err != nil {
+		log.AuditComponentFailed(kernelArgsComponentName)
+		return nil, fmt.Errorf("writing kernel args script: %w", err)
+	}
+
+	if !written {
+		log.AuditComponentSkipped(kernelArgsComponentName)
+		return nil, nil
+	}
+
+	log.AuditComponentSuccessful(kernelArgsComponentName)
+	return []string{kernelArgsScriptName}, nil
+}
+
+func renderKernelArgsScript(ctx *image.Context) renderNodeScript {
+	return func(hostname string, _ *image.OperatingSystem) (string, error) {
+		if hostname == "" {
+			return "", nil
+		}
+
+		override := ctx.ImageDefinition.NodeOverride(hostname)
+		if override == nil || len(override.OperatingSystem.KernelArgs) == 0 {
+			return "", nil
+		}
+
+		data, err := template.Parse(kernelArgsScriptName, kernelArgsScript, override.OperatingSystem.KernelArgs)
+		if err != nil {
+			return "", fmt.Errorf("parsing kernel args script template: %w", err)
+		}
+
+		return data, nil
+	}
+}
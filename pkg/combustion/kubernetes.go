@@ -1,6 +1,7 @@
 package combustion
 
 import (
+	"bytes"
 	_ "embed"
 	"fmt"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -32,6 +34,8 @@ const (
 	k8sInitServerConfigFile = "init_server.yaml"
 	k8sServerConfigFile     = "server.yaml"
 	k8sAgentConfigFile      = "agent.yaml"
+	k8sNodeConfigDir        = "nodes"
+	k8sKubeVIPManifestFile  = "kube-vip.yaml"
 
 	k8sInstallScript = "20-k8s-install.sh"
 	setNodeIPScript  = "set-node-ip.sh"
@@ -53,6 +57,9 @@ var (
 	//go:embed templates/k8s-vip.yaml.tpl
 	k8sVIPManifest string
 
+	//go:embed templates/kube-vip.yaml.tpl
+	kubeVIPManifest string
+
 	//go:embed templates/set-node-ip.sh.tpl
 	nodeIPScriptTemplate string
 )
@@ -75,11 +82,6 @@ func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 	// is usually taking longer to complete due to downloading files
 	log.Audit("Configuring Kubernetes component...")
 
-	if kubernetes.ServersCount(ctx.ImageDefinition.Kubernetes.Nodes) == 2 {
-		log.Audit("WARNING: Kubernetes clusters consisting of two server nodes cannot form a highly available architecture")
-		zap.S().Warn("Kubernetes cluster of two server nodes has been requested")
-	}
-
 	configDir := generateComponentPath(ctx, k8sDir)
 	configPath := filepath.Join(configDir, k8sConfigDir)
 
@@ -155,6 +157,11 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 		return "", fmt.Errorf("creating set node IP script: %w", err)
 	}
 
+	kubeVIPManifestPath, err := storeKubeVIPManifest(ctx)
+	if err != nil {
+		return "", fmt.Errorf("storing kube-vip manifest: %w", err)
+	}
+
 	templateValues := map[string]any{
 		"installScript":   installScript,
 		"apiVIP4":         ctx.ImageDefinition.Kubernetes.Network.APIVIP4,
@@ -166,6 +173,7 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 		"configFilePath":  prependArtefactPath(k8sDir),
 		"registryMirrors": prependArtefactPath(filepath.Join(k8sDir, registryMirrorsFileName)),
 		"setNodeIPScript": nodeIPScript,
+		"kubeVIPManifest": kubeVIPManifestPath,
 	}
 
 	singleNode := len(ctx.ImageDefinition.Kubernetes.Nodes) < 2
@@ -189,6 +197,7 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 	templateValues["nodes"] = ctx.ImageDefinition.Kubernetes.Nodes
 	templateValues["initialiser"] = cluster.InitialiserName
 	templateValues["initialiserConfigFile"] = k8sInitServerConfigFile
+	templateValues["nodeConfigDir"] = k8sNodeConfigDir
 
 	return storeKubernetesInstaller(ctx, "multi-node-k3s", k3sMultiNodeInstaller, templateValues)
 }
@@ -257,6 +266,11 @@ func (c *Combustion) configureRKE2(ctx *image.Context, cluster *kubernetes.Clust
 		return "", fmt.Errorf("creating set node IP script: %w", err)
 	}
 
+	kubeVIPManifestPath, err := storeKubeVIPManifest(ctx)
+	if err != nil {
+		return "", fmt.Errorf("storing kube-vip manifest: %w", err)
+	}
+
 	templateValues := map[string]any{
 		"installScript":   installScript,
 		"apiVIP4":         ctx.ImageDefinition.Kubernetes.Network.APIVIP4,
@@ -268,6 +282,7 @@ func (c *Combustion) configureRKE2(ctx *image.Context, cluster *kubernetes.Clust
 		"configFilePath":  prependArtefactPath(k8sDir),
 		"registryMirrors": prependArtefactPath(filepath.Join(k8sDir, registryMirrorsFileName)),
 		"setNodeIPScript": nodeIPScript,
+		"kubeVIPManifest": kubeVIPManifestPath,
 	}
 
 	singleNode := len(ctx.ImageDefinition.Kubernetes.Nodes) < 2
@@ -284,6 +299,7 @@ func (c *Combustion) configureRKE2(ctx *image.Context, cluster *kubernetes.Clust
 	templateValues["nodes"] = ctx.ImageDefinition.Kubernetes.Nodes
 	templateValues["initialiser"] = cluster.InitialiserName
 	templateValues["initialiserConfigFile"] = k8sInitServerConfigFile
+	templateValues["nodeConfigDir"] = k8sNodeConfigDir
 
 	return storeKubernetesInstaller(ctx, "multi-node-rke2", rke2MultiNodeInstaller, templateValues)
 }
@@ -354,6 +370,49 @@ func kubernetesVIPManifest(k *image.Kubernetes) (string, error) {
 	return template.Parse("k8s-vip", k8sVIPManifest, &manifest)
 }
 
+// storeKubeVIPManifest renders the static pod manifest of kube-vip into the Kubernetes artefacts if it serves the API VIPs.
+// Returns the path to the manifest, or an empty string if kube-vip is not used.
+func storeKubeVIPManifest(ctx *image.Context) (string, error) {
+	k := &ctx.ImageDefinition.Kubernetes
+	if k.Network.APIVIPProvider() != image.VIPProviderKubeVIP {
+		return "", nil
+	}
+
+	var addresses []string
+	for _, address := range []string{k.Network.APIVIP4, k.Network.APIVIP6} {
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	distro := image.KubernetesDistroK3S
+	if strings.Contains(k.Version, image.KubernetesDistroRKE2) {
+		distro = image.KubernetesDistroRKE2
+	}
+
+	manifest := struct {
+		Image      string
+		Address    string
+		Kubeconfig string
+	}{
+		Image:      ctx.ArtifactSources.KubeVIP.Image,
+		Address:    strings.Join(addresses, ","),
+		Kubeconfig: fmt.Sprintf("/etc/rancher/%[1]s/%[1]s.yaml", distro),
+	}
+
+	data, err := template.Parse("kube-vip", kubeVIPManifest, &manifest)
+	if err != nil {
+		return "", fmt.Errorf("parsing kube-vip template: %w", err)
+	}
+
+	manifestPath := filepath.Join(k8sDir, k8sKubeVIPManifestFile)
+	if err = os.WriteFile(filepath.Join(ctx.ArtefactsDir, manifestPath), []byte(data), fileio.NonExecutablePerms); err != nil {
+		return "", fmt.Errorf("writing kube-vip manifest: %w", err)
+	}
+
+	return prependArtefactPath(manifestPath), nil
+}
+
 func createNodeIPScript(ctx *image.Context, serverConfig map[string]any) (string, error) {
 	// Setting the Node IP only matters if we're doing dual-stack or single-stack IPv6
 	if ctx.ImageDefinition.Kubernetes.Network.APIVIP6 == "" {
@@ -412,6 +471,23 @@ func storeKubernetesClusterConfig(cluster *kubernetes.Cluster, destPath string)
 		}
 	}
 
+	if len(cluster.NodeConfigs) == 0 {
+		return nil
+	}
+
+	nodeConfigDir := filepath.Join(destPath, k8sNodeConfigDir)
+	if err := os.MkdirAll(nodeConfigDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating node config dir: %w", err)
+	}
+
+	for hostname, config := range cluster.NodeConfigs {
+		nodeConfig := filepath.Join(nodeConfigDir, hostname+".yaml")
+
+		if err := storeKubernetesConfig(config, nodeConfig); err != nil {
+			return fmt.Errorf("storing config file of node '%s': %w", hostname, err)
+		}
+	}
+
 	return nil
 }
 
@@ -430,7 +506,7 @@ func (c *Combustion) configureManifests(ctx *image.Context) (string, error) {
 	manifestsPath := localKubernetesManifestsPath()
 	manifestDestDir := filepath.Join(ctx.ArtefactsDir, manifestsPath)
 
-	if ctx.ImageDefinition.Kubernetes.Network.APIVIP4 != "" || ctx.ImageDefinition.Kubernetes.Network.APIVIP6 != "" {
+	if ctx.ImageDefinition.Kubernetes.Network.APIVIPProvider() == image.VIPProviderMetalLB {
 		if err := os.MkdirAll(manifestDestDir, os.ModePerm); err != nil {
 			return "", fmt.Errorf("creating manifests destination dir: %w", err)
 		}
@@ -477,6 +553,12 @@ func (c *Combustion) configureManifests(ctx *image.Context) (string, error) {
 				if err = os.WriteFile(filepath.Join(manifestDestDir, chartFileName), data, fileio.NonExecutablePerms); err != nil {
 					return "", fmt.Errorf("storing helm chart: %w", err)
 				}
+
+				if len(chart.CRDs) != 0 {
+					if err = storeHelmChartCRDs(chart, manifestDestDir); err != nil {
+						return "", fmt.Errorf("storing helm chart CRDs: %w", err)
+					}
+				}
 			}
 
 			manifestsPathPopulated = true
@@ -490,6 +572,26 @@ func (c *Combustion) configureManifests(ctx *image.Context) (string, error) {
 	return prependArtefactPath(manifestsPath), nil
 }
 
+// storeHelmChartCRDs writes the CRDs of the chart to a manifest named after it, e.g. `metallb-crds.yaml`.
+// Manifests are created in lexical order, so the CRDs are installed ahead of the chart in `metallb.yaml`.
+func storeHelmChartCRDs(chart *registry.HelmCRD, manifestDestDir string) error {
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	for _, crd := range chart.CRDs {
+		if err := encoder.Encode(crd); err != nil {
+			return fmt.Errorf("encoding CRD: %w", err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("closing encoder: %w", err)
+	}
+
+	crdsFileName := fmt.Sprintf("%s-crds.yaml", chart.Metadata.Name)
+	return os.WriteFile(filepath.Join(manifestDestDir, crdsFileName), buf.Bytes(), fileio.NonExecutablePerms)
+}
+
 func KubernetesConfigPath(ctx *image.Context) string {
 	return filepath.Join(ctx.ImageConfigDir, k8sDir, k8sConfigDir, k8sServerConfigFile)
 }
@@ -1300,6 +1300,187 @@ func TestConfigureKubernetes_Successful_MultiNode_RKE2_Dualstack_PrioIPv6_WithDu
 	assert.Nil(t, configContents["tls-san"])
 }
 
+func TestConfigureKubernetes_Successful_MultiNode_RKE2_NodeConfigs(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes = image.Kubernetes{
+		Version: "v1.30.3+rke2r1",
+		Network: image.Network{
+			APIVIP4: "192.168.122.100",
+		},
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+			},
+			{
+				Hostname: "node2.suse.com",
+				Type:     "agent",
+				Labels: map[string]string{
+					"accelerator": "nvidia",
+				},
+				Taints: []string{"nvidia.com/gpu=present:NoSchedule"},
+				Config: map[string]any{
+					"node-name": "gpu-worker",
+				},
+			},
+		},
+	}
+
+	c := Combustion{
+		KubernetesScriptDownloader: mockKubernetesScriptDownloader{
+			downloadScript: func(distribution, destPath string) (string, error) {
+				return kubernetesScriptInstaller, nil
+			},
+		},
+		KubernetesArtefactDownloader: mockKubernetesArtefactDownloader{
+			downloadRKE2Artefacts: func(_ image.Arch, _, _ string, _ bool, _ string, _, _ string) error {
+				return nil
+			},
+		},
+	}
+
+	scripts, err := c.configureKubernetes(ctx)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	// Script file assertions
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scripts[0]))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "NODECONFIGFILE=$ARTEFACTS_DIR/kubernetes/nodes/$HOSTNAME.yaml")
+	assert.Contains(t, contents, "CONFIGFILE=$NODECONFIGFILE")
+
+	// Node config file assertions
+	b, err = os.ReadFile(filepath.Join(ctx.ArtefactsDir, "kubernetes", "nodes", "node2.suse.com.yaml"))
+	require.NoError(t, err)
+
+	var configContents map[string]any
+	require.NoError(t, yaml.Unmarshal(b, &configContents))
+
+	assert.Equal(t, "https://192.168.122.100:9345", configContents["server"])
+	assert.Equal(t, "gpu-worker", configContents["node-name"])
+	assert.Equal(t, []any{"accelerator=nvidia"}, configContents["node-label"])
+	assert.Equal(t, []any{"nvidia.com/gpu=present:NoSchedule"}, configContents["node-taint"])
+
+	assert.NoFileExists(t, filepath.Join(ctx.ArtefactsDir, "kubernetes", "nodes", "node1.suse.com.yaml"))
+}
+
+func TestConfigureKubernetes_Successful_MultiNode_RKE2_KubeVIP(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ArtifactSources = &image.ArtifactSources{}
+	ctx.ArtifactSources.KubeVIP.Image = "ghcr.io/kube-vip/kube-vip:v0.8.9"
+
+	ctx.ImageDefinition.Kubernetes = image.Kubernetes{
+		Version: "v1.30.3+rke2r1",
+		Network: image.Network{
+			APIVIP4:     "192.168.122.100",
+			APIVIP6:     "fd12:3456:789a::21",
+			VIPProvider: image.VIPProviderKubeVIP,
+		},
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+			},
+			{
+				Hostname: "node2.suse.com",
+				Type:     "agent",
+			},
+		},
+	}
+
+	c := Combustion{
+		KubernetesScriptDownloader: mockKubernetesScriptDownloader{
+			downloadScript: func(distribution, destPath string) (string, error) {
+				return kubernetesScriptInstaller, nil
+			},
+		},
+		KubernetesArtefactDownloader: mockKubernetesArtefactDownloader{
+			downloadRKE2Artefacts: func(_ image.Arch, _, _ string, _ bool, _ string, _, _ string) error {
+				return nil
+			},
+		},
+	}
+
+	scripts, err := c.configureKubernetes(ctx)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	// Script file assertions
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scripts[0]))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/kube-vip.yaml /var/lib/rancher/rke2/agent/pod-manifests/")
+	assert.NotContains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/manifests/* /opt/eib-k8s/manifests/")
+
+	// Manifest assertions
+	b, err = os.ReadFile(filepath.Join(ctx.ArtefactsDir, "kubernetes", "kube-vip.yaml"))
+	require.NoError(t, err)
+
+	manifest := string(b)
+	assert.Contains(t, manifest, "image: ghcr.io/kube-vip/kube-vip:v0.8.9")
+	assert.Contains(t, manifest, "value: \"192.168.122.100,fd12:3456:789a::21\"")
+	assert.Contains(t, manifest, "path: /etc/rancher/rke2/rke2.yaml")
+
+	assert.NoFileExists(t, filepath.Join(ctx.ArtefactsDir, "kubernetes", "manifests", "k8s-vip.yaml"))
+}
+
+func TestConfigureKubernetes_Successful_SingleNode_K3s_NoVIPProvider(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes = image.Kubernetes{
+		Version: "v1.30.3+k3s1",
+		Network: image.Network{
+			APIVIP4:     "192.168.122.100",
+			VIPProvider: image.VIPProviderNone,
+		},
+	}
+
+	c := Combustion{
+		KubernetesScriptDownloader: mockKubernetesScriptDownloader{
+			downloadScript: func(distribution, destPath string) (string, error) {
+				return kubernetesScriptInstaller, nil
+			},
+		},
+		KubernetesArtefactDownloader: mockKubernetesArtefactDownloader{
+			downloadK3sArtefacts: func(arch image.Arch, version string, installPath, imagesPath string) error {
+				binary := filepath.Join(installPath, "cool-k3s-binary")
+				return os.WriteFile(binary, nil, os.ModePerm)
+			},
+		},
+	}
+
+	scripts, err := c.configureKubernetes(ctx)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scripts[0]))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.NotContains(t, contents, "pod-manifests")
+	assert.NotContains(t, contents, "/opt/eib-k8s/manifests")
+
+	b, err = os.ReadFile(filepath.Join(ctx.ArtefactsDir, "kubernetes", "server.yaml"))
+	require.NoError(t, err)
+
+	var configContents map[string]any
+	require.NoError(t, yaml.Unmarshal(b, &configContents))
+
+	assert.Equal(t, []any{"192.168.122.100"}, configContents["tls-san"])
+	assert.Nil(t, configContents["disable"])
+
+	assert.NoFileExists(t, filepath.Join(ctx.ArtefactsDir, "kubernetes", "kube-vip.yaml"))
+	assert.NoFileExists(t, filepath.Join(ctx.ArtefactsDir, "kubernetes", "manifests", "k8s-vip.yaml"))
+}
+
 func TestConfigureManifests_NoSetup(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
@@ -1419,6 +1600,55 @@ spec:
 	assert.Equal(t, chartContent, string(b))
 }
 
+func TestConfigureManifests_SeparateCRDs(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	helmChart := &image.HelmChart{
+		Name:           "operator",
+		RepositoryName: "operator-repo",
+		Version:        "1.0.0",
+		CRDHandling:    image.CRDHandlingSeparate,
+	}
+
+	c := Combustion{
+		Registry: &mockEmbeddedRegistry{
+			manifestsPathFunc: func() string {
+				return ""
+			},
+			helmChartsFunc: func() ([]*registry.HelmCRD, error) {
+				chart := registry.NewHelmCRD(helmChart, "some-content", "", "oci://registry.example.com/charts")
+				chart.CRDs = []map[string]any{
+					{"kind": "CustomResourceDefinition", "metadata": map[string]any{"name": "clusters.example.com"}},
+					{"kind": "CustomResourceDefinition", "metadata": map[string]any{"name": "backups.example.com"}},
+				}
+
+				return []*registry.HelmCRD{chart}, nil
+			},
+		},
+	}
+
+	manifestsPath, err := c.configureManifests(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "$ARTEFACTS_DIR/kubernetes/manifests", manifestsPath)
+	assert.FileExists(t, filepath.Join(ctx.ArtefactsDir, k8sDir, k8sManifestsDir, "operator.yaml"))
+
+	crdsPath := filepath.Join(ctx.ArtefactsDir, k8sDir, k8sManifestsDir, "operator-crds.yaml")
+	crdsContent := `kind: CustomResourceDefinition
+metadata:
+    name: clusters.example.com
+---
+kind: CustomResourceDefinition
+metadata:
+    name: backups.example.com
+`
+	b, err := os.ReadFile(crdsPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, crdsContent, string(b))
+}
+
 func TestConfigureKubernetes_Successful_RKE2Server_WithManifests(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
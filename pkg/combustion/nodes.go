@@ -0,0 +1,131 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	nodeOverridesDir     = "node-overrides"
+	nodeDefaultScriptDir = "_default"
+)
+
+//go:embed templates/node-selector.sh.tpl
+var nodeSelectorScript string
+
+// renderNodeScript renders the contents of a component script for the given operating system configuration.
+// The hostname is empty when rendering the configuration shared between all nodes.
+// An empty result indicates that the component has nothing to configure.
+type renderNodeScript func(hostname string, os *image.OperatingSystem) (string, error)
+
+// writeNodeScripts renders the script of a combustion component for the shared configuration
+// as well as for each of the configured node overrides.
+//
+// If none of the node overrides result in a different script, only the shared script is written
+// under the given name. Otherwise, the script under the given name selects which of the rendered
+// scripts to execute based on the hostname of the node.
+//
+// Returns whether a script has been written at all.
+func writeNodeScripts(ctx *image.Context, scriptName string, render renderNodeScript) (bool, error) {
+	def := ctx.ImageDefinition
+
+	defaultScript, err := render("", &def.OperatingSystem)
+	if err != nil {
+		return false, fmt.Errorf("rendering shared script: %w", err)
+	}
+
+	type nodeScript struct {
+		Hostname string
+		Script   string
+		contents string
+	}
+
+	var nodeScripts []nodeScript
+	for _, override := range def.NodeOverrides {
+		contents, err := render(override.Hostname, def.NodeOperatingSystem(override.Hostname))
+		if err != nil {
+			return false, fmt.Errorf("rendering script for node '%s': %w", override.Hostname, err)
+		}
+
+		if contents == defaultScript {
+			continue
+		}
+
+		nodeScripts = append(nodeScripts, nodeScript{
+			Hostname: override.Hostname,
+			Script:   filepath.Join(nodeOverridesDir, override.Hostname, scriptName),
+			contents: contents,
+		})
+	}
+
+	if len(nodeScripts) == 0 {
+		if defaultScript == "" {
+			return false, nil
+		}
+
+		filename := filepath.Join(ctx.CombustionDir, scriptName)
+		if err = os.WriteFile(filename, []byte(defaultScript), fileio.ExecutablePerms); err != nil {
+			return false, fmt.Errorf("writing %s to the combustion directory: %w", scriptName, err)
+		}
+
+		return true, nil
+	}
+
+	values := struct {
+		Nodes         []nodeScript
+		DefaultScript string
+	}{
+		Nodes: nodeScripts,
+	}
+
+	if defaultScript != "" {
+		values.DefaultScript = filepath.Join(nodeOverridesDir, nodeDefaultScriptDir, scriptName)
+		if err = writeCombustionFile(ctx, values.DefaultScript, defaultScript); err != nil {
+			return false, err
+		}
+	}
+
+	for _, s := range nodeScripts {
+		if err = writeCombustionFile(ctx, s.Script, s.contents); err != nil {
+			return false, err
+		}
+	}
+
+	data, err := template.Parse(scriptName, nodeSelectorScript, &values)
+	if err != nil {
+		return false, fmt.Errorf("parsing node selector template: %w", err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, scriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return false, fmt.Errorf("writing %s to the combustion directory: %w", scriptName, err)
+	}
+
+	return true, nil
+}
+
+func writeCombustionFile(ctx *image.Context, path, contents string) error {
+	filename := filepath.Join(ctx.CombustionDir, path)
+
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(filename, []byte(contents), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing %s to the combustion directory: %w", path, err)
+	}
+
+	return nil
+}
+
+// NodeOverridesPath returns the directory in the image configuration directory
+// containing the additional files for the given node.
+func NodeOverridesPath(ctx *image.Context, hostname string) string {
+	return filepath.Join(ctx.ImageConfigDir, nodeOverridesDir, hostname)
+}
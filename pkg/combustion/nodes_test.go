@@ -0,0 +1,278 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWriteNodeScripts_NoOverrides(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	render := func(hostname string, _ *image.OperatingSystem) (string, error) {
+		return "shared " + hostname, nil
+	}
+
+	// Test
+	written, err := writeNodeScripts(ctx, "script.sh", render)
+
+	// Verify
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.CombustionDir, "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "shared ", string(contents))
+
+	assert.NoDirExists(t, filepath.Join(ctx.CombustionDir, nodeOverridesDir))
+}
+
+func TestWriteNodeScripts_NothingToConfigure(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.NodeOverrides = []image.NodeOverride{{Hostname: "node1.suse.com"}}
+
+	render := func(string, *image.OperatingSystem) (string, error) {
+		return "", nil
+	}
+
+	// Test
+	written, err := writeNodeScripts(ctx, "script.sh", render)
+
+	// Verify
+	require.NoError(t, err)
+	assert.False(t, written)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, "script.sh"))
+}
+
+func TestWriteNodeScripts_Overrides(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.NodeOverrides = []image.NodeOverride{
+		{Hostname: "node1.suse.com"},
+		{Hostname: "node2.suse.com"},
+	}
+
+	render := func(hostname string, _ *image.OperatingSystem) (string, error) {
+		if hostname == "node1.suse.com" {
+			return "node1", nil
+		}
+
+		return "shared", nil
+	}
+
+	// Test
+	written, err := writeNodeScripts(ctx, "script.sh", render)
+
+	// Verify
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	selectorFilename := filepath.Join(ctx.CombustionDir, "script.sh")
+	contents, err := os.ReadFile(selectorFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(selectorFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	found := string(contents)
+	assert.Contains(t, found, "HOSTNAME=$(cat /etc/hostname)")
+	assert.Contains(t, found, "\"node1.suse.com\")\n    ./node-overrides/node1.suse.com/script.sh\n")
+	assert.Contains(t, found, "*)\n    ./node-overrides/_default/script.sh\n")
+	assert.NotContains(t, found, "node2.suse.com", "node rendering the shared script must not be selected explicitly")
+
+	contents, err = os.ReadFile(filepath.Join(ctx.CombustionDir, nodeOverridesDir, "node1.suse.com", "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "node1", string(contents))
+
+	contents, err = os.ReadFile(filepath.Join(ctx.CombustionDir, nodeOverridesDir, nodeDefaultScriptDir, "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "shared", string(contents))
+}
+
+func TestWriteNodeScripts_OverridesOnly(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.NodeOverrides = []image.NodeOverride{{Hostname: "node1.suse.com"}}
+
+	render := func(hostname string, _ *image.OperatingSystem) (string, error) {
+		if hostname == "node1.suse.com" {
+			return "node1", nil
+		}
+
+		return "", nil
+	}
+
+	// Test
+	written, err := writeNodeScripts(ctx, "script.sh", render)
+
+	// Verify
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.CombustionDir, "script.sh"))
+	require.NoError(t, err)
+
+	found := string(contents)
+	assert.Contains(t, found, "./node-overrides/node1.suse.com/script.sh")
+	assert.Contains(t, found, "echo \"No node specific configuration found for host '$HOSTNAME'\"")
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, nodeOverridesDir, nodeDefaultScriptDir, "script.sh"))
+}
+
+func TestConfigureUsers_NodeOverrides(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Users: []image.OperatingSystemUser{
+				{Username: "alpha", EncryptedPassword: "alpha123"},
+			},
+		},
+		NodeOverrides: []image.NodeOverride{
+			{
+				Hostname: "node1.suse.com",
+				OperatingSystem: image.OperatingSystemNodeOverride{
+					Users: []image.OperatingSystemUser{
+						{Username: "alpha", EncryptedPassword: "alpha456"},
+						{Username: "beta", EncryptedPassword: "beta123"},
+					},
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureUsers(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{usersScriptName}, scripts)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.CombustionDir, nodeOverridesDir, nodeDefaultScriptDir, usersScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "echo 'alpha:alpha123' | chpasswd -e\n")
+	assert.NotContains(t, string(contents), "beta")
+
+	contents, err = os.ReadFile(filepath.Join(ctx.CombustionDir, nodeOverridesDir, "node1.suse.com", usersScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "echo 'alpha:alpha456' | chpasswd -e\n")
+	assert.Contains(t, string(contents), "echo 'beta:beta123' | chpasswd -e\n")
+	assert.NotContains(t, string(contents), "alpha123")
+}
+
+func TestConfigureKernelArgs(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			KernelArgs: []string{"alpha=foo"},
+		},
+		NodeOverrides: []image.NodeOverride{
+			{
+				Hostname: "node1.suse.com",
+				OperatingSystem: image.OperatingSystemNodeOverride{
+					KernelArgs: []string{"beta=bar", "root=/dev/sda", "baz"},
+				},
+			},
+			{
+				Hostname: "node2.suse.com",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureKernelArgs(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{kernelArgsScriptName}, scripts)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.CombustionDir, kernelArgsScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "./node-overrides/node1.suse.com/16-kernel-args.sh")
+	assert.Contains(t, string(contents), `[ "$HOSTNAME" = "localhost.localdomain" ]`)
+	assert.NotContains(t, string(contents), "node2.suse.com")
+
+	contents, err = os.ReadFile(filepath.Join(ctx.CombustionDir, nodeOverridesDir, "node1.suse.com", kernelArgsScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "NODE_ARGS=('beta=bar' 'root=/dev/sda' 'baz' )")
+	assert.Contains(t, string(contents), "grub2-mkconfig -o /boot/grub2/grub.cfg")
+	assert.NotContains(t, string(contents), "alpha=foo")
+}
+
+func TestConfigureKernelArgs_NoOverrides(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			KernelArgs: []string{"alpha=foo"},
+		},
+	}
+
+	// Test
+	scripts, err := configureKernelArgs(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureOSFiles_NodeOverrides(t *testing.T) {
+	// Setup
+	ctx, teardown := setupOsFilesConfigDir(t, false)
+	defer teardown()
+
+	ctx.ImageDefinition.NodeOverrides = []image.NodeOverride{
+		{Hostname: "node1.suse.com"},
+		{Hostname: "node2.suse.com"},
+	}
+
+	nodeFilesDir := filepath.Join(ctx.ImageConfigDir, nodeOverridesDir, "node1.suse.com", osFilesConfigDir, "etc")
+	require.NoError(t, os.MkdirAll(nodeFilesDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(nodeFilesDir, "node-file"), []byte("node1"), 0o600))
+
+	// Test
+	scripts, err := configureOSFiles(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{osFilesScriptName}, scripts)
+
+	assert.FileExists(t, filepath.Join(ctx.CombustionDir, osFilesConfigDir, "etc", "ssh", "test-config-file"))
+	assert.FileExists(t, filepath.Join(ctx.CombustionDir, nodeOverridesDir, "node1.suse.com", osFilesConfigDir, "etc", "node-file"))
+
+	contents, err := os.ReadFile(filepath.Join(ctx.CombustionDir, osFilesScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "./node-overrides/node1.suse.com/19-copy-os-files.sh")
+	assert.NotContains(t, string(contents), "node2.suse.com")
+
+	contents, err = os.ReadFile(filepath.Join(ctx.CombustionDir, nodeOverridesDir, "node1.suse.com", osFilesScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "cp -R ./os-files/* /")
+	assert.Contains(t, string(contents), "cp -R ./node-overrides/node1.suse.com/os-files/* /")
+
+	contents, err = os.ReadFile(filepath.Join(ctx.CombustionDir, nodeOverridesDir, nodeDefaultScriptDir, osFilesScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "cp -R ./os-files/* /")
+	assert.NotContains(t, string(contents), "node-overrides")
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
 
@@ -20,35 +22,89 @@ const (
 )
 
 var (
-	//go:embed templates/19-copy-os-files.sh
+	//go:embed templates/19-copy-os-files.sh.tpl
 	osFilesScript string
 )
 
 func configureOSFiles(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, osFilesConfigDir) {
+	sharedFiles := isComponentConfigured(ctx, osFilesConfigDir)
+	nodeFiles := nodeOSFilesHostnames(ctx)
+
+	if !sharedFiles && len(nodeFiles) == 0 {
 		log.AuditComponentSkipped(osFilesComponentName)
 		zap.S().Info("skipping os files component, no files provided")
 		return nil, nil
 	}
 
-	if err := copyOSFiles(ctx); err != nil {
-		log.AuditComponentFailed(osFilesComponentName)
-		return nil, err
+	if sharedFiles {
+		srcDirectory := filepath.Join(ctx.ImageConfigDir, osFilesConfigDir)
+		destDirectory := filepath.Join(ctx.CombustionDir, osFilesConfigDir)
+
+		if err := copyOSFiles(srcDirectory, destDirectory); err != nil {
+			log.AuditComponentFailed(osFilesComponentName)
+			return nil, err
+		}
 	}
 
-	if err := writeOSFilesScript(ctx); err != nil {
+	for _, hostname := range nodeFiles {
+		srcDirectory := filepath.Join(NodeOverridesPath(ctx, hostname), osFilesConfigDir)
+		destDirectory := filepath.Join(ctx.CombustionDir, nodeOSFilesDir(hostname))
+
+		if err := copyOSFiles(srcDirectory, destDirectory); err != nil {
+			log.AuditComponentFailed(osFilesComponentName)
+			return nil, err
+		}
+	}
+
+	render := func(hostname string, _ *image.OperatingSystem) (string, error) {
+		var dirs []string
+
+		if sharedFiles {
+			dirs = append(dirs, osFilesConfigDir)
+		}
+
+		if hostname != "" && slices.Contains(nodeFiles, hostname) {
+			dirs = append(dirs, nodeOSFilesDir(hostname))
+		}
+
+		if len(dirs) == 0 {
+			return "", nil
+		}
+
+		return template.Parse(osFilesScriptName, osFilesScript, dirs)
+	}
+
+	if _, err := writeNodeScripts(ctx, osFilesScriptName, render); err != nil {
 		log.AuditComponentFailed(osFilesComponentName)
-		return nil, err
+		return nil, fmt.Errorf("writing os files script: %w", err)
 	}
 
 	log.AuditComponentSuccessful(osFilesComponentName)
 	return []string{osFilesScriptName}, nil
 }
 
-func copyOSFiles(ctx *image.Context) error {
-	srcDirectory := filepath.Join(ctx.ImageConfigDir, osFilesConfigDir)
-	destDirectory := filepath.Join(ctx.CombustionDir, osFilesConfigDir)
+// nodeOSFilesHostnames returns the hostnames of the node overrides providing their own os files.
+func nodeOSFilesHostnames(ctx *image.Context) []string {
+	var hostnames []string
+
+	for _, override := range ctx.ImageDefinition.NodeOverrides {
+		if override.Hostname == "" {
+			continue
+		}
 
+		if isComponentConfigured(ctx, filepath.Join(nodeOverridesDir, override.Hostname, osFilesConfigDir)) {
+			hostnames = append(hostnames, override.Hostname)
+		}
+	}
+
+	return hostnames
+}
+
+func nodeOSFilesDir(hostname string) string {
+	return filepath.Join(nodeOverridesDir, hostname, osFilesConfigDir)
+}
+
+func copyOSFiles(srcDirectory, destDirectory string) error {
 	dirEntries, err := os.ReadDir(srcDirectory)
 	if err != nil {
 		return fmt.Errorf("reading the os files directory at %s: %w", srcDirectory, err)
@@ -65,13 +121,3 @@ func copyOSFiles(ctx *image.Context) error {
 
 	return nil
 }
-
-func writeOSFilesScript(ctx *image.Context) error {
-	osFilesScriptFilename := filepath.Join(ctx.CombustionDir, osFilesScriptName)
-
-	if err := os.WriteFile(osFilesScriptFilename, []byte(osFilesScript), fileio.ExecutablePerms); err != nil {
-		return fmt.Errorf("writing os files script %s: %w", osFilesScriptFilename, err)
-	}
-
-	return nil
-}
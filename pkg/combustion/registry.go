@@ -2,8 +2,10 @@ package combustion
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/schollz/progressbar/v3"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
@@ -64,17 +67,25 @@ func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
 	return []string{script}, nil
 }
 
-func storeImage(containerImage, arch string, outputWriter io.Writer) error {
+// Hauler manages the embedded artifact registry store using the hauler CLI.
+type Hauler struct{}
+
+func (Hauler) AddImage(containerImage, arch string, outputWriter io.Writer) error {
+	if err := http.AllowNetworkAccess(fmt.Sprintf("pulling container image '%s'", containerImage)); err != nil {
+		return err
+	}
+
 	args := []string{"store", "add", "image", containerImage, "-p", fmt.Sprintf("linux/%s", arch)}
 
 	cmd := exec.Command(hauler, args...)
+	cmd.Env = http.CommandEnv()
 	cmd.Stdout = outputWriter
 	cmd.Stderr = outputWriter
 
 	return cmd.Run()
 }
 
-func generateRegistryTar(imageTarDest string, outputWriter io.Writer) error {
+func (Hauler) Save(imageTarDest string, outputWriter io.Writer) error {
 	args := []string{"store", "save", "--filename", imageTarDest}
 
 	cmd := exec.Command(hauler, args...)
@@ -92,16 +103,28 @@ func generateRegistryTar(imageTarDest string, outputWriter io.Writer) error {
 	return nil
 }
 
-func loginToRegistry(registry image.Registry, outputWriter io.Writer) error {
-	args := []string{"login", registry.URI, "--username", registry.Authentication.Username, "--password", registry.Authentication.Password}
+func (Hauler) Login(registry image.Registry, outputWriter io.Writer) error {
+	if err := http.AllowNetworkAccess(fmt.Sprintf("logging into registry '%s'", registry.URI)); err != nil {
+		return err
+	}
+
+	args := []string{"login", registry.URI, "--username", registry.Authentication.Username, "--password-stdin"}
 
 	cmd := exec.Command(hauler, args...)
+	cmd.Env = http.CommandEnv()
+	cmd.Stdin = strings.NewReader(registry.Authentication.Password)
 	cmd.Stdout = outputWriter
 	cmd.Stderr = outputWriter
 
 	return cmd.Run()
 }
 
+func (Hauler) InstallBinary(destinationPath string) error {
+	const sourcePath = "/usr/bin/hauler"
+
+	return fileio.CopyFile(sourcePath, destinationPath, fileio.ExecutablePerms)
+}
+
 func writeRegistryScript(ctx *image.Context) (string, error) {
 	values := struct {
 		RegistryPort      string
@@ -198,9 +221,8 @@ func (c *Combustion) configureEmbeddedArtifactRegistry(ctx *image.Context, conta
 		return "", fmt.Errorf("populating registry: %w", err)
 	}
 
-	sourcePath := "/usr/bin/hauler"
-	destinationPath := filepath.Join(registryArtefactsPath(ctx), "hauler")
-	if err := fileio.CopyFile(sourcePath, destinationPath, fileio.ExecutablePerms); err != nil {
+	destinationPath := filepath.Join(registryArtefactsPath(ctx), hauler)
+	if err := c.RegistryStore.InstallBinary(destinationPath); err != nil {
 		return "", fmt.Errorf("copying hauler binary: %w", err)
 	}
 
@@ -217,17 +239,7 @@ func registryArtefactsPath(ctx *image.Context) string {
 }
 
 func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error {
-	enableCache := ctx.CacheDir != ""
-
-	var imageCacheDir string
-	if enableCache {
-		imageCacheDir = filepath.Join(ctx.CacheDir, "images")
-		if !fileio.DirExists(imageCacheDir) {
-			if err := os.Mkdir(imageCacheDir, os.ModePerm); err != nil {
-				return fmt.Errorf("creating container image cache dir: %w", err)
-			}
-		}
-	}
+	enableCache := c.ImageCache != nil
 
 	const registryLogFileName = "embedded-registry.log"
 	logFilename := filepath.Join(ctx.BuildDir, registryLogFileName)
@@ -243,10 +255,29 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 		}
 	}()
 
-	for _, registry := range ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries {
-		if err = loginToRegistry(registry, logFile); err != nil {
-			return fmt.Errorf("logging into registry '%s': %w", registry.URI, err)
+	output := log.NewRedactingWriter(logFile)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Failed to flush registry log file properly: %v", err)
+		}
+	}()
+
+	// Logging into the registries is deferred until the first image has to be pulled,
+	// so that builds served entirely from the cache do not require network access
+	loggedIn := false
+	login := func() error {
+		if loggedIn {
+			return nil
+		}
+
+		for _, registry := range ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries {
+			if err := c.RegistryStore.Login(registry, output); err != nil {
+				return fmt.Errorf("logging into registry '%s': %w", registry.URI, err)
+			}
 		}
+
+		loggedIn = true
+		return nil
 	}
 
 	bar := progressbar.Default(int64(len(images)), "Populating Embedded Artifact Registry...")
@@ -257,53 +288,34 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 	var imagesWithDigest []string
 	for _, img := range images {
 		cacheImage := enableCache
-		convertedImage := strings.ReplaceAll(img, "/", "_")
-		convertedImageName := fmt.Sprintf("%s-%s", convertedImage, registryTarSuffix)
-		if strings.Contains(img, ":latest") {
+		convertedImageName := registryImageTarName(img, "")
+		if isLatestImage(img) {
 			var digest string
 			digest, err = c.ImageDigester.ImageDigest(img, arch)
 			if err != nil {
 				zap.S().Warnf("Failed getting digest for %s: %s", img, err)
 				cacheImage = false
 			} else {
-				convertedImageName = fmt.Sprintf("%s-%s-%s", convertedImage, digest, registryTarSuffix)
+				convertedImageName = registryImageTarName(img, digest)
 			}
 		} else if strings.Contains(img, "sha256:") {
 			imagesWithDigest = append(imagesWithDigest, img)
 		}
 
-		imageCacheLocation := filepath.Join(imageCacheDir, convertedImageName)
+		cacheIdentifier := imageCacheIdentifier(convertedImageName)
 		imageTarDest := filepath.Join(registryArtefactsPath(ctx), convertedImageName)
 
-		if fileio.FileExists(imageCacheLocation) && enableCache {
-			_, err = fmt.Fprintf(logFile, "%s found in cache, copying instead of downloading\n", img)
-			if err != nil {
-				return fmt.Errorf("writing to %s: %w", registryLogFileName, err)
-			}
-
-			if err = fileio.CopyFile(imageCacheLocation, imageTarDest, fileio.NonExecutablePerms); err != nil {
-				return fmt.Errorf("copying cached container image: %w", err)
-			}
-		} else {
-			if err = storeImage(img, arch, logFile); err != nil {
-				return fmt.Errorf("adding image to registry store: %w", err)
-			}
-
-			if err = generateRegistryTar(imageTarDest, logFile); err != nil {
-				return fmt.Errorf("generating registry store tarball: %w", err)
-			}
-
-			if cacheImage {
-				if err = fileio.CopyFile(imageTarDest, imageCacheLocation, fileio.NonExecutablePerms); err != nil {
-					return fmt.Errorf("copying container image to cache: %w", err)
-				}
-			}
+		if err = c.fetchRegistryImage(img, arch, cacheIdentifier, imageTarDest, cacheImage, login, output); err != nil {
+			return err
 		}
+
 		if err = bar.Add(1); err != nil {
 			zap.S().Debugf("Error incrementing the progress bar: %s", err)
 		}
 	}
 
+	// Images referenced by digest in the definition are also reported during validation,
+	// while the ones extracted from manifests and Helm charts are only known at this point
 	if len(imagesWithDigest) != 0 {
 		log.Audit("WARNING: Container image(s) with digest detected, please be sure that each digest is a manifest " +
 			"digest (the digest of the container image) and NOT an index digest (the digest of the " +
@@ -317,3 +329,101 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 
 	return nil
 }
+
+// fetchRegistryImage copies the container image tarball from the cache, pulling the image first if necessary.
+// The cache entry is locked throughout, so that concurrent builds sharing the cache wait for an in-flight
+// pull instead of duplicating it.
+func (c *Combustion) fetchRegistryImage(img, arch, cacheIdentifier, imageTarDest string, cacheImage bool, login func() error, output io.Writer) error {
+	enableCache := c.ImageCache != nil
+
+	var cachedImagePath string
+	if enableCache {
+		unlock, err := c.ImageCache.Lock(cacheIdentifier)
+		if err != nil {
+			return fmt.Errorf("locking container image in cache: %w", err)
+		}
+		defer unlock()
+
+		cachedImagePath, err = c.ImageCache.Get(cacheIdentifier)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("retrieving container image from cache: %w", err)
+		}
+	}
+
+	if cachedImagePath != "" {
+		if _, err := fmt.Fprintf(output, "%s found in cache, copying instead of downloading\n", img); err != nil {
+			return fmt.Errorf("writing to registry log file: %w", err)
+		}
+
+		if err := fileio.CopyFile(cachedImagePath, imageTarDest, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying cached container image: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := login(); err != nil {
+		return err
+	}
+
+	if err := c.RegistryStore.AddImage(img, arch, output); err != nil {
+		return fmt.Errorf("adding image to registry store: %w", err)
+	}
+
+	if err := c.RegistryStore.Save(imageTarDest, output); err != nil {
+		return fmt.Errorf("generating registry store tarball: %w", err)
+	}
+
+	if cacheImage {
+		if err := cacheContainerImage(c.ImageCache, cacheIdentifier, img, imageTarDest); err != nil {
+			return fmt.Errorf("copying container image to cache: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// imageCacheIdentifier returns the identifier of the container image tarball in the cache.
+func imageCacheIdentifier(imageTarName string) string {
+	return fmt.Sprintf("images/%s", imageTarName)
+}
+
+// registryImageTarName returns the name of the tarball the container image is stored in.
+// The digest is only included for images using the `latest` tag.
+func registryImageTarName(img, digest string) string {
+	convertedImage := strings.ReplaceAll(img, "/", "_")
+	if digest != "" {
+		return fmt.Sprintf("%s-%s-%s", convertedImage, digest, registryTarSuffix)
+	}
+
+	return fmt.Sprintf("%s-%s", convertedImage, registryTarSuffix)
+}
+
+func isLatestImage(img string) bool {
+	return strings.Contains(img, ":latest")
+}
+
+// ContainerImageCacheIdentifier returns the identifier of the container image tarball in the cache.
+// Images using the `latest` tag are cached under the digest they resolve to at build time,
+// in which case the identifier cannot be determined upfront and false is returned.
+func ContainerImageCacheIdentifier(img string) (string, bool) {
+	if isLatestImage(img) {
+		return "", false
+	}
+
+	return imageCacheIdentifier(registryImageTarName(img, "")), true
+}
+
+func cacheContainerImage(cache imageCache, identifier, img, imageTarPath string) error {
+	file, err := os.Open(imageTarPath)
+	if err != nil {
+		return fmt.Errorf("opening container image tarball: %w", err)
+	}
+	defer file.Close()
+
+	if err = cache.Put(identifier, img, file); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+
+	return nil
+}
@@ -1,13 +1,17 @@
 package combustion
 
 import (
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 )
 
 func TestWriteRegistryScript(t *testing.T) {
@@ -180,3 +184,133 @@ func TestGetImageHostnames(t *testing.T) {
 	// Verify
 	assert.Equal(t, expectedHostnames, hostnames)
 }
+
+func TestPopulateRegistry_NoCredentialsInBuildDir(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	const password = "hauler-s3cr3t-pass"
+	log.RegisterSecrets(password)
+	t.Cleanup(log.ResetSecrets)
+
+	// Stub out hauler with a script which echoes back everything it was provided with
+	binDir := t.TempDir()
+	fakeHauler := "#!/bin/sh\necho \"args: $*\"\necho \"stdin: $(cat)\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, hauler), []byte(fakeHauler), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries = []image.Registry{
+		{
+			URI: "registry.suse.com",
+			Authentication: image.RegistryAuthentication{
+				Username: "user",
+				Password: password,
+			},
+		},
+	}
+
+	c := &Combustion{
+		RegistryStore: Hauler{},
+	}
+
+	// Test
+	err := c.populateRegistry(ctx, []string{"registry.suse.com/hello-world:1.0"})
+
+	// Verify
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.BuildDir, "embedded-registry.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "args: login registry.suse.com --username user --password-stdin")
+	assert.Contains(t, string(contents), "stdin: [REDACTED]")
+
+	err = filepath.WalkDir(ctx.BuildDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		assert.NotContains(t, string(contents), password, "file %s contains the registry password", path)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+type fakeRegistryStore struct {
+	addedImages []string
+	logins      int
+}
+
+func (s *fakeRegistryStore) Login(image.Registry, io.Writer) error {
+	s.logins++
+	return nil
+}
+
+func (s *fakeRegistryStore) AddImage(containerImage, _ string, _ io.Writer) error {
+	s.addedImages = append(s.addedImages, containerImage)
+	return nil
+}
+
+func (s *fakeRegistryStore) Save(imageTarDest string, _ io.Writer) error {
+	return os.WriteFile(imageTarDest, []byte("image-tarball"), 0o600)
+}
+
+func (s *fakeRegistryStore) InstallBinary(string) error {
+	return nil
+}
+
+func TestPopulateRegistry_Cache(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries = []image.Registry{{URI: "registry.suse.com"}}
+	require.NoError(t, os.MkdirAll(registryArtefactsPath(ctx), os.ModePerm))
+
+	imageCache, err := cache.New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	store := &fakeRegistryStore{}
+	c := &Combustion{
+		RegistryStore: store,
+		ImageCache:    imageCache,
+	}
+
+	images := []string{"registry.suse.com/hello-world:1.0"}
+	imageTar := filepath.Join(registryArtefactsPath(ctx), "registry.suse.com_hello-world:1.0-registry.tar.zst")
+
+	// Test
+	require.NoError(t, c.populateRegistry(ctx, images))
+	require.NoError(t, os.Remove(imageTar))
+	require.NoError(t, c.populateRegistry(ctx, images))
+
+	// Verify
+	assert.Equal(t, images, store.addedImages, "the image must only be pulled once")
+	assert.Equal(t, 1, store.logins, "registries must only be logged into when pulling images")
+
+	contents, err := os.ReadFile(imageTar)
+	require.NoError(t, err)
+	assert.Equal(t, "image-tarball", string(contents))
+
+	entries, err := imageCache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "images/registry.suse.com_hello-world:1.0-registry.tar.zst", entries[0].Identifier)
+	assert.Equal(t, "registry.suse.com/hello-world:1.0", entries[0].Source)
+}
+
+func TestContainerImageCacheIdentifier(t *testing.T) {
+	identifier, ok := ContainerImageCacheIdentifier("registry.suse.com/hello-world:1.0")
+	assert.True(t, ok)
+	assert.Equal(t, "images/registry.suse.com_hello-world:1.0-registry.tar.zst", identifier)
+
+	_, ok = ContainerImageCacheIdentifier("hello-world:latest")
+	assert.False(t, ok)
+}
@@ -3,10 +3,7 @@ package combustion
 import (
 	_ "embed"
 	"fmt"
-	"os"
-	"path/filepath"
 
-	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
@@ -21,26 +18,31 @@ const (
 var systemdTemplate string
 
 func configureSystemd(ctx *image.Context) ([]string, error) {
-	// Nothing to do if both lists are empty
-	systemd := ctx.ImageDefinition.OperatingSystem.Systemd
-	if len(systemd.Enable) == 0 && len(systemd.Disable) == 0 {
+	written, err := writeNodeScripts(ctx, systemdScriptName, renderSystemdScript)
+	if err != nil {
+		log.AuditComponentFailed(systemdComponentName)
+		return nil, fmt.Errorf("writing systemd script: %w", err)
+	}
+
+	if !written {
 		log.AuditComponentSkipped(systemdComponentName)
 		return nil, nil
 	}
 
-	data, err := template.Parse(systemdScriptName, systemdTemplate, ctx.ImageDefinition.OperatingSystem.Systemd)
-	if err != nil {
-		log.AuditComponentFailed(systemdComponentName)
-		return nil, fmt.Errorf("applying systemd script template: %w", err)
+	log.AuditComponentSuccessful(systemdComponentName)
+	return []string{systemdScriptName}, nil
+}
+
+func renderSystemdScript(_ string, os *image.OperatingSystem) (string, error) {
+	// Nothing to do if both lists are empty
+	if len(os.Systemd.Enable) == 0 && len(os.Systemd.Disable) == 0 {
+		return "", nil
 	}
 
-	filename := filepath.Join(ctx.CombustionDir, systemdScriptName)
-	err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms)
+	data, err := template.Parse(systemdScriptName, systemdTemplate, os.Systemd)
 	if err != nil {
-		log.AuditComponentFailed(systemdComponentName)
-		return nil, fmt.Errorf("writing systemd combustion file: %w", err)
+		return "", fmt.Errorf("applying systemd script template: %w", err)
 	}
 
-	log.AuditComponentSuccessful(systemdComponentName)
-	return []string{systemdScriptName}, nil
+	return data, nil
 }
@@ -0,0 +1,31 @@
+#!/bin/bash
+set -euo pipefail
+
+GRUB_DEFAULTS=/etc/default/grub
+NODE_ARGS=({{ range . }}{{ shellQuote . }} {{ end }})
+
+read -r -a CURRENT_ARGS <<< "$(sed -n 's/^GRUB_CMDLINE_LINUX_DEFAULT="\(.*\)"$/\1/p' $GRUB_DEFAULTS)"
+
+# Node specific arguments replace the shared arguments with the same key
+ARGS=()
+for ARG in "${CURRENT_ARGS[@]}"; do
+    REPLACED=false
+    for NODE_ARG in "${NODE_ARGS[@]}"; do
+        if [ "${ARG%%=*}" = "${NODE_ARG%%=*}" ]; then
+            REPLACED=true
+            break
+        fi
+    done
+
+    if [ "$REPLACED" = false ]; then
+        ARGS+=("$ARG")
+    fi
+done
+ARGS+=("${NODE_ARGS[@]}")
+
+# The command line is passed through the environment so that no characters of the arguments need escaping
+CMDLINE="${ARGS[*]}" awk '/^GRUB_CMDLINE_LINUX_DEFAULT="/ { print "GRUB_CMDLINE_LINUX_DEFAULT=\"" ENVIRON["CMDLINE"] "\""; next } { print }' \
+    $GRUB_DEFAULTS > $GRUB_DEFAULTS.tmp
+mv $GRUB_DEFAULTS.tmp $GRUB_DEFAULTS
+
+grub2-mkconfig -o /boot/grub2/grub.cfg
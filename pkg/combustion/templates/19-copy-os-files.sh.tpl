@@ -3,6 +3,8 @@ set -euo pipefail
 
 mount /var
 mount /usr/local
-cp -R ./os-files/* /
+{{- range . }}
+cp -R ./{{ . }}/* /
+{{- end }}
 umount /var
 umount /usr/local
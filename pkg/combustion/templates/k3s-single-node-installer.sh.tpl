@@ -5,6 +5,11 @@ mount /var
 
 mkdir -p /var/lib/rancher/k3s/agent/images/
 cp {{ .imagesPath }}/* /var/lib/rancher/k3s/agent/images/
+{{- if .kubeVIPManifest }}
+
+mkdir -p /var/lib/rancher/k3s/agent/pod-manifests/
+cp {{ .kubeVIPManifest }} /var/lib/rancher/k3s/agent/pod-manifests/
+{{- end }}
 
 umount /var
 
@@ -0,0 +1,26 @@
+#!/bin/bash
+set -euo pipefail
+
+HOSTNAME=$(cat /etc/hostname)
+if [ ! "$HOSTNAME" ]; then
+    HOSTNAME=$(cat /proc/sys/kernel/hostname)
+    if [ ! "$HOSTNAME" ] || [ "$HOSTNAME" = "localhost.localdomain" ]; then
+        echo "ERROR: Could not select the node specific configuration due to missing hostname"
+        exit 1
+    fi
+fi
+
+case "$HOSTNAME" in
+{{- range .Nodes }}
+"{{ .Hostname }}")
+    ./{{ .Script }}
+    ;;
+{{- end }}
+*)
+{{- if .DefaultScript }}
+    ./{{ .DefaultScript }}
+{{- else }}
+    echo "No node specific configuration found for host '$HOSTNAME'"
+{{- end }}
+    ;;
+esac
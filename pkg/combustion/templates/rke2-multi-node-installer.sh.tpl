@@ -26,6 +26,14 @@ mount /var
 
 mkdir -p /var/lib/rancher/rke2/agent/images/
 cp {{ .imagesPath }}/* /var/lib/rancher/rke2/agent/images/
+{{- if .kubeVIPManifest }}
+
+# kube-vip serves the API VIPs from the server nodes
+if [ "$NODETYPE" = "server" ]; then
+mkdir -p /var/lib/rancher/rke2/agent/pod-manifests/
+cp {{ .kubeVIPManifest }} /var/lib/rancher/rke2/agent/pod-manifests/
+fi
+{{- end }}
 
 umount /var
 
@@ -88,6 +96,11 @@ systemctl enable kubernetes-resources-install.service
 {{- end }}
 fi
 
+NODECONFIGFILE={{ .configFilePath }}/{{ .nodeConfigDir }}/$HOSTNAME.yaml
+if [ -f "$NODECONFIGFILE" ]; then
+CONFIGFILE=$NODECONFIGFILE
+fi
+
 {{- if and .apiVIP4 .apiHost }}
 echo "{{ .apiVIP4 }} {{ .apiHost }}" >> /etc/hosts
 {{- end }}
@@ -100,7 +113,8 @@ mkdir -p /etc/rancher/rke2/
 cp $CONFIGFILE /etc/rancher/rke2/config.yaml
 
 {{- if .setNodeIPScript }}
-if [ "$NODETYPE" = "server" ]; then
+# The node IP may already be configured for the individual node
+if [ "$NODETYPE" = "server" ] && ! grep -q '^node-ip:' /etc/rancher/rke2/config.yaml; then
 sh {{ .setNodeIPScript }}
 fi
 {{- end }}
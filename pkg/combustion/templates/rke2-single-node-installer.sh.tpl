@@ -5,6 +5,11 @@ mount /var
 
 mkdir -p /var/lib/rancher/rke2/agent/images/
 cp {{ .imagesPath }}/* /var/lib/rancher/rke2/agent/images/
+{{- if .kubeVIPManifest }}
+
+mkdir -p /var/lib/rancher/rke2/agent/pod-manifests/
+cp {{ .kubeVIPManifest }} /var/lib/rancher/rke2/agent/pod-manifests/
+{{- end }}
 
 umount /var
 
@@ -3,10 +3,7 @@ package combustion
 import (
 	_ "embed"
 	"fmt"
-	"os"
-	"path/filepath"
 
-	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
@@ -21,25 +18,31 @@ const (
 var usersScript string
 
 func configureUsers(ctx *image.Context) ([]string, error) {
+	written, err := writeNodeScripts(ctx, usersScriptName, renderUsersScript)
+	if err != nil {
+		log.AuditComponentFailed(usersComponentName)
+		return nil, fmt.Errorf("writing users script: %w", err)
+	}
+
 	// Punch out early if there are no users
-	if len(ctx.ImageDefinition.OperatingSystem.Users) == 0 {
+	if !written {
 		log.AuditComponentSkipped(usersComponentName)
 		return nil, nil
 	}
 
-	data, err := template.Parse(usersScriptName, usersScript, ctx.ImageDefinition.OperatingSystem.Users)
-	if err != nil {
-		log.AuditComponentFailed(usersComponentName)
-		return nil, fmt.Errorf("parsing users script template: %w", err)
+	log.AuditComponentSuccessful(usersComponentName)
+	return []string{usersScriptName}, nil
+}
+
+func renderUsersScript(_ string, os *image.OperatingSystem) (string, error) {
+	if len(os.Users) == 0 {
+		return "", nil
 	}
 
-	filename := filepath.Join(ctx.CombustionDir, usersScriptName)
-	err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms)
+	data, err := template.Parse(usersScriptName, usersScript, os.Users)
 	if err != nil {
-		log.AuditComponentFailed(usersComponentName)
-		return nil, fmt.Errorf("writing %s to the combustion directory: %w", usersScriptName, err)
+		return "", fmt.Errorf("parsing users script template: %w", err)
 	}
 
-	log.AuditComponentSuccessful(usersComponentName)
-	return []string{usersScriptName}, nil
+	return data, nil
 }
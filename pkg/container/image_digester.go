@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/containers/image/v5/manifest"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 )
 
 type imageInspector interface {
@@ -16,6 +17,10 @@ type ImageDigester struct {
 }
 
 func (d *ImageDigester) ImageDigest(img string, arch string) (string, error) {
+	if err := http.AllowNetworkAccess(fmt.Sprintf("inspecting image '%s'", img)); err != nil {
+		return "", err
+	}
+
 	schemas, err := d.ImageInspector.Inspect(img)
 	if err != nil {
 		return "", fmt.Errorf("inspecting image: %w", err)
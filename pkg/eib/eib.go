@@ -25,37 +25,69 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/network"
 	"github.com/suse-edge/edge-image-builder/pkg/registry"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
 	"go.uber.org/zap"
 )
 
 func Run(ctx *image.Context, rootBuildDir string) error {
-	if err := appendKubernetesSELinuxRPMs(ctx); err != nil {
-		log.Auditf("Bootstrapping dependency services failed.")
-		return fmt.Errorf("configuring kubernetes selinux policy: %w", err)
+	artefactCache, err := newCache(ctx)
+	if err != nil {
+		log.Audit("Bootstrapping dependency services failed.")
+		return err
 	}
 
-	appendHelm(ctx)
-	if !ctx.IsConfigDrive {
-		appendElementalRPMs(ctx)
-		appendFIPS(ctx)
+	if err = appendDependencies(ctx, newArtefactDownloader(ctx, artefactCache).DownloadSELinuxRPMsSigningKey); err != nil {
+		log.Auditf("Bootstrapping dependency services failed.")
+		return err
 	}
 
-	c, err := buildCombustion(ctx, rootBuildDir)
+	buildReport := report.New(version.GetEibVersion(), ctx.ArtifactSources)
+	buildReport.KubernetesRelease = ctx.KubernetesRelease
+
+	c, err := buildCombustion(ctx, rootBuildDir, artefactCache, buildReport)
 	if err != nil {
 		log.Audit("Bootstrapping dependency services failed.")
 		return fmt.Errorf("building combustion: %w", err)
 	}
 
 	if !ctx.IsConfigDrive {
-		builder := build.NewBuilder(ctx, c)
-		return builder.Build()
+		err = build.NewBuilder(ctx, c).Build()
+	} else {
+		err = build.NewGenerator(ctx, c).Generate()
+	}
+	if err != nil {
+		return err
+	}
+
+	reportPath := filepath.Join(ctx.BuildDir, report.FileName)
+	if err = buildReport.Write(reportPath); err != nil {
+		log.Audit("Writing the build report failed.")
+		return err
+	}
+
+	log.Auditf("Build report written to: %s", reportPath)
+	return nil
+}
+
+// appendDependencies extends the definition with the packages, charts, container images and kernel arguments
+// required by the configured components.
+func appendDependencies(ctx *image.Context, downloadSigningKey func(gpgKeysDir string) error) error {
+	if err := appendKubernetesSELinuxRPMs(ctx, downloadSigningKey); err != nil {
+		return fmt.Errorf("configuring kubernetes selinux policy: %w", err)
+	}
+
+	appendHelm(ctx)
+	appendContainerImages(ctx)
+	if !ctx.IsConfigDrive {
+		appendElementalRPMs(ctx)
+		appendFIPS(ctx)
 	}
 
-	builder := build.NewGenerator(ctx, c)
-	return builder.Generate()
+	return nil
 }
 
-func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
+func appendKubernetesSELinuxRPMs(ctx *image.Context, downloadSigningKey func(gpgKeysDir string) error) error {
 	if ctx.ImageDefinition.Kubernetes.Version == "" {
 		return nil
 	}
@@ -91,7 +123,7 @@ func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
 		return fmt.Errorf("creating directory '%s': %w", gpgKeysDir, err)
 	}
 
-	if err = kubernetes.DownloadSELinuxRPMsSigningKey(gpgKeysDir); err != nil {
+	if err = downloadSigningKey(gpgKeysDir); err != nil {
 		return fmt.Errorf("downloading signing key: %w", err)
 	}
 
@@ -127,12 +159,6 @@ func appendFIPS(ctx *image.Context) {
 	if fips {
 		log.AuditInfo("FIPS mode is configured. The necessary RPM packages will be downloaded.")
 
-		packages := ctx.ImageDefinition.OperatingSystem.Packages
-		if packages.RegCode == "" && len(packages.AdditionalRepos) > 0 {
-			log.Audit("WARNING: FIPS enabled with no SUSE registration code provided. Package resolution may fail if additional repositories do not contain the `patterns-base-fips` package.")
-			zap.S().Warn("Detected FIPS for installation with no sccRegistrationCode provided")
-		}
-
 		appendRPMs(ctx, nil, combustion.FIPSPackages...)
 		appendKernelArgs(ctx, combustion.FIPSKernelArgs...)
 	}
@@ -156,20 +182,59 @@ func appendHelm(ctx *image.Context) {
 	ctx.ImageDefinition.Kubernetes.Helm.Repositories = append(ctx.ImageDefinition.Kubernetes.Helm.Repositories, componentRepos...)
 }
 
+func appendContainerImages(ctx *image.Context) {
+	for _, containerImage := range combustion.ComponentContainerImages(ctx) {
+		ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages = append(ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages,
+			image.ContainerImage{Name: containerImage})
+	}
+}
+
 func appendKernelArgs(ctx *image.Context, kernelArgs ...string) {
 	kernelArgList := ctx.ImageDefinition.OperatingSystem.KernelArgs
 	kernelArgList = append(kernelArgList, kernelArgs...)
 	ctx.ImageDefinition.OperatingSystem.KernelArgs = kernelArgList
 }
 
-func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion, error) {
+// newCache opens the cache directory of the context. Caching is disabled when no cache directory is set up.
+func newCache(ctx *image.Context) (*cache.Cache, error) {
+	if ctx.CacheDir == "" {
+		return nil, nil
+	}
+
+	c, err := cache.New(ctx.CacheDir, ctx.CacheMaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("initialising cache instance: %w", err)
+	}
+
+	return c, nil
+}
+
+func newArtefactDownloader(ctx *image.Context, c *cache.Cache) kubernetes.ArtefactDownloader {
+	artefactDownloader := kubernetes.ArtefactDownloader{
+		Rke2ReleaseURL: ctx.ArtifactSources.Kubernetes.Rke2.ReleaseURL,
+		K3sReleaseURL:  ctx.ArtifactSources.Kubernetes.K3s.ReleaseURL,
+		Rke2Mirrors:    ctx.ArtifactSources.Kubernetes.Rke2.Mirrors,
+		K3sMirrors:     ctx.ArtifactSources.Kubernetes.K3s.Mirrors,
+		Parallelism:    ctx.DownloadParallelism,
+	}
+
+	if c != nil {
+		artefactDownloader.Cache = c
+	}
+
+	return artefactDownloader
+}
+
+func buildCombustion(ctx *image.Context, rootDir string, c *cache.Cache, buildReport *report.Report) (*combustion.Combustion, error) {
 	combustionHandler := &combustion.Combustion{
 		NetworkConfigGenerator:       network.ConfigGenerator{},
 		NetworkConfiguratorInstaller: network.ConfiguratorInstaller{},
 	}
 
 	if !combustion.SkipRPMComponent(ctx) || combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
-		p, err := podman.New(ctx.BuildDir)
+		p, err := podman.New(ctx.BuildDir, map[string]string{
+			resolver.RegCodeSecretID: ctx.ImageDefinition.OperatingSystem.Packages.RegCode,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("setting up Podman instance: %w", err)
 		}
@@ -191,7 +256,12 @@ func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion
 		if combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
 			helmClient := helm.New(ctx.BuildDir, combustion.HelmCertsPath(ctx))
 
-			combustionHandler.Registry, err = registry.New(ctx, combustion.KubernetesManifestsPath(ctx), helmClient, combustion.HelmValuesPath(ctx))
+			combustionHandler.RegistryStore = combustion.Hauler{}
+			if c != nil {
+				combustionHandler.ImageCache = c
+			}
+
+			combustionHandler.Registry, err = newRegistry(ctx, helmClient, c)
 			if err != nil {
 				return nil, fmt.Errorf("initialising embedded artifact registry: %w", err)
 			}
@@ -199,22 +269,33 @@ func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion
 	}
 
 	if ctx.ImageDefinition.Kubernetes.Version != "" {
-		c, err := cache.New(ctx.CacheDir)
-		if err != nil {
-			return nil, fmt.Errorf("initialising cache instance: %w", err)
+		scriptDownloader := kubernetes.ScriptDownloader{}
+		if c != nil {
+			scriptDownloader.Cache = c
 		}
 
-		combustionHandler.KubernetesScriptDownloader = kubernetes.ScriptDownloader{}
-		combustionHandler.KubernetesArtefactDownloader = kubernetes.ArtefactDownloader{
-			Cache:          c,
-			Rke2ReleaseURL: ctx.ArtifactSources.Kubernetes.Rke2.ReleaseURL,
-			K3sReleaseURL:  ctx.ArtifactSources.Kubernetes.K3s.ReleaseURL,
-		}
+		combustionHandler.KubernetesScriptDownloader = scriptDownloader
+		artefactDownloader := newArtefactDownloader(ctx, c)
+		artefactDownloader.Report = buildReport
+
+		combustionHandler.KubernetesArtefactDownloader = artefactDownloader
 	}
 
 	return combustionHandler, nil
 }
 
+// newRegistry pulls the Helm charts of the embedded artifact registry, using the cache if any.
+func newRegistry(ctx *image.Context, helmClient *helm.Helm, c *cache.Cache) (*registry.Registry, error) {
+	manifestsPath := combustion.KubernetesManifestsPath(ctx)
+	valuesPath := combustion.HelmValuesPath(ctx)
+
+	if c == nil {
+		return registry.New(ctx, manifestsPath, helmClient, nil, valuesPath)
+	}
+
+	return registry.New(ctx, manifestsPath, helmClient, c, valuesPath)
+}
+
 func SetupBuildDirectory(rootDir string) (string, error) {
 	timestamp := time.Now().Format("Jan02_15-04-05")
 	buildDir := filepath.Join(rootDir, fmt.Sprintf("build-%s", timestamp))
@@ -0,0 +1,45 @@
+package eib
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/helm"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+)
+
+// ImageReferences determines the container images a build of the image would embed in the artifact registry
+// along with the reasons for embedding each of them.
+//
+// The manifests and Helm charts are retrieved the same way as during a build, using the cache if any,
+// while the container images themselves are not pulled.
+func ImageReferences(ctx *image.Context) ([]registry.ImageReference, error) {
+	// The SELinux packages are irrelevant to the container images, hence neither is their signing key
+	if err := appendDependencies(ctx, func(string) error { return nil }); err != nil {
+		return nil, err
+	}
+
+	if !combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
+		return nil, nil
+	}
+
+	c, err := newCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	helmClient := helm.New(ctx.BuildDir, combustion.HelmCertsPath(ctx))
+
+	r, err := newRegistry(ctx, helmClient, c)
+	if err != nil {
+		return nil, fmt.Errorf("initialising embedded artifact registry: %w", err)
+	}
+
+	references, err := r.ImageReferences()
+	if err != nil {
+		return nil, fmt.Errorf("extracting container images: %w", err)
+	}
+
+	return references, nil
+}
@@ -0,0 +1,94 @@
+package eib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+)
+
+const imagesManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.27
+---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+spec:
+  repository: registry.example.com/postgres
+  version: "16.4"
+`
+
+func TestImageReferences(t *testing.T) {
+	// Setup
+	configDir := t.TempDir()
+	manifestsDir := filepath.Join(configDir, "kubernetes", "manifests")
+	require.NoError(t, os.MkdirAll(manifestsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(manifestsDir, "app.yaml"), []byte(imagesManifest), 0o600))
+
+	ctx := &image.Context{
+		ImageConfigDir: configDir,
+		BuildDir:       t.TempDir(),
+		ImageDefinition: &image.Definition{
+			EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{
+					{Name: "nginx:1.27"},
+				},
+				ImageRules: []image.ImageRule{
+					{
+						Kind:     "Database",
+						Selector: "spec",
+						Template: "{{ .repository }}:{{ .version }}",
+					},
+				},
+			},
+		},
+		ArtifactSources: &image.ArtifactSources{},
+	}
+
+	// Test
+	references, err := ImageReferences(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []registry.ImageReference{
+		{
+			Name: "nginx:1.27",
+			Reasons: []string{
+				"embeddedArtifactRegistry.images",
+				"manifest 'app.yaml', Deployment 'web'",
+			},
+		},
+		{
+			Name: "registry.example.com/postgres:16.4",
+			Reasons: []string{
+				"manifest 'app.yaml', Database 'db' (image definition rule 'spec' of Database)",
+			},
+		},
+	}, references)
+}
+
+func TestImageReferences_RegistryNotConfigured(t *testing.T) {
+	ctx := &image.Context{
+		ImageConfigDir:  t.TempDir(),
+		BuildDir:        t.TempDir(),
+		ImageDefinition: &image.Definition{},
+		ArtifactSources: &image.ArtifactSources{},
+	}
+
+	references, err := ImageReferences(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, references)
+}
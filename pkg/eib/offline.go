@@ -0,0 +1,183 @@
+package eib
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/helm"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/plan"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+	"go.uber.org/zap"
+)
+
+// MissingArtefact describes an artefact required by a build which is not available offline.
+type MissingArtefact struct {
+	Kind   string
+	Source string
+	// Reason explains why the artefact is not available.
+	Reason string
+}
+
+const notCachedReason = "not found in the cache"
+
+// MissingArtefacts determines the artefacts required by a build of the image which can be served
+// neither from the cache nor from the image configuration directory, so that offline builds are able
+// to report all of them before any work starts.
+//
+// The build is planned on a copy of the context in a scratch directory under the build directory.
+// The container images referenced by manifests and Helm charts are extracted from the cached files,
+// hence those are only checked once all manifests and charts are cached.
+func MissingArtefacts(ctx *image.Context) ([]MissingArtefact, error) {
+	c, err := newCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if c == nil {
+		return nil, fmt.Errorf("the cache is required to look up artefacts offline")
+	}
+
+	scratchDir, err := os.MkdirTemp(ctx.BuildDir, "offline-check-")
+	if err != nil {
+		return nil, fmt.Errorf("creating scratch directory: %w", err)
+	}
+	defer func() {
+		if removeErr := os.RemoveAll(scratchDir); removeErr != nil {
+			zap.S().Warnf("Removing scratch directory '%s' failed: %v", scratchDir, removeErr)
+		}
+	}()
+
+	// Planning extends the definition with the dependencies of the configured components,
+	// which must not leak into the subsequent build
+	definition := *ctx.ImageDefinition
+
+	scratchCtx := *ctx
+	scratchCtx.ImageDefinition = &definition
+	scratchCtx.BuildDir = scratchDir
+
+	scratchCtx.CombustionDir, scratchCtx.ArtefactsDir, err = SetupCombustionDirectory(scratchDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sizes are irrelevant since nothing is going to be downloaded
+	recorder := plan.NewRecorder(func(string) (int64, error) {
+		return plan.UnknownSize, nil
+	})
+
+	p, err := RunPlan(&scratchCtx, recorder)
+	if err != nil {
+		return nil, fmt.Errorf("planning build: %w", err)
+	}
+
+	var missing []MissingArtefact
+	registryContentsCached := true
+
+	for _, artefact := range p.Artefacts {
+		switch artefact.Kind {
+		case plan.KindContainerImage:
+			// Checked along with the images referenced by manifests and Helm charts
+			continue
+		case plan.KindPackages:
+			missing = append(missing, MissingArtefact{
+				Kind:   artefact.Kind,
+				Source: artefact.Source,
+				Reason: "resolving packages requires access to the package repositories",
+			})
+			continue
+		}
+
+		found, err := cacheContains(c, artefact.Identifier)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			continue
+		}
+
+		if artefact.Kind == plan.KindManifest || artefact.Kind == plan.KindHelmChart {
+			registryContentsCached = false
+		}
+
+		missing = append(missing, MissingArtefact{Kind: artefact.Kind, Source: artefact.Source, Reason: notCachedReason})
+	}
+
+	if combustion.IsEmbeddedArtifactRegistryConfigured(&scratchCtx) {
+		missingImages, err := missingContainerImages(&scratchCtx, c, registryContentsCached)
+		if err != nil {
+			return nil, err
+		}
+
+		missing = append(missing, missingImages...)
+	}
+
+	return missing, nil
+}
+
+func missingContainerImages(ctx *image.Context, c *cache.Cache, registryContentsCached bool) ([]MissingArtefact, error) {
+	var missing []MissingArtefact
+	var images []string
+
+	if registryContentsCached {
+		helmClient := helm.New(ctx.BuildDir, combustion.HelmCertsPath(ctx))
+
+		r, err := registry.New(ctx, combustion.KubernetesManifestsPath(ctx), helmClient, c, combustion.HelmValuesPath(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("initialising embedded artifact registry: %w", err)
+		}
+
+		if images, err = r.ContainerImages(); err != nil {
+			return nil, fmt.Errorf("extracting container images: %w", err)
+		}
+	} else {
+		for _, img := range ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages {
+			images = append(images, img.Name)
+		}
+
+		missing = append(missing, MissingArtefact{
+			Kind:   plan.KindContainerImage,
+			Source: "images referenced by manifests and Helm charts",
+			Reason: "unknown until the manifests and Helm charts are cached",
+		})
+	}
+
+	for _, img := range images {
+		identifier, ok := combustion.ContainerImageCacheIdentifier(img)
+		if !ok {
+			missing = append(missing, MissingArtefact{
+				Kind:   plan.KindContainerImage,
+				Source: img,
+				Reason: "the digest of images using the latest tag cannot be resolved offline",
+			})
+			continue
+		}
+
+		found, err := cacheContains(c, identifier)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			missing = append(missing, MissingArtefact{Kind: plan.KindContainerImage, Source: img, Reason: notCachedReason})
+		}
+	}
+
+	return missing, nil
+}
+
+func cacheContains(c *cache.Cache, identifier string) (bool, error) {
+	if identifier == "" {
+		return false, nil
+	}
+
+	found, err := c.Contains(identifier)
+	if err != nil {
+		return false, fmt.Errorf("looking up cache: %w", err)
+	}
+
+	return found, nil
+}
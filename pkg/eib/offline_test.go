@@ -0,0 +1,101 @@
+package eib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/plan"
+)
+
+const (
+	offlineReleaseURL  = "https://releases.example.com"
+	offlineManifestURL = "https://manifests.example.com/app.yaml"
+	offlineManifest    = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: registry.suse.com/app:1.0
+`
+)
+
+func offlineContext(t *testing.T) *image.Context {
+	ctx := prefetchContext(t, offlineReleaseURL)
+	ctx.CacheDir = t.TempDir()
+	ctx.ImageDefinition.Kubernetes.Manifests.URLs = []string{offlineManifestURL}
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages = []image.ContainerImage{
+		{Name: "nginx:1.27"},
+		{Name: "busybox:latest"},
+	}
+
+	return ctx
+}
+
+func TestMissingArtefacts_EmptyCache(t *testing.T) {
+	// Setup
+	ctx := offlineContext(t)
+
+	// Test
+	missing, err := MissingArtefacts(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	artefactURL := offlineReleaseURL + "/v1.30.3%2Brke2r1/"
+	assert.ElementsMatch(t, []MissingArtefact{
+		{Kind: plan.KindKubernetesArtefact, Source: artefactURL + "rke2-images-core.linux-amd64.tar.zst", Reason: notCachedReason},
+		{Kind: plan.KindKubernetesArtefact, Source: artefactURL + "rke2-images-cilium.linux-amd64.tar.zst", Reason: notCachedReason},
+		{Kind: plan.KindKubernetesArtefact, Source: artefactURL + "rke2.linux-amd64.tar.gz", Reason: notCachedReason},
+		{Kind: plan.KindKubernetesArtefact, Source: artefactURL + "sha256sum-amd64.txt", Reason: notCachedReason},
+		{Kind: plan.KindInstallScript, Source: "https://get.rke2.io", Reason: notCachedReason},
+		{Kind: plan.KindManifest, Source: offlineManifestURL, Reason: notCachedReason},
+		{Kind: plan.KindPackages, Source: "vim", Reason: "resolving packages requires access to the package repositories"},
+		{Kind: plan.KindContainerImage, Source: "images referenced by manifests and Helm charts", Reason: "unknown until the manifests and Helm charts are cached"},
+		{Kind: plan.KindContainerImage, Source: "nginx:1.27", Reason: notCachedReason},
+		{Kind: plan.KindContainerImage, Source: "busybox:latest", Reason: "the digest of images using the latest tag cannot be resolved offline"},
+	}, missing)
+
+	// The definition used by the subsequent build is left untouched
+	assert.Equal(t, []string{"vim"}, ctx.ImageDefinition.OperatingSystem.Packages.PKGList)
+}
+
+func TestMissingArtefacts_WarmCache(t *testing.T) {
+	// Setup
+	ctx := offlineContext(t)
+
+	c, err := cache.New(ctx.CacheDir, 0)
+	require.NoError(t, err)
+
+	for identifier, contents := range map[string]string{
+		"v1.30.3+rke2r1/rke2-images-core.linux-amd64.tar.zst":   "core",
+		"v1.30.3+rke2r1/rke2-images-cilium.linux-amd64.tar.zst": "cilium",
+		"v1.30.3+rke2r1/rke2.linux-amd64.tar.gz":                "rke2",
+		"v1.30.3+rke2r1/sha256sum-amd64.txt":                    "checksums",
+		"scripts/rke2_installer.sh":                             "#!/bin/sh",
+		"manifests/manifests.example.com/app.yaml":              offlineManifest,
+		"images/nginx:1.27-registry.tar.zst":                    "nginx",
+	} {
+		require.NoError(t, c.Put(identifier, "test", strings.NewReader(contents)))
+	}
+
+	// Test
+	missing, err := MissingArtefacts(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	// The images referenced by the cached manifest are checked as well
+	assert.ElementsMatch(t, []MissingArtefact{
+		{Kind: plan.KindPackages, Source: "vim", Reason: "resolving packages requires access to the package repositories"},
+		{Kind: plan.KindContainerImage, Source: "registry.suse.com/app:1.0", Reason: notCachedReason},
+		{Kind: plan.KindContainerImage, Source: "busybox:latest", Reason: "the digest of images using the latest tag cannot be resolved offline"},
+	}, missing)
+}
@@ -0,0 +1,114 @@
+package eib
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"github.com/suse-edge/edge-image-builder/pkg/network"
+	"github.com/suse-edge/edge-image-builder/pkg/plan"
+)
+
+// Plan describes what a build of the image would produce.
+type Plan struct {
+	// Scripts is the ordered list of scripts executed by the Combustion script.
+	Scripts []string
+	// CombustionFiles lists the files in the combustion directory, relative to it.
+	CombustionFiles []string
+	// ArtefactFiles lists the files in the artefacts directory, relative to it.
+	ArtefactFiles []string
+	// Artefacts lists everything that would be downloaded or pulled.
+	Artefacts []plan.Artefact
+	// Unresolved lists the build outputs which are only known once the manifests and Helm charts are retrieved.
+	Unresolved []string
+}
+
+// RunPlan configures all Combustion components the same way as a build would,
+// replacing the downloaders, the RPM resolver, the embedded registry and the image digester
+// with stubs which only record the artefacts they would retrieve.
+func RunPlan(ctx *image.Context, recorder *plan.Recorder) (*Plan, error) {
+	recordSigningKey := func(gpgKeysDir string) error {
+		recorder.RecordSigningKey(kubernetes.SELinuxRPMsSigningKeyURL, filepath.Join(gpgKeysDir, kubernetes.SELinuxRPMsSigningKeyFile))
+		return nil
+	}
+
+	if err := appendDependencies(ctx, recordSigningKey); err != nil {
+		return nil, err
+	}
+
+	c := planCombustion(ctx, recorder)
+
+	scripts, err := c.ConfigureScripts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("configuring combustion: %w", err)
+	}
+
+	combustionFiles, err := listFiles(ctx.CombustionDir)
+	if err != nil {
+		return nil, fmt.Errorf("listing combustion files: %w", err)
+	}
+
+	artefactFiles, err := listFiles(ctx.ArtefactsDir)
+	if err != nil {
+		return nil, fmt.Errorf("listing artefact files: %w", err)
+	}
+
+	return &Plan{
+		Scripts:         scripts,
+		CombustionFiles: combustionFiles,
+		ArtefactFiles:   artefactFiles,
+		Artefacts:       recorder.Artefacts(),
+		Unresolved:      recorder.Unresolved(),
+	}, nil
+}
+
+func planCombustion(ctx *image.Context, recorder *plan.Recorder) *combustion.Combustion {
+	c := &combustion.Combustion{
+		NetworkConfigGenerator:       network.ConfigGenerator{},
+		NetworkConfiguratorInstaller: network.ConfiguratorInstaller{},
+		ImageDigester:                plan.ImageDigester{},
+		RPMResolver:                  plan.RPMResolver{Recorder: recorder},
+		RPMRepoCreator:               plan.RPMRepoCreator{},
+	}
+
+	if combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
+		c.Registry = plan.NewRegistry(ctx, combustion.KubernetesManifestsPath(ctx), recorder)
+		c.RegistryStore = plan.RegistryStore{Recorder: recorder}
+	}
+
+	if ctx.ImageDefinition.Kubernetes.Version != "" {
+		downloader := plan.KubernetesDownloader{
+			Recorder:       recorder,
+			RKE2ReleaseURL: ctx.ArtifactSources.Kubernetes.Rke2.ReleaseURL,
+			K3sReleaseURL:  ctx.ArtifactSources.Kubernetes.K3s.ReleaseURL,
+		}
+
+		c.KubernetesScriptDownloader = downloader
+		c.KubernetesArtefactDownloader = downloader
+	}
+
+	return c
+}
+
+func listFiles(root string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, relPath)
+		return nil
+	})
+
+	return files, err
+}
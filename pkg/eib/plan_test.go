@@ -0,0 +1,95 @@
+package eib
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/plan"
+)
+
+func TestRunPlan(t *testing.T) {
+	// Setup
+	buildDir := t.TempDir()
+
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		BuildDir:       buildDir,
+		CombustionDir:  filepath.Join(buildDir, "combustion"),
+		ArtefactsDir:   filepath.Join(buildDir, "artefacts"),
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType: image.TypeISO,
+				Arch:      image.ArchTypeX86,
+			},
+			OperatingSystem: image.OperatingSystem{
+				Users: []image.OperatingSystemUser{
+					{Username: "alpha", EncryptedPassword: "alpha123"},
+				},
+				Packages: image.Packages{
+					PKGList: []string{"vim"},
+					AdditionalRepos: []image.AddRepo{
+						{URL: "https://repo.suse.com"},
+					},
+				},
+			},
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+			},
+			EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{
+					{Name: "nginx:1.25"},
+				},
+			},
+		},
+		ArtifactSources: &image.ArtifactSources{},
+	}
+	ctx.ArtifactSources.Kubernetes.Rke2.ReleaseURL = "https://rke2.suse.com/releases"
+
+	require.NoError(t, os.MkdirAll(ctx.CombustionDir, os.ModePerm))
+	require.NoError(t, os.MkdirAll(ctx.ArtefactsDir, os.ModePerm))
+
+	recorder := plan.NewRecorder(func(url string) (int64, error) {
+		if url == "https://rke2.suse.com/releases/v1.30.3%2Brke2r1/rke2-images-core.linux-amd64.tar.zst" {
+			return 1024, nil
+		}
+
+		return 0, errors.New("unreachable")
+	})
+
+	// Test
+	p, err := RunPlan(ctx, recorder)
+
+	// Verify
+	require.NoError(t, err)
+
+	assert.Contains(t, p.Scripts, "13b-add-users.sh")
+	assert.Contains(t, p.Scripts, "10-rpm-install.sh")
+	assert.Contains(t, p.Scripts, "26-embedded-registry.sh")
+	assert.Contains(t, p.Scripts, "20-k8s-install.sh")
+
+	assert.Contains(t, p.CombustionFiles, "script")
+	assert.Contains(t, p.CombustionFiles, "13b-add-users.sh")
+
+	assert.Contains(t, p.ArtefactFiles, filepath.Join("kubernetes", "images", "rke2-images-core.linux-amd64.tar.zst"))
+	assert.Contains(t, p.ArtefactFiles, filepath.Join("kubernetes", "rke2_installer.sh"))
+	assert.Contains(t, p.ArtefactFiles, filepath.Join("registry", "hauler"))
+
+	var kinds []string
+	for _, a := range p.Artefacts {
+		kinds = append(kinds, a.Kind)
+
+		if a.Source == "https://rke2.suse.com/releases/v1.30.3%2Brke2r1/rke2-images-core.linux-amd64.tar.zst" {
+			assert.Equal(t, int64(1024), a.Size)
+		}
+	}
+
+	assert.Contains(t, kinds, plan.KindInstallScript)
+	assert.Contains(t, kinds, plan.KindKubernetesArtefact)
+	assert.Contains(t, kinds, plan.KindContainerImage)
+	assert.Contains(t, kinds, plan.KindPackages)
+}
@@ -0,0 +1,120 @@
+package eib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"sync"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/container"
+	"github.com/suse-edge/edge-image-builder/pkg/helm"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"github.com/suse-edge/edge-image-builder/pkg/plan"
+	"github.com/suse-edge/edge-image-builder/pkg/podman"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+)
+
+// Prefetch retrieves the artefacts which a build of the image stores in the cache: the Kubernetes artefacts
+// and install scripts, the SELinux RPMs signing key, the manifests, the Helm charts and the container images
+// of the embedded artifact registry.
+// The remaining components are configured with the planning stubs, so that no packages are resolved
+// and nothing else is downloaded.
+//
+// Returns the identifiers of the cache entries used by the build. Since artefacts are downloaded concurrently,
+// the identifiers are not ordered.
+func Prefetch(ctx *image.Context, c *cache.Cache) ([]string, error) {
+	recordingCache := &usageRecordingCache{cache: c}
+
+	downloader := newArtefactDownloader(ctx, nil)
+	downloader.Cache = recordingCache
+
+	if err := appendDependencies(ctx, downloader.DownloadSELinuxRPMsSigningKey); err != nil {
+		return nil, err
+	}
+
+	// Sizes are irrelevant since nothing is reported about the recorded artefacts
+	recorder := plan.NewRecorder(func(string) (int64, error) {
+		return plan.UnknownSize, nil
+	})
+
+	combustionHandler := planCombustion(ctx, recorder)
+
+	if ctx.ImageDefinition.Kubernetes.Version != "" {
+		combustionHandler.KubernetesScriptDownloader = kubernetes.ScriptDownloader{Cache: recordingCache}
+		combustionHandler.KubernetesArtefactDownloader = downloader
+	}
+
+	if combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
+		p, err := podman.New(ctx.BuildDir, nil)
+		if err != nil {
+			return nil, fmt.Errorf("setting up Podman instance: %w", err)
+		}
+
+		combustionHandler.ImageDigester = &container.ImageDigester{ImageInspector: p}
+		combustionHandler.RegistryStore = combustion.Hauler{}
+		combustionHandler.ImageCache = recordingCache
+
+		helmClient := helm.New(ctx.BuildDir, combustion.HelmCertsPath(ctx))
+		combustionHandler.Registry, err = registry.New(ctx, combustion.KubernetesManifestsPath(ctx), helmClient, recordingCache, combustion.HelmValuesPath(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("initialising embedded artifact registry: %w", err)
+		}
+	}
+
+	if _, err := combustionHandler.ConfigureScripts(ctx); err != nil {
+		return nil, fmt.Errorf("configuring combustion: %w", err)
+	}
+
+	return recordingCache.identifiers(), nil
+}
+
+// usageRecordingCache records the identifiers of the entries which are retrieved from or stored in the cache.
+type usageRecordingCache struct {
+	cache *cache.Cache
+
+	mu   sync.Mutex
+	used []string
+}
+
+func (r *usageRecordingCache) Lock(identifier string) (unlock func(), err error) {
+	return r.cache.Lock(identifier)
+}
+
+func (r *usageRecordingCache) Get(identifier string) (string, error) {
+	path, err := r.cache.Get(identifier)
+	if err == nil {
+		r.record(identifier)
+	}
+
+	return path, err
+}
+
+func (r *usageRecordingCache) Put(identifier, source string, reader io.Reader) error {
+	err := r.cache.Put(identifier, source, reader)
+	if err == nil || errors.Is(err, fs.ErrExist) {
+		r.record(identifier)
+	}
+
+	return err
+}
+
+func (r *usageRecordingCache) record(identifier string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.Contains(r.used, identifier) {
+		r.used = append(r.used, identifier)
+	}
+}
+
+func (r *usageRecordingCache) identifiers() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.used)
+}
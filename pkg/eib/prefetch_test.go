@@ -0,0 +1,105 @@
+package eib
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func prefetchContext(t *testing.T, releaseURL string) *image.Context {
+	buildDir := t.TempDir()
+
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		BuildDir:       buildDir,
+		CombustionDir:  filepath.Join(buildDir, "combustion"),
+		ArtefactsDir:   filepath.Join(buildDir, "artefacts"),
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType: image.TypeISO,
+				Arch:      image.ArchTypeX86,
+			},
+			OperatingSystem: image.OperatingSystem{
+				Packages: image.Packages{
+					PKGList: []string{"vim"},
+				},
+			},
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+			},
+		},
+		ArtifactSources: &image.ArtifactSources{},
+	}
+	ctx.ArtifactSources.Kubernetes.Rke2.ReleaseURL = releaseURL
+
+	require.NoError(t, os.MkdirAll(ctx.CombustionDir, os.ModePerm))
+	require.NoError(t, os.MkdirAll(ctx.ArtefactsDir, os.ModePerm))
+
+	return ctx
+}
+
+func TestPrefetch(t *testing.T) {
+	// Setup
+	const contents = "artefact"
+	checksum := sha256.Sum256([]byte(contents))
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if path.Base(r.URL.Path) != "sha256sum-amd64.txt" {
+			_, _ = w.Write([]byte(contents))
+			return
+		}
+
+		for _, artefact := range []string{"rke2-images-core.linux-amd64.tar.zst", "rke2-images-cilium.linux-amd64.tar.zst", "rke2.linux-amd64.tar.gz"} {
+			_, _ = fmt.Fprintf(w, "%x  %s\n", checksum, artefact)
+		}
+	}))
+	defer server.Close()
+
+	c, err := cache.New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	// The install script is served from its upstream location, so it has to be cached upfront
+	require.NoError(t, c.Put("scripts/rke2_installer.sh", "https://get.rke2.io", strings.NewReader("#!/bin/sh")))
+
+	// Test
+	identifiers, err := Prefetch(prefetchContext(t, server.URL), c)
+	require.NoError(t, err)
+
+	// Verify
+	expectedIdentifiers := []string{
+		"v1.30.3+rke2r1/rke2-images-core.linux-amd64.tar.zst",
+		"v1.30.3+rke2r1/rke2-images-cilium.linux-amd64.tar.zst",
+		"v1.30.3+rke2r1/rke2.linux-amd64.tar.gz",
+		"v1.30.3+rke2r1/sha256sum-amd64.txt",
+		"scripts/rke2_installer.sh",
+	}
+	for _, identifier := range expectedIdentifiers {
+		assert.Contains(t, identifiers, identifier)
+	}
+	assert.Equal(t, len(identifiers)-1, requests)
+
+	entries, err := c.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, len(identifiers))
+
+	// Artefacts which are already cached are not downloaded again, but still reported
+	server.Close()
+
+	cachedIdentifiers, err := Prefetch(prefetchContext(t, server.URL), c)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, identifiers, cachedIdentifiers)
+}
@@ -10,7 +10,9 @@ import (
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
@@ -46,6 +48,10 @@ func chartPath(repoName, repoURL, chart string) string {
 }
 
 func (h *Helm) AddRepo(repo *image.HelmRepository) error {
+	if err := http.AllowNetworkAccess(fmt.Sprintf("adding helm repository '%s'", repo.URL)); err != nil {
+		return err
+	}
+
 	logFile := filepath.Join(h.outputDir, repoAddLogFileName)
 
 	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
@@ -58,9 +64,15 @@ func (h *Helm) AddRepo(repo *image.HelmRepository) error {
 		}
 	}()
 
-	cmd := addRepoCommand(repo, h.certsDir, file)
+	output := log.NewRedactingWriter(file)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Flushing %s file failed: %s", logFile, err)
+		}
+	}()
+	cmd := addRepoCommand(repo, h.certsDir, output)
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(output, "command: %s\n", cmd); err != nil {
 		return fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
@@ -72,10 +84,10 @@ func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Write
 	args = append(args, "repo", "add", repo.Name, repo.URL)
 
 	if repo.Authentication.Username != "" && repo.Authentication.Password != "" {
-		args = append(args, "--username", repo.Authentication.Username, "--password", repo.Authentication.Password)
+		args = append(args, "--username", repo.Authentication.Username, "--password-stdin")
 	}
 
-	if repo.SkipTLSVerify {
+	if skipTLSVerify(repo) {
 		args = append(args, "--insecure-skip-tls-verify")
 	} else if repo.CAFile != "" {
 		caFilePath := filepath.Join(certsDir, repo.CAFile)
@@ -83,6 +95,8 @@ func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Write
 	}
 
 	cmd := exec.Command("helm", args...)
+	cmd.Env = http.CommandEnv()
+	cmd.Stdin = passwordStdin(repo)
 	cmd.Stdout = output
 	cmd.Stderr = output
 
@@ -90,6 +104,10 @@ func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Write
 }
 
 func (h *Helm) RegistryLogin(repo *image.HelmRepository) error {
+	if err := http.AllowNetworkAccess(fmt.Sprintf("logging into helm registry '%s'", repo.URL)); err != nil {
+		return err
+	}
+
 	logFile := filepath.Join(h.outputDir, registryLoginFileName)
 
 	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
@@ -107,9 +125,15 @@ func (h *Helm) RegistryLogin(repo *image.HelmRepository) error {
 		return fmt.Errorf("getting host url: %w", err)
 	}
 
-	cmd := registryLoginCommand(host, repo, h.certsDir, file)
+	output := log.NewRedactingWriter(file)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Flushing %s file failed: %s", logFile, err)
+		}
+	}()
+	cmd := registryLoginCommand(host, repo, h.certsDir, output)
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(output, "command: %s\n", cmd); err != nil {
 		return fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
@@ -121,10 +145,10 @@ func registryLoginCommand(host string, repo *image.HelmRepository, certsDir stri
 	args = append(args, "registry", "login", host)
 
 	if repo.Authentication.Username != "" && repo.Authentication.Password != "" {
-		args = append(args, "--username", repo.Authentication.Username, "--password", repo.Authentication.Password)
+		args = append(args, "--username", repo.Authentication.Username, "--password-stdin")
 	}
 
-	if repo.SkipTLSVerify || repo.PlainHTTP {
+	if skipTLSVerify(repo) || repo.PlainHTTP {
 		args = append(args, "--insecure")
 	} else if repo.CAFile != "" {
 		caFilePath := filepath.Join(certsDir, repo.CAFile)
@@ -132,13 +156,35 @@ func registryLoginCommand(host string, repo *image.HelmRepository, certsDir stri
 	}
 
 	cmd := exec.Command("helm", args...)
+	cmd.Env = http.CommandEnv()
+	cmd.Stdin = passwordStdin(repo)
 	cmd.Stdout = output
 	cmd.Stderr = output
 
 	return cmd
 }
 
+// skipTLSVerify reports whether the certificates of the repository are not verified, either as configured
+// for the repository itself or for its host. A CA file configured for the repository takes precedence over the latter.
+func skipTLSVerify(repo *image.HelmRepository) bool {
+	return repo.SkipTLSVerify || (repo.CAFile == "" && http.SkipTLSVerify(repo.URL))
+}
+
+// passwordStdin provides the repository password to commands using the `--password-stdin` flag
+// in order to avoid exposing it as part of the command line.
+func passwordStdin(repo *image.HelmRepository) io.Reader {
+	if repo.Authentication.Username == "" || repo.Authentication.Password == "" {
+		return nil
+	}
+
+	return strings.NewReader(repo.Authentication.Password)
+}
+
 func (h *Helm) Pull(chart string, repo *image.HelmRepository, version, destDir string) (string, error) {
+	if err := http.AllowNetworkAccess(fmt.Sprintf("pulling helm chart '%s'", chart)); err != nil {
+		return "", err
+	}
+
 	logFile := filepath.Join(h.outputDir, pullLogFileName)
 
 	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
@@ -156,9 +202,15 @@ func (h *Helm) Pull(chart string, repo *image.HelmRepository, version, destDir s
 		return "", fmt.Errorf("creating chart dir %q: %w", chartDir, err)
 	}
 
-	cmd := pullCommand(chart, repo, version, chartDir, h.certsDir, file)
+	output := log.NewRedactingWriter(file)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Flushing %s file failed: %s", logFile, err)
+		}
+	}()
+	cmd := pullCommand(chart, repo, version, chartDir, h.certsDir, output)
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(output, "command: %s\n", cmd); err != nil {
 		return "", fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
@@ -193,7 +245,7 @@ func pullCommand(chart string, repo *image.HelmRepository, version, destDir, cer
 	}
 
 	switch {
-	case repo.SkipTLSVerify:
+	case skipTLSVerify(repo):
 		args = append(args, "--insecure-skip-tls-verify")
 	case repo.PlainHTTP:
 		args = append(args, "--plain-http")
@@ -203,6 +255,7 @@ func pullCommand(chart string, repo *image.HelmRepository, version, destDir, cer
 	}
 
 	cmd := exec.Command("helm", args...)
+	cmd.Env = http.CommandEnv()
 
 	cmd.Stdout = output
 	cmd.Stderr = output
@@ -210,7 +263,7 @@ func pullCommand(chart string, repo *image.HelmRepository, version, destDir, cer
 	return cmd
 }
 
-func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string) ([]map[string]any, error) {
+func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
 	logFile := filepath.Join(h.outputDir, templateLogFileName)
 
 	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
@@ -223,10 +276,17 @@ func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion,
 		}
 	}()
 
+	output := log.NewRedactingWriter(file)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Flushing %s file failed: %s", logFile, err)
+		}
+	}()
+
 	chartContentsBuffer := new(strings.Builder)
-	cmd := templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace, apiVersions, io.MultiWriter(file, chartContentsBuffer), file)
+	cmd := templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace, apiVersions, includeCRDs, io.MultiWriter(output, chartContentsBuffer), output)
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(output, "command: %s\n", cmd); err != nil {
 		return nil, fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
@@ -243,9 +303,14 @@ func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion,
 	return resources, nil
 }
 
-func templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, stdout, stderr io.Writer) *exec.Cmd {
+func templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool, stdout, stderr io.Writer) *exec.Cmd {
+	crdsFlag := "--skip-crds"
+	if includeCRDs {
+		crdsFlag = "--include-crds"
+	}
+
 	var args []string
-	args = append(args, "template", "--skip-crds", chart, repository)
+	args = append(args, "template", crdsFlag, chart, repository)
 
 	if targetNamespace != "" {
 		args = append(args, "--namespace", targetNamespace)
@@ -2,11 +2,17 @@ package helm
 
 import (
 	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 )
 
 const (
@@ -83,8 +89,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"https://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 			},
 		},
 		{
@@ -106,8 +111,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"https://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--insecure-skip-tls-verify",
 			},
 		},
@@ -130,8 +134,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"http://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 			},
 		},
 		{
@@ -153,8 +156,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"https://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--ca-file",
 				"certs/suse-edge.crt",
 			},
@@ -170,10 +172,47 @@ func TestAddRepoCommand(t *testing.T) {
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
 			assert.Equal(t, &buf, cmd.Stderr)
+			assertPasswordStdin(t, test.repo, cmd.Stdin)
 		})
 	}
 }
 
+func TestHelmCommands_HostTLSSettings(t *testing.T) {
+	// Setup
+	settings := http.TLSSettings{
+		Hosts: map[string]http.HostTLSSettings{
+			"charts.example.com": {InsecureSkipVerify: true},
+		},
+	}
+	require.NoError(t, http.ConfigureTLS(settings, t.TempDir()))
+	t.Cleanup(func() {
+		require.NoError(t, http.ConfigureTLS(http.TLSSettings{}, ""))
+	})
+
+	insecureRepo := &image.HelmRepository{
+		Name: "internal",
+		URL:  "https://charts.example.com/stable",
+	}
+	secureRepo := &image.HelmRepository{
+		Name: "suse-edge",
+		URL:  "https://suse-edge.github.io/charts",
+	}
+
+	var buf bytes.Buffer
+
+	// Test
+	addRepoArgs := addRepoCommand(insecureRepo, certsDir, &buf).Args
+	loginArgs := registryLoginCommand("charts.example.com", insecureRepo, certsDir, &buf).Args
+	pullArgs := pullCommand("chart", insecureRepo, "", "", certsDir, &buf).Args
+	secureArgs := addRepoCommand(secureRepo, certsDir, &buf).Args
+
+	// Verify
+	assert.Contains(t, addRepoArgs, "--insecure-skip-tls-verify")
+	assert.Contains(t, loginArgs, "--insecure")
+	assert.Contains(t, pullArgs, "--insecure-skip-tls-verify")
+	assert.NotContains(t, secureArgs, "--insecure-skip-tls-verify")
+}
+
 func TestRegistryLoginCommand(t *testing.T) {
 
 	tests := []struct {
@@ -200,8 +239,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 			},
 		},
 		{
@@ -223,8 +261,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--insecure",
 			},
 		},
@@ -247,8 +284,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--insecure",
 			},
 		},
@@ -271,8 +307,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--ca-file",
 				"certs/apache.crt",
 			},
@@ -288,6 +323,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
 			assert.Equal(t, &buf, cmd.Stderr)
+			assertPasswordStdin(t, test.repo, cmd.Stdin)
 		})
 	}
 }
@@ -506,6 +542,7 @@ func TestTemplateCommand(t *testing.T) {
 		kubeVersion     string
 		targetNamespace string
 		valuesPath      string
+		includeCRDs     bool
 		expectedArgs    []string
 	}{
 		{
@@ -550,6 +587,22 @@ func TestTemplateCommand(t *testing.T) {
 				"v1.30.3+rke2r1",
 			},
 		},
+		{
+			name:        "Template including CRDs",
+			repo:        "suse-edge/kubevirt",
+			chart:       "kubevirt",
+			kubeVersion: "v1.30.3+rke2r1",
+			includeCRDs: true,
+			expectedArgs: []string{
+				"helm",
+				"template",
+				"--include-crds",
+				"kubevirt",
+				"suse-edge/kubevirt",
+				"--kube-version",
+				"v1.30.3+rke2r1",
+			},
+		},
 	}
 
 	var stdout bytes.Buffer
@@ -557,7 +610,7 @@ func TestTemplateCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := templateCommand(test.chart, test.repo, test.version, test.valuesPath, test.kubeVersion, test.targetNamespace, test.apiVersions, &stdout, &stderr)
+			cmd := templateCommand(test.chart, test.repo, test.version, test.valuesPath, test.kubeVersion, test.targetNamespace, test.apiVersions, test.includeCRDs, &stdout, &stderr)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &stdout, cmd.Stdout)
@@ -658,3 +711,66 @@ metadata:
 		},
 	}, resources[1])
 }
+
+func assertPasswordStdin(t *testing.T, repo *image.HelmRepository, stdin io.Reader) {
+	if repo.Authentication.Password == "" {
+		assert.Nil(t, stdin)
+		return
+	}
+
+	require.NotNil(t, stdin)
+
+	password, err := io.ReadAll(stdin)
+	require.NoError(t, err)
+	assert.Equal(t, repo.Authentication.Password, string(password))
+}
+
+func TestHelmCommands_NoCredentialsInBuildDir(t *testing.T) {
+	// Setup
+	const password = "helm-s3cr3t-pass"
+	log.RegisterSecrets(password)
+	t.Cleanup(log.ResetSecrets)
+
+	// Stub out helm with a script which echoes back everything it was provided with
+	binDir := t.TempDir()
+	fakeHelm := "#!/bin/sh\necho \"args: $*\"\necho \"stdin: $(cat)\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "helm"), []byte(fakeHelm), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	buildDir := t.TempDir()
+	h := New(buildDir, certsDir)
+
+	repo := &image.HelmRepository{
+		Name: "apache-repo",
+		URL:  "oci://registry-1.docker.io/bitnamicharts",
+		Authentication: image.HelmAuthentication{
+			Username: "user",
+			Password: password,
+		},
+	}
+
+	// Test
+	require.NoError(t, h.AddRepo(repo))
+	require.NoError(t, h.RegistryLogin(repo))
+
+	// Verify
+	contents, err := os.ReadFile(filepath.Join(buildDir, registryLoginFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "--password-stdin")
+	assert.Contains(t, string(contents), "stdin: [REDACTED]")
+
+	err = filepath.WalkDir(buildDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		assert.NotContains(t, string(contents), password, "file %s contains the repository password", path)
+		return nil
+	})
+	require.NoError(t, err)
+}
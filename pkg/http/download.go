@@ -2,70 +2,352 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/schollz/progressbar/v3"
-	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"go.uber.org/zap"
 )
 
+// retryPolicy controls how often failed requests to a single URL are attempted
+// and how long to wait in between.
+type retryPolicy struct {
+	attempts       int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+var downloadRetryPolicy = retryPolicy{
+	attempts:       5,
+	initialBackoff: time.Second,
+	maxBackoff:     30 * time.Second,
+}
+
+// backoff returns the delay before the given retry, doubling with every attempt.
+func (p retryPolicy) backoff(retry int) time.Duration {
+	delay := p.initialBackoff
+	for i := 1; i < retry && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.maxBackoff)
+}
+
+// permanentError marks failures which are not resolved by retrying the same URL.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
 // DownloadFile downloads a file from the specified URL and stores it to the given path.
 //
+// Failed requests, server errors and interrupted transfers are retried with an exponential backoff.
+// Interrupted transfers are resumed from where they stopped, provided the server supports range requests.
+// Once all attempts for a URL are exhausted, the given mirrors are tried in turn. Mirrors must serve
+// the very same file, since transfers are resumed across them.
+//
 // Optionally provide an additional cache writer in cases where the pending download
 // must be stored to other locations alongside the given path.
-func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error {
+func DownloadFile(ctx context.Context, url, path string, cache io.Writer, mirrors ...string) error {
 	filename := filepath.Base(path)
 
+	if err := AllowNetworkAccess(fmt.Sprintf("downloading '%s'", url)); err != nil {
+		return err
+	}
+
 	zap.S().Infof("Downloading file '%s' from '%s' to '%s'...", filename, url, filepath.Dir(path))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	d := &download{
+		ctx:      ctx,
+		path:     path,
+		filename: filename,
+		cache:    cache,
+	}
+	defer d.close()
+
+	urls := append([]string{url}, mirrors...)
+
+	var errs []error
+	for i, u := range urls {
+		err := d.fetch(u)
+		if err == nil {
+			zap.S().Infof("Downloading file '%s' completed", filename)
+			return nil
+		}
+
+		if len(urls) == 1 {
+			return err
+		}
+
+		errs = append(errs, fmt.Errorf("downloading from '%s': %w", u, err))
+		if ctx != nil && ctx.Err() != nil {
+			break
+		}
+
+		if i < len(urls)-1 {
+			zap.S().Warnf("Downloading file '%s' from '%s' failed, trying the next mirror: %v", filename, u, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// download holds the state of a single file download across retries and mirrors.
+type download struct {
+	ctx      context.Context
+	path     string
+	filename string
+	cache    io.Writer
+
+	file *os.File
+	bar  *progressBar
+	// written is the number of bytes stored so far.
+	written int64
+	// size is the total size of the file, if known.
+	size int64
+}
+
+// fetch downloads the remaining contents of the file from the given URL, retrying according to the policy.
+func (d *download) fetch(url string) error {
+	var err error
+
+	for attempt := 1; attempt <= downloadRetryPolicy.attempts; attempt++ {
+		var done bool
+		if done, err = d.attempt(url); done {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt == downloadRetryPolicy.attempts {
+			break
+		}
+
+		delay := downloadRetryPolicy.backoff(attempt)
+		zap.S().Warnf("Downloading file '%s' from '%s' failed, retrying in %s: %v", d.filename, url, delay, err)
+
+		select {
+		case <-d.ctx.Done():
+			return d.ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return err
+}
+
+// attempt performs a single request for the remaining contents of the file.
+// Returns true once the file is complete.
+func (d *download) attempt(url string) (bool, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return false, &permanentError{fmt.Errorf("creating request: %w", err)}
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	if d.written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.written))
+	}
+
+	resp, err := httpClient().Do(req)
 	if err != nil {
-		return fmt.Errorf("executing request: %w", err)
+		if d.ctx.Err() != nil || !isRetryableError(err) {
+			return false, &permanentError{fmt.Errorf("executing request: %w", err)}
+		}
+
+		return false, fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	var offset int64
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		d.size = resp.ContentLength
+	case resp.StatusCode == http.StatusPartialContent && d.written > 0:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil || start > d.written {
+			return false, &permanentError{fmt.Errorf("unexpected content range: %s", resp.Header.Get("Content-Range"))}
+		}
+
+		offset = start
+		d.size = size
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && d.written > 0 && d.written == d.size:
+		return true, nil
+	case isRetryableStatus(resp.StatusCode):
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	default:
+		return false, &permanentError{fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+
+	// Skip the contents which have already been stored, e.g. when the server does not support range requests
+	if skipped, err := io.CopyN(io.Discard, resp.Body, d.written-offset); err != nil {
+		return false, fmt.Errorf("skipping %d stored bytes, skipped %d: %w", d.written-offset, skipped, err)
 	}
 
-	file, err := os.Create(path)
+	writer, err := d.writer()
 	if err != nil {
-		return fmt.Errorf("creating file: %w", err)
+		return false, &permanentError{err}
 	}
-	defer file.Close()
 
-	var writers []io.Writer
-	writers = append(writers, file)
+	body := &bodyReader{reader: resp.Body}
+
+	n, err := io.Copy(writer, body)
+	d.written += n
+	if err != nil {
+		if body.err != nil {
+			return false, fmt.Errorf("reading response: %w", err)
+		}
 
-	if cache != nil {
-		writers = append(writers, cache)
+		return false, &permanentError{fmt.Errorf("storing response: %w", err)}
 	}
 
-	message := fmt.Sprintf("Downloading file: %s", filename)
+	return true, nil
+}
+
+// writer returns the writer of the stored contents, creating the file upon the first response.
+func (d *download) writer() (io.Writer, error) {
+	if d.file == nil {
+		file, err := os.Create(d.path)
+		if err != nil {
+			return nil, fmt.Errorf("creating file: %w", err)
+		}
 
-	if resp.ContentLength == -1 {
-		// Only audit the message since progress bars of unknown length
-		// (i.e. spinners) are not properly rendered.
-		log.Audit(message)
-	} else {
-		bar := progressbar.DefaultBytes(resp.ContentLength, message)
-		writers = append(writers, bar)
+		d.file = file
 	}
 
-	if _, err = io.Copy(io.MultiWriter(writers...), resp.Body); err != nil {
-		return fmt.Errorf("storing response: %w", err)
+	writers := []io.Writer{d.file}
+
+	if d.cache != nil {
+		writers = append(writers, d.cache)
 	}
 
-	zap.S().Infof("Downloading file '%s' completed", filename)
+	if d.bar == nil {
+		d.bar = progress.add(fmt.Sprintf("Downloading file: %s", d.filename), d.size)
+	}
+
+	d.bar.size.Store(d.size)
+	d.bar.current.Store(d.written)
+
+	writers = append(writers, d.bar)
+
+	return io.MultiWriter(writers...), nil
+}
+
+func (d *download) close() {
+	if d.bar != nil {
+		progress.finish(d.bar)
+	}
+
+	if d.file == nil {
+		return
+	}
+
+	if err := d.file.Close(); err != nil {
+		zap.S().Warnf("Closing file '%s' failed: %v", d.path, err)
+	}
+}
+
+// bodyReader records read errors, so that they can be distinguished from write errors.
+type bodyReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.err = err
+	}
+
+	return n, err
+}
+
+// isRetryableError reports whether the request failed due to network conditions,
+// as opposed to e.g. an unsupported URL.
+func isRetryableError(err error) bool {
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
+// parseContentRange returns the start and the total size from a `bytes <start>-<end>/<size>` header.
+// The size is -1 if unknown.
+func parseContentRange(header string) (start, size int64, err error) {
+	contentRange, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid content range: %s", header)
+	}
+
+	byteRange, total, found := strings.Cut(contentRange, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid content range: %s", header)
+	}
+
+	first, _, found := strings.Cut(byteRange, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid content range: %s", header)
+	}
+
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid content range: %s", header)
+	}
+
+	if total == "*" {
+		return start, -1, nil
+	}
+
+	if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid content range: %s", header)
+	}
+
+	return start, size, nil
+}
+
+// ContentLength queries the size of the file under the specified URL without downloading it.
+// Returns -1 if the server does not report the size.
+func ContentLength(ctx context.Context, url string) (int64, error) {
+	if err := AllowNetworkAccess(fmt.Sprintf("querying '%s'", url)); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
 
-	return nil
+	return resp.ContentLength, nil
 }
@@ -41,6 +41,8 @@ func TestDownloadFile(t *testing.T) {
 		},
 	}
 
+	withFastRetries(t)
+
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			err := DownloadFile(test.ctx, test.url, test.path, nil)
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrOffline is returned when network access is requested while offline mode is enabled.
+var ErrOffline = errors.New("network access is disabled in offline mode")
+
+var offline atomic.Bool
+
+// SetOffline enables or disables offline mode. While enabled, all requests made through
+// this package, as well as every other operation which checks AllowNetworkAccess, are refused,
+// so that only cached or local content can be used.
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// Offline reports whether offline mode is enabled.
+func Offline() bool {
+	return offline.Load()
+}
+
+// AllowNetworkAccess returns an error wrapping ErrOffline if offline mode is enabled.
+// The given description of the operation requiring network access is included in the error.
+func AllowNetworkAccess(operation string) error {
+	if Offline() {
+		return fmt.Errorf("%s: %w", operation, ErrOffline)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffline(t *testing.T) {
+	// Setup
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("contents"))
+	}))
+	defer server.Close()
+
+	SetOffline(true)
+	defer SetOffline(false)
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	// Test
+	downloadErr := DownloadFile(context.Background(), server.URL, path, nil)
+	_, lengthErr := ContentLength(context.Background(), server.URL)
+
+	// Verify
+	require.ErrorIs(t, downloadErr, ErrOffline)
+	assert.ErrorContains(t, downloadErr, "downloading '"+server.URL+"'")
+	assert.ErrorIs(t, lengthErr, ErrOffline)
+	assert.NoFileExists(t, path)
+	assert.Zero(t, requests)
+
+	SetOffline(false)
+	assert.NoError(t, AllowNetworkAccess("downloading"))
+	assert.NoError(t, DownloadFile(context.Background(), server.URL, path, nil))
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"golang.org/x/term"
+)
+
+const (
+	progressBarWidth       = 20
+	progressRenderInterval = 100 * time.Millisecond
+)
+
+// progress is the display shared by all downloads.
+var progress = newProgressDisplay(os.Stderr, term.IsTerminal(int(os.Stderr.Fd())))
+
+// progressDisplay renders the progress of concurrent downloads, one line per download.
+// The lines are redrawn in place while the output is a terminal; otherwise,
+// only the start of each download is reported.
+type progressDisplay struct {
+	out         io.Writer
+	interactive bool
+
+	mu   sync.Mutex
+	bars []*progressBar
+	// rendered is the number of lines of the active bars drawn in the last frame.
+	rendered int
+	running  bool
+}
+
+func newProgressDisplay(out io.Writer, interactive bool) *progressDisplay {
+	return &progressDisplay{
+		out:         out,
+		interactive: interactive,
+	}
+}
+
+// add registers a new bar of the given size, which is -1 if unknown.
+func (d *progressDisplay) add(description string, size int64) *progressBar {
+	bar := &progressBar{description: description}
+	bar.size.Store(size)
+
+	if !d.interactive {
+		log.Audit(description)
+		return bar
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.bars = append(d.bars, bar)
+
+	if !d.running {
+		d.running = true
+		go d.loop()
+	}
+
+	return bar
+}
+
+// finish draws the final state of the bar, which is then no longer redrawn.
+func (d *progressDisplay) finish(bar *progressBar) {
+	bar.done.Store(true)
+
+	if !d.interactive {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.render()
+}
+
+func (d *progressDisplay) loop() {
+	ticker := time.NewTicker(progressRenderInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		d.mu.Lock()
+
+		d.render()
+		if len(d.bars) == 0 {
+			d.running = false
+			d.mu.Unlock()
+			return
+		}
+
+		d.mu.Unlock()
+	}
+}
+
+// render redraws the bars in place. Finished bars are drawn first, so that they
+// remain above the bars which are still in progress.
+func (d *progressDisplay) render() {
+	var frame strings.Builder
+
+	if d.rendered > 0 {
+		fmt.Fprintf(&frame, "\033[%dA", d.rendered)
+	}
+
+	var active []*progressBar
+	for _, bar := range d.bars {
+		if bar.done.Load() {
+			fmt.Fprintf(&frame, "\r\033[K%s\n", bar)
+		} else {
+			active = append(active, bar)
+		}
+	}
+
+	for _, bar := range active {
+		fmt.Fprintf(&frame, "\r\033[K%s\n", bar)
+	}
+
+	d.bars = active
+	d.rendered = len(active)
+
+	_, _ = io.WriteString(d.out, frame.String())
+}
+
+// progressBar tracks the progress of a single download. Writes are counted towards its progress.
+type progressBar struct {
+	description string
+	size        atomic.Int64
+	current     atomic.Int64
+	done        atomic.Bool
+}
+
+func (b *progressBar) Write(p []byte) (int, error) {
+	b.current.Add(int64(len(p)))
+	return len(p), nil
+}
+
+func (b *progressBar) String() string {
+	current := b.current.Load()
+	size := b.size.Load()
+
+	if size <= 0 {
+		return fmt.Sprintf("%s (%s)", b.description, formatBytes(current))
+	}
+
+	percent := min(100, current*100/size)
+	filled := int(percent * progressBarWidth / 100)
+
+	return fmt.Sprintf("%s %3d%% |%s%s| (%s/%s)", b.description, percent,
+		strings.Repeat("█", filled), strings.Repeat(" ", progressBarWidth-filled),
+		formatBytes(current), formatBytes(size))
+}
+
+func formatBytes(n int64) string {
+	const unit = 1000
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	value := float64(n)
+	for _, prefix := range "kMGT" {
+		value /= unit
+		if value < unit || prefix == 'T' {
+			return fmt.Sprintf("%.1f %cB", value, prefix)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressBar_String(t *testing.T) {
+	tests := map[string]struct {
+		size     int64
+		current  int64
+		expected string
+	}{
+		"Known size": {
+			size:     2_000_000,
+			current:  500_000,
+			expected: "Downloading file: k3s  25% |█████               | (500.0 kB/2.0 MB)",
+		},
+		"Complete": {
+			size:     512,
+			current:  512,
+			expected: "Downloading file: k3s 100% |████████████████████| (512 B/512 B)",
+		},
+		"Unknown size": {
+			size:     -1,
+			current:  1_500_000_000,
+			expected: "Downloading file: k3s (1.5 GB)",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bar := &progressBar{description: "Downloading file: k3s"}
+			bar.size.Store(test.size)
+			bar.current.Store(test.current)
+
+			assert.Equal(t, test.expected, bar.String())
+		})
+	}
+}
+
+func TestProgressDisplay_Render(t *testing.T) {
+	// Setup
+	var out bytes.Buffer
+	display := &progressDisplay{out: &out, interactive: true}
+
+	first := &progressBar{description: "first"}
+	second := &progressBar{description: "second"}
+	display.bars = []*progressBar{first, second}
+
+	// Test
+	display.render()
+	second.done.Store(true)
+	display.render()
+
+	// Verify
+	frames := strings.Split(out.String(), "\033[2A")
+	assert.Len(t, frames, 2)
+
+	// Finished bars are moved above the bars in progress and no longer redrawn
+	assert.Equal(t, "\r\033[Ksecond (0 B)\n\r\033[Kfirst (0 B)\n", frames[1])
+	assert.Equal(t, []*progressBar{first}, display.bars)
+	assert.Equal(t, 1, display.rendered)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "999 B", formatBytes(999))
+	assert.Equal(t, "1.0 kB", formatBytes(1000))
+	assert.Equal(t, "1.5 GB", formatBytes(1_500_000_000))
+	assert.Equal(t, "2000.0 TB", formatBytes(2_000_000_000_000_000))
+}
@@ -0,0 +1,337 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fileContents = bytes.Repeat([]byte("0123456789"), 10000)
+
+// withFastRetries shortens the backoff for the duration of the test.
+func withFastRetries(t *testing.T) {
+	policy := downloadRetryPolicy
+	downloadRetryPolicy = retryPolicy{
+		attempts:       3,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     5 * time.Millisecond,
+	}
+
+	t.Cleanup(func() {
+		downloadRetryPolicy = policy
+	})
+}
+
+// dropResponse declares the full length of the file but only sends the given number of bytes,
+// which makes the server close the connection mid-stream.
+func dropResponse(w http.ResponseWriter, sent int) {
+	w.Header().Set("Content-Length", fmt.Sprint(len(fileContents)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(fileContents[:sent])
+}
+
+// serveFile serves the file, honouring range requests.
+func serveFile(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(fileContents))
+}
+
+func newServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, request int)) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, int(requests.Add(1)))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestDownloadFile_Retries(t *testing.T) {
+	withFastRetries(t)
+
+	tests := map[string]struct {
+		handler          func(w http.ResponseWriter, r *http.Request, request int)
+		expectedRequests int32
+		expectedError    string
+	}{
+		"Server errors": {
+			handler: func(w http.ResponseWriter, r *http.Request, request int) {
+				if request < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				serveFile(w, r)
+			},
+			expectedRequests: 3,
+		},
+		"Too many requests": {
+			handler: func(w http.ResponseWriter, r *http.Request, request int) {
+				if request == 1 {
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+
+				serveFile(w, r)
+			},
+			expectedRequests: 2,
+		},
+		"Attempts exhausted": {
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			expectedRequests: 3,
+			expectedError:    "unexpected status code: 502",
+		},
+		"Client errors are not retried": {
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedRequests: 1,
+			expectedError:    "unexpected status code: 404",
+		},
+		"Dropped connections": {
+			handler: func(w http.ResponseWriter, r *http.Request, request int) {
+				if request < 3 {
+					dropResponse(w, request*1000)
+					return
+				}
+
+				serveFile(w, r)
+			},
+			expectedRequests: 3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			server, requests := newServer(t, test.handler)
+			path := filepath.Join(t.TempDir(), "file")
+
+			// Test
+			err := DownloadFile(context.Background(), server.URL, path, nil)
+
+			// Verify
+			assert.Equal(t, test.expectedRequests, requests.Load())
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			contents, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, fileContents, contents)
+		})
+	}
+}
+
+func TestDownloadFile_Resume(t *testing.T) {
+	withFastRetries(t)
+
+	tests := map[string]struct {
+		handler       func(w http.ResponseWriter, r *http.Request)
+		expectedRange string
+	}{
+		"Range requests": {
+			handler:       serveFile,
+			expectedRange: "bytes=40000-",
+		},
+		"Range requests not supported": {
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write(fileContents)
+			},
+			expectedRange: "bytes=40000-",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			var ranges []string
+			server, _ := newServer(t, func(w http.ResponseWriter, r *http.Request, request int) {
+				ranges = append(ranges, r.Header.Get("Range"))
+
+				if request == 1 {
+					dropResponse(w, 40000)
+					return
+				}
+
+				test.handler(w, r)
+			})
+
+			path := filepath.Join(t.TempDir(), "file")
+			var cache bytes.Buffer
+
+			// Test
+			err := DownloadFile(context.Background(), server.URL, path, &cache)
+
+			// Verify
+			require.NoError(t, err)
+			assert.Equal(t, []string{"", test.expectedRange}, ranges)
+
+			contents, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, fileContents, contents)
+			assert.Equal(t, fileContents, cache.Bytes())
+		})
+	}
+}
+
+func TestDownloadFile_Mirrors(t *testing.T) {
+	withFastRetries(t)
+
+	// Setup
+	unavailable, unavailableRequests := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	missing, missingRequests := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	// The transfer started on the first mirror is resumed on the next one
+	var ranges []string
+	dropping, _ := newServer(t, func(w http.ResponseWriter, r *http.Request, _ int) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		dropResponse(w, 5000)
+	})
+	mirror, mirrorRequests := newServer(t, func(w http.ResponseWriter, r *http.Request, _ int) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		serveFile(w, r)
+	})
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	// Test
+	err := DownloadFile(context.Background(), unavailable.URL, path, nil, missing.URL, dropping.URL, mirror.URL)
+
+	// Verify
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, unavailableRequests.Load())
+	assert.EqualValues(t, 1, missingRequests.Load())
+	assert.EqualValues(t, 1, mirrorRequests.Load())
+	assert.Equal(t, []string{"", "bytes=5000-", "bytes=5000-", "bytes=5000-"}, ranges)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, fileContents, contents)
+}
+
+func TestDownloadFile_MirrorsExhausted(t *testing.T) {
+	withFastRetries(t)
+
+	// Setup
+	unavailable, _ := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	missing, _ := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	// Test
+	err := DownloadFile(context.Background(), unavailable.URL, path, nil, missing.URL)
+
+	// Verify
+	require.Error(t, err)
+	assert.ErrorContains(t, err, fmt.Sprintf("downloading from '%s': unexpected status code: 500", unavailable.URL))
+	assert.ErrorContains(t, err, fmt.Sprintf("downloading from '%s': unexpected status code: 404", missing.URL))
+	assert.NoFileExists(t, path)
+}
+
+func TestDownloadFile_Cancelled(t *testing.T) {
+	// Setup
+	policy := downloadRetryPolicy
+	downloadRetryPolicy = retryPolicy{attempts: 5, initialBackoff: time.Hour, maxBackoff: time.Hour}
+	defer func() {
+		downloadRetryPolicy = policy
+	}()
+
+	server, requests := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Test
+	err := DownloadFile(ctx, server.URL, filepath.Join(t.TempDir(), "file"), nil)
+
+	// Verify
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := retryPolicy{attempts: 10, initialBackoff: time.Second, maxBackoff: 10 * time.Second}
+
+	var delays []string
+	for retry := 1; retry <= 6; retry++ {
+		delays = append(delays, policy.backoff(retry).String())
+	}
+
+	assert.Equal(t, "1s 2s 4s 8s 10s 10s", strings.Join(delays, " "))
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := map[string]struct {
+		header        string
+		expectedStart int64
+		expectedSize  int64
+		expectedError string
+	}{
+		"Known size": {
+			header:        "bytes 100-199/200",
+			expectedStart: 100,
+			expectedSize:  200,
+		},
+		"Unknown size": {
+			header:        "bytes 100-199/*",
+			expectedStart: 100,
+			expectedSize:  -1,
+		},
+		"Invalid unit": {
+			header:        "items 100-199/200",
+			expectedError: "invalid content range: items 100-199/200",
+		},
+		"Invalid start": {
+			header:        "bytes abc-199/200",
+			expectedError: "invalid content range: bytes abc-199/200",
+		},
+		"Missing size": {
+			header:        "bytes 100-199",
+			expectedError: "invalid content range: bytes 100-199",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			start, size, err := parseContentRange(test.header)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStart, start)
+			assert.Equal(t, test.expectedSize, size)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultParallelism is the number of concurrent downloads unless configured otherwise.
+const DefaultParallelism = 4
+
+// Scheduler runs downloads concurrently, bounding the number of downloads in progress.
+// The progress of the downloads is rendered on a shared display.
+type Scheduler struct {
+	group  errgroup.Group
+	failed atomic.Bool
+}
+
+// NewScheduler creates a scheduler running up to the given number of downloads at once.
+// DefaultParallelism is used unless the number is positive.
+func NewScheduler(parallelism int) *Scheduler {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	s := &Scheduler{}
+	s.group.SetLimit(parallelism)
+
+	return s
+}
+
+// Go schedules the download, blocking while the limit of downloads in progress is reached.
+// Downloads which have not been started yet are skipped once any download fails.
+func (s *Scheduler) Go(download func() error) {
+	s.group.Go(func() error {
+		if s.failed.Load() {
+			return nil
+		}
+
+		if err := download(); err != nil {
+			s.failed.Store(true)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Wait blocks until all scheduled downloads are complete and returns the first error, if any.
+func (s *Scheduler) Wait() error {
+	return s.group.Wait()
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_Parallelism(t *testing.T) {
+	// Setup
+	scheduler := NewScheduler(2)
+
+	var running, maxRunning, completed atomic.Int32
+
+	// Test
+	for range 6 {
+		scheduler.Go(func() error {
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			completed.Add(1)
+
+			return nil
+		})
+	}
+
+	// Verify
+	assert.NoError(t, scheduler.Wait())
+	assert.EqualValues(t, 6, completed.Load())
+	assert.EqualValues(t, 2, maxRunning.Load())
+}
+
+func TestScheduler_Failure(t *testing.T) {
+	// Setup
+	scheduler := NewScheduler(1)
+
+	var started atomic.Int32
+
+	// Test
+	scheduler.Go(func() error {
+		started.Add(1)
+		return errors.New("connection reset")
+	})
+
+	for range 3 {
+		scheduler.Go(func() error {
+			started.Add(1)
+			return nil
+		})
+	}
+
+	// Verify
+	assert.EqualError(t, scheduler.Wait(), "connection reset")
+	assert.EqualValues(t, 1, started.Load(), "pending downloads must be skipped after a failure")
+}
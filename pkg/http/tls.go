@@ -0,0 +1,297 @@
+package http
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+)
+
+const (
+	certificatesFileName = "eib-ca-certificates.pem"
+	bundleFileName       = "eib-ca-bundle.pem"
+)
+
+// systemBundlePaths are the locations of the CA bundle of the build host, as looked up by Go on Linux.
+var systemBundlePaths = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/tls/cacert.pem",
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+}
+
+// TLSSettings configures the TLS connections EIB establishes to remote hosts.
+type TLSSettings struct {
+	// CABundle is the path to a PEM file holding certificate authorities which are trusted
+	// for all hosts in addition to the ones of the build host.
+	CABundle string
+	// Hosts maps host names to the settings of the connections to them.
+	Hosts map[string]HostTLSSettings
+}
+
+// HostTLSSettings configures the TLS connections to a single host.
+type HostTLSSettings struct {
+	// CAFile is the path to a PEM file holding certificate authorities which are trusted for the host.
+	CAFile string
+	// InsecureSkipVerify disables the verification of the certificates presented by the host.
+	InsecureSkipVerify bool
+}
+
+var tlsState = struct {
+	mu     sync.RWMutex
+	client *http.Client
+	hosts  map[string]HostTLSSettings
+	// certificatesFile holds the configured certificate authorities only.
+	certificatesFile string
+	// bundleFile holds the certificate authorities of the build host along with the configured ones.
+	bundleFile string
+}{
+	client: http.DefaultClient,
+}
+
+// ConfigureTLS applies the given settings to all requests made through this package.
+//
+// The configured certificate authorities are additionally written to the given directory, so that external
+// commands (see CommandEnv) and container builds (see CertificatesFile) are able to trust them as well.
+func ConfigureTLS(settings TLSSettings, dir string) error {
+	var custom bytes.Buffer
+
+	if settings.CABundle != "" {
+		if err := appendCertificates(&custom, settings.CABundle); err != nil {
+			return fmt.Errorf("loading CA bundle: %w", err)
+		}
+	}
+
+	base, err := newTransport(custom.Bytes(), "", false)
+	if err != nil {
+		return fmt.Errorf("configuring transport: %w", err)
+	}
+
+	transport := &hostTransport{
+		base:  base,
+		hosts: map[string]http.RoundTripper{},
+	}
+
+	hosts := map[string]HostTLSSettings{}
+
+	for host, hostSettings := range settings.Hosts {
+		host = strings.ToLower(host)
+		hosts[host] = hostSettings
+
+		t, err := newTransport(custom.Bytes(), hostSettings.CAFile, hostSettings.InsecureSkipVerify)
+		if err != nil {
+			return fmt.Errorf("configuring transport for host '%s': %w", host, err)
+		}
+
+		transport.hosts[host] = t
+	}
+
+	// Host specific certificate authorities are trusted by external commands for all hosts,
+	// since those cannot be configured per host
+	for _, hostSettings := range settings.Hosts {
+		if hostSettings.CAFile == "" {
+			continue
+		}
+
+		if err = appendCertificates(&custom, hostSettings.CAFile); err != nil {
+			return fmt.Errorf("loading CA file: %w", err)
+		}
+	}
+
+	var certificatesFile, bundleFile string
+
+	if custom.Len() > 0 {
+		if certificatesFile, bundleFile, err = writeCertificates(custom.Bytes(), dir); err != nil {
+			return err
+		}
+	}
+
+	tlsState.mu.Lock()
+	defer tlsState.mu.Unlock()
+
+	tlsState.client = &http.Client{Transport: transport}
+	tlsState.hosts = hosts
+	tlsState.certificatesFile = certificatesFile
+	tlsState.bundleFile = bundleFile
+
+	return nil
+}
+
+// CertificatesFile returns the path to the PEM file holding the configured certificate authorities,
+// or an empty string if none are configured.
+func CertificatesFile() string {
+	tlsState.mu.RLock()
+	defer tlsState.mu.RUnlock()
+
+	return tlsState.certificatesFile
+}
+
+// CommandEnv returns the environment of external commands connecting to remote hosts, which makes them trust
+// the configured certificate authorities along with the ones of the build host.
+// Returns nil, i.e. the environment of EIB, if none are configured.
+func CommandEnv() []string {
+	tlsState.mu.RLock()
+	defer tlsState.mu.RUnlock()
+
+	if tlsState.bundleFile == "" {
+		return nil
+	}
+
+	return append(os.Environ(), fmt.Sprintf("SSL_CERT_FILE=%s", tlsState.bundleFile))
+}
+
+// SkipTLSVerify reports whether the verification of certificates is disabled for the host of the given
+// URL or registry reference.
+func SkipTLSVerify(ref string) bool {
+	tlsState.mu.RLock()
+	defer tlsState.mu.RUnlock()
+
+	return tlsState.hosts[hostName(ref)].InsecureSkipVerify
+}
+
+func httpClient() *http.Client {
+	tlsState.mu.RLock()
+	defer tlsState.mu.RUnlock()
+
+	return tlsState.client
+}
+
+// hostName extracts the lowercase host name, without the port, from a URL or a reference such as "registry.example.com/chart".
+func hostName(ref string) string {
+	if u, err := neturl.Parse(ref); err == nil && u.Host != "" {
+		return strings.ToLower(u.Hostname())
+	}
+
+	host, _, _ := strings.Cut(ref, "/")
+	if u, err := neturl.Parse("//" + host); err == nil {
+		return strings.ToLower(u.Hostname())
+	}
+
+	return strings.ToLower(host)
+}
+
+// hostTransport routes requests through the transport configured for their host, if any.
+type hostTransport struct {
+	base  http.RoundTripper
+	hosts map[string]http.RoundTripper
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := t.hosts[strings.ToLower(req.URL.Hostname())]; ok {
+		return transport.RoundTrip(req)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// newTransport creates a transport trusting the certificate authorities of the build host along with the given ones.
+func newTransport(certificates []byte, caFile string, insecureSkipVerify bool) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(certificates) == 0 && caFile == "" && !insecureSkipVerify {
+		return transport, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("loading system certificate pool: %w", err)
+	}
+
+	if len(certificates) > 0 {
+		// Validated while being loaded
+		pool.AppendCertsFromPEM(certificates)
+	}
+
+	if caFile != "" {
+		var buf bytes.Buffer
+		if err = appendCertificates(&buf, caFile); err != nil {
+			return nil, fmt.Errorf("loading CA file: %w", err)
+		}
+
+		pool.AppendCertsFromPEM(buf.Bytes())
+	}
+
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            pool,
+		InsecureSkipVerify: insecureSkipVerify, //nolint:gosec // Explicitly requested for the host
+	}
+
+	return transport, nil
+}
+
+// appendCertificates reads the PEM file under the given path, validating that it holds at least one certificate.
+func appendCertificates(buf *bytes.Buffer, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading '%s': %w", path, err)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return fmt.Errorf("no PEM encoded certificates found in '%s'", path)
+	}
+
+	buf.Write(data)
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	return nil
+}
+
+// writeCertificates stores the given certificate authorities on their own and combined with the ones of the build host.
+func writeCertificates(certificates []byte, dir string) (certificatesFile, bundleFile string, err error) {
+	certificatesFile = filepath.Join(dir, certificatesFileName)
+	if err = os.WriteFile(certificatesFile, certificates, fileio.NonExecutablePerms); err != nil {
+		return "", "", fmt.Errorf("writing CA certificates: %w", err)
+	}
+
+	system, err := systemBundle()
+	if err != nil {
+		return "", "", fmt.Errorf("reading system CA bundle: %w", err)
+	}
+
+	if len(system) > 0 && !bytes.HasSuffix(system, []byte("\n")) {
+		system = append(system, '\n')
+	}
+
+	bundleFile = filepath.Join(dir, bundleFileName)
+	if err = os.WriteFile(bundleFile, append(system, certificates...), fileio.NonExecutablePerms); err != nil {
+		return "", "", fmt.Errorf("writing CA bundle: %w", err)
+	}
+
+	return certificatesFile, bundleFile, nil
+}
+
+// systemBundle reads the CA bundle of the build host. Returns no contents if there is none.
+func systemBundle() ([]byte, error) {
+	paths := systemBundlePaths
+	if path := os.Getenv("SSL_CERT_FILE"); path != "" {
+		paths = []string{path}
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return data, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("reading '%s': %w", path, err)
+		}
+	}
+
+	return nil, nil
+}
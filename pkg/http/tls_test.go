@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSServer starts a server with a self-signed certificate and writes the certificate to a PEM file.
+// Returns the server along with the path to the PEM file.
+func newTLSServer(t *testing.T) (*httptest.Server, string) {
+	server := httptest.NewTLSServer(http.HandlerFunc(serveFile))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, data, 0o600))
+
+	return server, caFile
+}
+
+// resetTLS restores the default TLS settings once the test completes.
+func resetTLS(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, ConfigureTLS(TLSSettings{}, ""))
+	})
+}
+
+func TestConfigureTLS(t *testing.T) {
+	server, caFile := newTLSServer(t)
+
+	tests := map[string]struct {
+		settings         TLSSettings
+		expectedError    string
+		expectedInsecure bool
+		expectedEnv      bool
+	}{
+		"Default": {
+			expectedError: "certificate signed by unknown authority",
+		},
+		"CA bundle": {
+			settings: TLSSettings{
+				CABundle: caFile,
+			},
+			expectedEnv: true,
+		},
+		"Host CA file": {
+			settings: TLSSettings{
+				Hosts: map[string]HostTLSSettings{
+					"127.0.0.1": {CAFile: caFile},
+				},
+			},
+			expectedEnv: true,
+		},
+		"Other host CA file": {
+			settings: TLSSettings{
+				Hosts: map[string]HostTLSSettings{
+					"registry.example.com": {CAFile: caFile},
+				},
+			},
+			expectedError: "certificate signed by unknown authority",
+			expectedEnv:   true,
+		},
+		"Host insecure": {
+			settings: TLSSettings{
+				Hosts: map[string]HostTLSSettings{
+					"127.0.0.1": {InsecureSkipVerify: true},
+				},
+			},
+			expectedInsecure: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetTLS(t)
+			dir := t.TempDir()
+
+			// Test
+			require.NoError(t, ConfigureTLS(test.settings, dir))
+			err := DownloadFile(context.Background(), server.URL, filepath.Join(dir, "file"), nil)
+
+			// Verify
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+			} else {
+				require.NoError(t, err)
+
+				data, err := os.ReadFile(filepath.Join(dir, "file"))
+				require.NoError(t, err)
+				assert.Equal(t, fileContents, data)
+			}
+
+			assert.Equal(t, test.expectedInsecure, SkipTLSVerify(server.URL))
+
+			if !test.expectedEnv {
+				assert.Nil(t, CommandEnv())
+				assert.Empty(t, CertificatesFile())
+				return
+			}
+
+			assert.Contains(t, CommandEnv(), "SSL_CERT_FILE="+filepath.Join(dir, bundleFileName))
+
+			certificates, err := os.ReadFile(CertificatesFile())
+			require.NoError(t, err)
+
+			ca, err := os.ReadFile(caFile)
+			require.NoError(t, err)
+			assert.Equal(t, ca, certificates)
+
+			bundle, err := os.ReadFile(filepath.Join(dir, bundleFileName))
+			require.NoError(t, err)
+			assert.True(t, strings.HasSuffix(string(bundle), string(ca)))
+		})
+	}
+}
+
+func TestConfigureTLS_InvalidCertificates(t *testing.T) {
+	resetTLS(t)
+	dir := t.TempDir()
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a certificate"), 0o600))
+
+	tests := map[string]struct {
+		settings      TLSSettings
+		expectedError string
+	}{
+		"Missing CA bundle": {
+			settings: TLSSettings{
+				CABundle: filepath.Join(dir, "missing.pem"),
+			},
+			expectedError: "loading CA bundle: reading '" + filepath.Join(dir, "missing.pem") + "'",
+		},
+		"Invalid CA bundle": {
+			settings: TLSSettings{
+				CABundle: invalidFile,
+			},
+			expectedError: "loading CA bundle: no PEM encoded certificates found in '" + invalidFile + "'",
+		},
+		"Invalid host CA file": {
+			settings: TLSSettings{
+				Hosts: map[string]HostTLSSettings{
+					"registry.example.com": {CAFile: invalidFile},
+				},
+			},
+			expectedError: "configuring transport for host 'registry.example.com': loading CA file: no PEM encoded certificates found",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ConfigureTLS(test.settings, dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedError)
+		})
+	}
+}
+
+func TestHostName(t *testing.T) {
+	tests := map[string]string{
+		"https://Registry.example.com:5000/charts": "registry.example.com",
+		"oci://registry.example.com/charts":        "registry.example.com",
+		"registry.example.com:5000/charts":         "registry.example.com",
+		"registry.example.com":                     "registry.example.com",
+		"http://[::1]:8080/file":                   "::1",
+	}
+
+	for ref, expected := range tests {
+		t.Run(ref, func(t *testing.T) {
+			assert.Equal(t, expected, hostName(ref))
+		})
+	}
+}
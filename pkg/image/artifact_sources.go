@@ -0,0 +1,127 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseArtifactSources decodes the built-in artifact sources and merges the given overlays over them in order.
+//
+// Overlays only need to specify the values they override. Nested sections are merged field by field,
+// while lists, such as the mirrors, replace the built-in ones entirely. Unknown fields in overlays are rejected.
+func ParseArtifactSources(data []byte, overlays ...[]byte) (*ArtifactSources, error) {
+	var sources ArtifactSources
+	if err := yaml.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("decoding artifact sources: %w", err)
+	}
+
+	for _, overlay := range overlays {
+		decoder := yaml.NewDecoder(bytes.NewReader(overlay))
+		decoder.KnownFields(true)
+
+		// An empty overlay leaves the sources unchanged
+		if err := decoder.Decode(&sources); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("decoding artifact sources overlay: %w", err)
+		}
+	}
+
+	return &sources, nil
+}
+
+// ValidateArtifactSources reports all problems with the given artifact sources at once.
+func ValidateArtifactSources(sources *ArtifactSources) error {
+	var errs []error
+
+	errs = append(errs, validateChartSources("metallb", sources.MetalLB.Chart,
+		sources.MetalLB.Repository, sources.MetalLB.Version)...)
+	errs = append(errs, validateChartSources("endpoint-copier-operator", sources.EndpointCopierOperator.Chart,
+		sources.EndpointCopierOperator.Repository, sources.EndpointCopierOperator.Version)...)
+
+	if sources.KubeVIP.Image == "" {
+		errs = append(errs, fmt.Errorf("'kube-vip.image' must be specified"))
+	}
+
+	k8s := sources.Kubernetes
+	errs = append(errs, validateKubernetesSources("kubernetes.k3s", k8s.K3s.SELinuxPackage, k8s.K3s.SELinuxRepository,
+		k8s.K3s.SELinuxRepositoryPriority, k8s.K3s.ReleaseURL, k8s.K3s.Mirrors, k8s.K3s.ChannelsURL)...)
+	errs = append(errs, validateKubernetesSources("kubernetes.rke2", k8s.Rke2.SELinuxPackage, k8s.Rke2.SELinuxRepository,
+		k8s.Rke2.SELinuxRepositoryPriority, k8s.Rke2.ReleaseURL, k8s.Rke2.Mirrors, k8s.Rke2.ChannelsURL)...)
+
+	return errors.Join(errs...)
+}
+
+func validateChartSources(section, chart, repository, version string) []error {
+	var errs []error
+
+	if chart == "" {
+		errs = append(errs, fmt.Errorf("'%s.chart' must be specified", section))
+	}
+
+	if err := validateSourceURL(repository, "http", "https", "oci"); err != nil {
+		errs = append(errs, fmt.Errorf("'%s.repository' %w", section, err))
+	}
+
+	if version == "" {
+		errs = append(errs, fmt.Errorf("'%s.version' must be specified", section))
+	}
+
+	return errs
+}
+
+func validateKubernetesSources(section, selinuxPackage, selinuxRepository string, priority int,
+	releaseURL string, mirrors []string, channelsURL string) []error {
+	var errs []error
+
+	if selinuxPackage == "" {
+		errs = append(errs, fmt.Errorf("'%s.selinuxPackage' must be specified", section))
+	}
+
+	if err := validateSourceURL(selinuxRepository, "http", "https"); err != nil {
+		errs = append(errs, fmt.Errorf("'%s.selinuxRepository' %w", section, err))
+	}
+
+	// Zypper repository priorities range from 1 (highest) to 99 (lowest)
+	if priority < 1 || priority > 99 {
+		errs = append(errs, fmt.Errorf("'%s.selinuxRepositoryPriority' must be between 1 and 99", section))
+	}
+
+	if err := validateSourceURL(releaseURL, "http", "https"); err != nil {
+		errs = append(errs, fmt.Errorf("'%s.releaseURL' %w", section, err))
+	}
+
+	for i, mirror := range mirrors {
+		if err := validateSourceURL(mirror, "http", "https"); err != nil {
+			errs = append(errs, fmt.Errorf("'%s.mirrors[%d]' %w", section, i, err))
+		}
+	}
+
+	if err := validateSourceURL(channelsURL, "http", "https"); err != nil {
+		errs = append(errs, fmt.Errorf("'%s.channelsURL' %w", section, err))
+	}
+
+	return errs
+}
+
+func validateSourceURL(value string, schemes ...string) error {
+	if value == "" {
+		return fmt.Errorf("must be specified")
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("must be an absolute URL, found '%s'", value)
+	}
+
+	if !slices.Contains(schemes, u.Scheme) {
+		return fmt.Errorf("must use one of the schemes %s, found '%s'", strings.Join(schemes, ", "), value)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func builtInArtifactSources(t *testing.T) []byte {
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "artifacts.yaml"))
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestParseArtifactSources_BuiltIn(t *testing.T) {
+	sources, err := ParseArtifactSources(builtInArtifactSources(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "metallb", sources.MetalLB.Chart)
+	assert.Equal(t, "https://github.com/rancher/rke2/releases/download/", sources.Kubernetes.Rke2.ReleaseURL)
+	assert.NoError(t, ValidateArtifactSources(sources))
+}
+
+func TestParseArtifactSources_Overlay(t *testing.T) {
+	// Setup
+	overlay := []byte(`
+metallb:
+  version: 0.2.0
+kubernetes:
+  rke2:
+    releaseURL: https://mirror.example.com/rke2/
+    mirrors:
+      - https://backup.example.com/rke2/
+`)
+
+	// Test
+	sources, err := ParseArtifactSources(builtInArtifactSources(t), overlay)
+	require.NoError(t, err)
+
+	// Verify
+	assert.Equal(t, "metallb", sources.MetalLB.Chart)
+	assert.Equal(t, "https://suse-edge.github.io/charts", sources.MetalLB.Repository)
+	assert.Equal(t, "0.2.0", sources.MetalLB.Version)
+
+	assert.Equal(t, "rke2-selinux", sources.Kubernetes.Rke2.SELinuxPackage)
+	assert.Equal(t, "https://mirror.example.com/rke2/", sources.Kubernetes.Rke2.ReleaseURL)
+	assert.Equal(t, []string{"https://backup.example.com/rke2/"}, sources.Kubernetes.Rke2.Mirrors)
+
+	assert.Equal(t, "https://github.com/k3s-io/k3s/releases/download/", sources.Kubernetes.K3s.ReleaseURL)
+}
+
+func TestParseArtifactSources_EmptyOverlay(t *testing.T) {
+	expected, err := ParseArtifactSources(builtInArtifactSources(t))
+	require.NoError(t, err)
+
+	sources, err := ParseArtifactSources(builtInArtifactSources(t), []byte("# No overrides\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, sources)
+}
+
+func TestParseArtifactSources_InvalidOverlay(t *testing.T) {
+	tests := map[string]struct {
+		overlay       string
+		expectedError string
+	}{
+		"Unknown field": {
+			overlay:       "kubernetes:\n  rke2:\n    releaseUrl: https://mirror.example.com/rke2/\n",
+			expectedError: "field releaseUrl not found",
+		},
+		"Invalid type": {
+			overlay:       "kubernetes:\n  rke2:\n    mirrors: https://mirror.example.com/rke2/\n",
+			expectedError: "cannot unmarshal !!str",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseArtifactSources(builtInArtifactSources(t), []byte(test.overlay))
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "decoding artifact sources overlay")
+			assert.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestValidateArtifactSources(t *testing.T) {
+	// Setup
+	overlay := []byte(`
+metallb:
+  chart: ""
+  repository: suse-edge.github.io/charts
+endpoint-copier-operator:
+  repository: oci://registry.example.com/charts
+kube-vip:
+  image: ""
+kubernetes:
+  k3s:
+    selinuxRepositoryPriority: 0
+    mirrors:
+      - ftp://mirror.example.com/k3s/
+    channelsURL: update.k3s.io/v1-release/channels
+  rke2:
+    releaseURL: ""
+`)
+
+	sources, err := ParseArtifactSources(builtInArtifactSources(t), overlay)
+	require.NoError(t, err)
+
+	// Test
+	err = ValidateArtifactSources(sources)
+
+	// Verify
+	require.Error(t, err)
+	assert.Equal(t, "'metallb.chart' must be specified\n"+
+		"'metallb.repository' must be an absolute URL, found 'suse-edge.github.io/charts'\n"+
+		"'kube-vip.image' must be specified\n"+
+		"'kubernetes.k3s.selinuxRepositoryPriority' must be between 1 and 99\n"+
+		"'kubernetes.k3s.mirrors[0]' must use one of the schemes http, https, found 'ftp://mirror.example.com/k3s/'\n"+
+		"'kubernetes.k3s.channelsURL' must be an absolute URL, found 'update.k3s.io/v1-release/channels'\n"+
+		"'kubernetes.rke2.releaseURL' must be specified", err.Error())
+}
@@ -0,0 +1,217 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	extendsKey    = "extends"
+	apiVersionKey = "apiVersion"
+
+	// replaceTag can be set on a list or a map in order to replace the respective value
+	// of the extended definitions instead of merging with it.
+	replaceTag = "!replace"
+)
+
+// ExtendsAPIVersion is the API version which introduced composing definitions via the `extends` field.
+const ExtendsAPIVersion = "1.4"
+
+// ComposeDefinition merges the image definition data over the definitions listed under its `extends` field.
+// The extended definitions are located relative to the given configuration directory and may extend
+// other definitions themselves.
+//
+// Definitions are merged in the order they are listed, followed by the definition extending them, using the following rules:
+//   - Maps are merged key by key
+//   - Lists are appended to each other, unless tagged with `!replace`
+//   - Scalars override each other
+//
+// The result is a self-contained definition which no longer contains the `extends` field.
+// Definitions which do not extend others are returned unchanged, leaving any malformed content
+// to be reported by ParseDefinition.
+func ComposeDefinition(data []byte, configDir string) ([]byte, error) {
+	root, err := decodeDefinitionNode(data)
+	if err != nil || mappingValue(root, extendsKey) == nil {
+		return data, nil
+	}
+
+	composed, err := composeDefinitionNode(root, configDir, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stripReplaceTags(composed)
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err = encoder.Encode(composed); err != nil {
+		return nil, fmt.Errorf("encoding composed definition: %w", err)
+	}
+
+	if err = encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encoding composed definition: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeDefinitionNode(data []byte) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("could not parse the image definition: %w", err)
+	}
+
+	if len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("could not parse the image definition: expected a map at line %d", root.Line)
+	}
+
+	return root, nil
+}
+
+func composeDefinitionNode(root *yaml.Node, configDir string, chain []string) (*yaml.Node, error) {
+	extends, err := removeExtends(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(extends) == 0 {
+		return root, nil
+	}
+
+	if len(chain) == 0 {
+		if v := mappingValue(root, apiVersionKey); v != nil && strings.Compare(v.Value, ExtendsAPIVersion) < 0 {
+			return nil, fmt.Errorf("field `%s` is only available in API version >= %s", extendsKey, ExtendsAPIVersion)
+		}
+	}
+
+	var composed *yaml.Node
+
+	for _, path := range extends {
+		fullPath := filepath.Join(configDir, path)
+		if slices.Contains(chain, fullPath) {
+			return nil, fmt.Errorf("circular extension of definition '%s'", path)
+		}
+
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading extended definition '%s': %w", path, err)
+		}
+
+		base, err := decodeDefinitionNode(data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding extended definition '%s': %w", path, err)
+		}
+
+		base, err = composeDefinitionNode(base, configDir, append(chain, fullPath))
+		if err != nil {
+			return nil, fmt.Errorf("composing extended definition '%s': %w", path, err)
+		}
+
+		if composed == nil {
+			composed = base
+		} else {
+			composed = mergeNodes(composed, base)
+		}
+	}
+
+	return mergeNodes(composed, root), nil
+}
+
+func removeExtends(root *yaml.Node) ([]string, error) {
+	for i := 0; i < len(root.Content); i += 2 {
+		if root.Content[i].Value != extendsKey {
+			continue
+		}
+
+		var extends []string
+		if err := root.Content[i+1].Decode(&extends); err != nil {
+			return nil, fmt.Errorf("field `%s` must be a list of definition files: %w", extendsKey, err)
+		}
+
+		root.Content = append(root.Content[:i], root.Content[i+2:]...)
+
+		if slices.Contains(extends, "") {
+			return nil, errors.New("field `extends` cannot contain empty values")
+		}
+
+		return extends, nil
+	}
+
+	return nil, nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if i := mappingKeyIndex(node, key); i >= 0 {
+		return node.Content[i+1]
+	}
+
+	return nil
+}
+
+// mappingKeyIndex returns the index of the key in the contents of the mapping node, or -1 if it is missing.
+// Only even indexes correspond to keys, the values following them.
+func mappingKeyIndex(node *yaml.Node, key string) int {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func mergeNodes(base, override *yaml.Node) *yaml.Node {
+	if override.Tag == replaceTag || base.Kind != override.Kind {
+		return override
+	}
+
+	switch override.Kind {
+	case yaml.MappingNode:
+		merged := *base
+		merged.Content = slices.Clone(base.Content)
+
+		for i := 0; i < len(override.Content); i += 2 {
+			key, value := override.Content[i], override.Content[i+1]
+
+			if index := mappingKeyIndex(&merged, key.Value); index >= 0 {
+				merged.Content[index+1] = mergeNodes(merged.Content[index+1], value)
+			} else {
+				merged.Content = append(merged.Content, key, value)
+			}
+		}
+
+		return &merged
+	case yaml.SequenceNode:
+		merged := *base
+		merged.Content = append(slices.Clone(base.Content), override.Content...)
+
+		return &merged
+	default:
+		return override
+	}
+}
+
+func stripReplaceTags(node *yaml.Node) {
+	if node.Tag == replaceTag {
+		node.Tag = ""
+	}
+
+	for _, n := range node.Content {
+		stripReplaceTags(n)
+	}
+}
@@ -0,0 +1,215 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDefinitions(t *testing.T, definitions map[string]string) string {
+	dir := t.TempDir()
+
+	for name, contents := range definitions {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0o600))
+	}
+
+	return dir
+}
+
+func TestComposeDefinition(t *testing.T) {
+	// Setup
+	configDir := writeDefinitions(t, map[string]string{
+		"bases/os.yaml": `
+apiVersion: "1.4"
+image:
+  arch: x86_64
+  baseImage: slemicro.iso
+operatingSystem:
+  kernelArgs:
+    - alpha=foo
+  packages:
+    packageList:
+      - vim
+  users:
+    - username: alpha
+      encryptedPassword: alpha123
+`,
+		"bases/k8s.yaml": `
+extends:
+  - bases/os.yaml
+kubernetes:
+  version: v1.30.3+rke2r1
+operatingSystem:
+  packages:
+    packageList:
+      - open-iscsi
+`,
+	})
+
+	data := []byte(`
+apiVersion: "1.4"
+extends:
+  - bases/k8s.yaml
+image:
+  imageType: iso
+  outputImageName: eib-image.iso
+operatingSystem:
+  kernelArgs: !replace
+    - beta=bar
+  packages:
+    packageList:
+      - htop
+`)
+
+	// Test
+	composed, err := ComposeDefinition(data, configDir)
+	require.NoError(t, err)
+
+	// Verify
+	assert.NotContains(t, string(composed), "extends")
+	assert.NotContains(t, string(composed), "!replace")
+
+	definition, err := ParseDefinition(composed, configDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.4", definition.APIVersion)
+	assert.Equal(t, TypeISO, definition.Image.ImageType)
+	assert.Equal(t, ArchTypeX86, definition.Image.Arch)
+	assert.Equal(t, "slemicro.iso", definition.Image.BaseImage)
+	assert.Equal(t, "eib-image.iso", definition.Image.OutputImageName)
+	assert.Equal(t, "v1.30.3+rke2r1", definition.Kubernetes.Version)
+	assert.Equal(t, []string{"beta=bar"}, definition.OperatingSystem.KernelArgs)
+	assert.Equal(t, []string{"vim", "open-iscsi", "htop"}, definition.OperatingSystem.Packages.PKGList)
+	require.Len(t, definition.OperatingSystem.Users, 1)
+	assert.Equal(t, "alpha", definition.OperatingSystem.Users[0].Username)
+}
+
+func TestComposeDefinition_MultipleBases(t *testing.T) {
+	// Setup
+	configDir := writeDefinitions(t, map[string]string{
+		"first.yaml": `
+image:
+  arch: x86_64
+  baseImage: first.iso
+`,
+		"second.yaml": `
+image:
+  baseImage: second.iso
+`,
+	})
+
+	data := []byte(`
+apiVersion: "1.4"
+extends:
+  - first.yaml
+  - second.yaml
+`)
+
+	// Test
+	composed, err := ComposeDefinition(data, configDir)
+	require.NoError(t, err)
+
+	// Verify
+	definition, err := ParseDefinition(composed, configDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, ArchTypeX86, definition.Image.Arch)
+	assert.Equal(t, "second.iso", definition.Image.BaseImage)
+}
+
+func TestComposeDefinition_KeyMatchingValue(t *testing.T) {
+	// Setup
+	configDir := writeDefinitions(t, map[string]string{
+		"base.yaml": `
+image:
+  baseImage: arch
+  arch: x86_64
+`,
+	})
+
+	data := []byte(`
+apiVersion: "1.4"
+extends:
+  - base.yaml
+image:
+  arch: aarch64
+`)
+
+	// Test
+	composed, err := ComposeDefinition(data, configDir)
+	require.NoError(t, err)
+
+	// Verify
+	definition, err := ParseDefinition(composed, configDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "arch", definition.Image.BaseImage)
+	assert.Equal(t, ArchTypeARM, definition.Image.Arch)
+}
+
+func TestComposeDefinition_NoExtends(t *testing.T) {
+	// Setup
+	data := []byte("apiVersion: \"1.3\"\n# comment kept as is\n")
+
+	// Test
+	composed, err := ComposeDefinition(data, t.TempDir())
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, data, composed)
+}
+
+func TestComposeDefinition_Errors(t *testing.T) {
+	configDir := writeDefinitions(t, map[string]string{
+		"cycle-a.yaml": "extends:\n  - cycle-b.yaml\n",
+		"cycle-b.yaml": "extends:\n  - cycle-a.yaml\n",
+	})
+
+	tests := map[string]struct {
+		Data          string
+		ExpectedError string
+	}{
+		"Unsupported API Version": {
+			Data:          "apiVersion: \"1.3\"\nextends:\n  - cycle-a.yaml\n",
+			ExpectedError: "field `extends` is only available in API version >= 1.4",
+		},
+		"Invalid Extends": {
+			Data:          "apiVersion: \"1.4\"\nextends: base.yaml\n",
+			ExpectedError: "field `extends` must be a list of definition files",
+		},
+		"Empty Extends Entry": {
+			Data:          "apiVersion: \"1.4\"\nextends:\n  - \"\"\n",
+			ExpectedError: "field `extends` cannot contain empty values",
+		},
+		"Missing Base": {
+			Data:          "apiVersion: \"1.4\"\nextends:\n  - missing.yaml\n",
+			ExpectedError: "reading extended definition 'missing.yaml'",
+		},
+		"Circular Extension": {
+			Data:          "apiVersion: \"1.4\"\nextends:\n  - cycle-a.yaml\n",
+			ExpectedError: "circular extension of definition 'cycle-a.yaml'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ComposeDefinition([]byte(test.Data), configDir)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, test.ExpectedError)
+		})
+	}
+}
+
+func TestParseDefinition_ExtendsRequiresComposition(t *testing.T) {
+	// Test
+	_, err := ParseDefinition([]byte("apiVersion: \"1.4\"\nextends:\n  - base.yaml\n"), "")
+
+	// Verify
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "field extends not found")
+}
@@ -24,35 +24,68 @@ type Context struct {
 	ArtifactSources *ArtifactSources
 	// CacheDir contains all of the artifacts that are cached for the build process.
 	CacheDir string
+	// CacheMaxSize is the maximum size of the cache in bytes. Zero means unlimited.
+	CacheMaxSize int64
+	// DownloadParallelism is the maximum number of artifacts downloaded concurrently.
+	// Zero means the default of the download scheduler.
+	DownloadParallelism int
 	// IsConfigDrive defines whether this is an image or config drive build
 	IsConfigDrive bool
+	// KubernetesRelease describes the Kubernetes release installed by the build, including the channel
+	// its version was resolved from. Nil if the definition does not configure Kubernetes.
+	KubernetesRelease *KubernetesRelease
+}
+
+// KubernetesRelease describes a release of RKE2 or K3s.
+type KubernetesRelease struct {
+	// Version is the exact version of the release, e.g. `v1.30.3+rke2r1`.
+	Version string `json:"version"`
+	// Channel is the channel expression the version was resolved from, e.g. `rke2:stable`.
+	Channel string `json:"channel,omitempty"`
+	// ReleaseIndex is the URL or path of the release index the channel was resolved against.
+	ReleaseIndex string `json:"releaseIndex,omitempty"`
 }
 
 type ArtifactSources struct {
 	MetalLB struct {
-		Chart      string `yaml:"chart"`
-		Repository string `yaml:"repository"`
-		Version    string `yaml:"version"`
-	} `yaml:"metallb"`
+		Chart      string `yaml:"chart" json:"chart"`
+		Repository string `yaml:"repository" json:"repository"`
+		Version    string `yaml:"version" json:"version"`
+	} `yaml:"metallb" json:"metallb"`
 	EndpointCopierOperator struct {
-		Chart      string `yaml:"chart"`
-		Repository string `yaml:"repository"`
-		Version    string `yaml:"version"`
-	} `yaml:"endpoint-copier-operator"`
+		Chart      string `yaml:"chart" json:"chart"`
+		Repository string `yaml:"repository" json:"repository"`
+		Version    string `yaml:"version" json:"version"`
+	} `yaml:"endpoint-copier-operator" json:"endpoint-copier-operator"`
+	KubeVIP struct {
+		Image string `yaml:"image" json:"image"`
+	} `yaml:"kube-vip" json:"kube-vip"`
 	Kubernetes struct {
 		K3s struct {
-			SELinuxPackage            string `yaml:"selinuxPackage"`
-			SELinuxRepository         string `yaml:"selinuxRepository"`
-			SELinuxRepositoryPriority int    `yaml:"selinuxRepositoryPriority"`
-			ReleaseURL                string `yaml:"releaseURL"`
-		} `yaml:"k3s"`
+			SELinuxPackage            string   `yaml:"selinuxPackage" json:"selinuxPackage"`
+			SELinuxRepository         string   `yaml:"selinuxRepository" json:"selinuxRepository"`
+			SELinuxRepositoryPriority int      `yaml:"selinuxRepositoryPriority" json:"selinuxRepositoryPriority"`
+			ReleaseURL                string   `yaml:"releaseURL" json:"releaseURL"`
+			Mirrors                   []string `yaml:"mirrors" json:"mirrors"`
+			ChannelsURL               string   `yaml:"channelsURL" json:"channelsURL"`
+		} `yaml:"k3s" json:"k3s"`
 		Rke2 struct {
-			SELinuxPackage            string `yaml:"selinuxPackage"`
-			SELinuxRepository         string `yaml:"selinuxRepository"`
-			SELinuxRepositoryPriority int    `yaml:"selinuxRepositoryPriority"`
-			ReleaseURL                string `yaml:"releaseURL"`
-		} `yaml:"rke2"`
-	} `yaml:"kubernetes"`
+			SELinuxPackage            string   `yaml:"selinuxPackage" json:"selinuxPackage"`
+			SELinuxRepository         string   `yaml:"selinuxRepository" json:"selinuxRepository"`
+			SELinuxRepositoryPriority int      `yaml:"selinuxRepositoryPriority" json:"selinuxRepositoryPriority"`
+			ReleaseURL                string   `yaml:"releaseURL" json:"releaseURL"`
+			Mirrors                   []string `yaml:"mirrors" json:"mirrors"`
+			ChannelsURL               string   `yaml:"channelsURL" json:"channelsURL"`
+		} `yaml:"rke2" json:"rke2"`
+	} `yaml:"kubernetes" json:"kubernetes"`
+	TLS []HostTLS `yaml:"tls" json:"tls"`
+}
+
+// HostTLS configures the TLS connections to a single host serving artifacts, charts or container images.
+type HostTLS struct {
+	Host               string `yaml:"host" json:"host"`
+	CAFile             string `yaml:"caFile" json:"caFile"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
 }
 
 func (c *Context) OutputPath() string {
@@ -26,6 +26,14 @@ const (
 	KubernetesNodeTypeServer = "server"
 	KubernetesNodeTypeAgent  = "agent"
 
+	VIPProviderMetalLB = "metallb"
+	VIPProviderKubeVIP = "kube-vip"
+	VIPProviderNone    = "none"
+
+	CRDHandlingSkip     = "skip"
+	CRDHandlingInclude  = "include"
+	CRDHandlingSeparate = "separate"
+
 	CNITypeNone        = "none"
 	CNITypeCilium      = "cilium"
 	CNITypeCanal       = "canal"
@@ -33,8 +41,14 @@ const (
 	IngressTypeTraefik = "traefik"
 )
 
+// DiskSizePattern is the format of the raw image disk size, e.g. `32G`.
+const DiskSizePattern = `^([1-9]\d+|[1-9])+([MGT])`
+
+// ImagePathPattern is the format of the image paths of custom resources, e.g. `spec.components[*].image`.
+const ImagePathPattern = `^[^.\[\]\s]+(\[\*\])?(\.[^.\[\]\s]+(\[\*\])?)*$`
+
 var (
-	diskSizeRegexp = regexp.MustCompile(`^([1-9]\d+|[1-9])+([MGT])`)
+	diskSizeRegexp = regexp.MustCompile(DiskSizePattern)
 )
 
 type Definition struct {
@@ -43,6 +57,7 @@ type Definition struct {
 	OperatingSystem          OperatingSystem          `yaml:"operatingSystem"`
 	EmbeddedArtifactRegistry EmbeddedArtifactRegistry `yaml:"embeddedArtifactRegistry"`
 	Kubernetes               Kubernetes               `yaml:"kubernetes"`
+	NodeOverrides            []NodeOverride           `yaml:"nodeOverrides"`
 }
 
 type Arch string
@@ -185,6 +200,16 @@ type Proxy struct {
 type EmbeddedArtifactRegistry struct {
 	ContainerImages []ContainerImage `yaml:"images"`
 	Registries      []Registry       `yaml:"registries"`
+	ImageRules      []ImageRule      `yaml:"imageRules"`
+}
+
+// ImageRule extracts the container images referenced by the manifests and Helm charts beyond the containers
+// of their workloads. The selector locates the values referencing the images in the resources of the given kind,
+// e.g. `spec.imageName`, and the optional template builds the image from each of them, e.g. `{{ .repository }}:{{ .version }}`.
+type ImageRule struct {
+	Kind     string `yaml:"kind"`
+	Selector string `yaml:"selector"`
+	Template string `yaml:"template"`
 }
 
 type ContainerImage struct {
@@ -213,12 +238,45 @@ type Network struct {
 	APIHost string `yaml:"apiHost"`
 	APIVIP4 string `yaml:"apiVIP"`
 	APIVIP6 string `yaml:"apiVIP6"`
+	// VIPProvider selects the component serving the API VIPs, one of `metallb` (default), `kube-vip` or `none`.
+	VIPProvider string `yaml:"vipProvider"`
+}
+
+// APIVIPProvider returns the component serving the API VIPs, or an empty string if no VIP is configured.
+func (n *Network) APIVIPProvider() string {
+	if n.APIVIP4 == "" && n.APIVIP6 == "" {
+		return ""
+	}
+
+	if n.VIPProvider == "" {
+		return VIPProviderMetalLB
+	}
+
+	return n.VIPProvider
 }
 
 type Node struct {
 	Hostname    string `yaml:"hostname"`
 	Type        string `yaml:"type"`
 	Initialiser bool   `yaml:"initializer"`
+	// Labels are registered with the node when it joins the cluster.
+	Labels map[string]string `yaml:"labels"`
+	// Taints are registered with the node when it joins the cluster, in the `key[=value]:effect` format.
+	Taints []string `yaml:"taints"`
+	// Config holds Kubernetes configuration options which override the ones shared by all nodes of the same type.
+	Config map[string]any `yaml:"config"`
+}
+
+type NodeOverride struct {
+	Hostname        string                      `yaml:"hostname"`
+	OperatingSystem OperatingSystemNodeOverride `yaml:"operatingSystem"`
+}
+
+type OperatingSystemNodeOverride struct {
+	KernelArgs []string               `yaml:"kernelArgs"`
+	Groups     []OperatingSystemGroup `yaml:"groups"`
+	Users      []OperatingSystemUser  `yaml:"users"`
+	Systemd    Systemd                `yaml:"systemd"`
 }
 
 type Manifests struct {
@@ -231,15 +289,29 @@ type Helm struct {
 }
 
 type HelmChart struct {
-	Name                  string   `yaml:"name"`
-	ReleaseName           string   `yaml:"releaseName"`
-	RepositoryName        string   `yaml:"repositoryName"`
-	Version               string   `yaml:"version"`
-	TargetNamespace       string   `yaml:"targetNamespace"`
-	CreateNamespace       bool     `yaml:"createNamespace"`
-	InstallationNamespace string   `yaml:"installationNamespace"`
-	ValuesFile            string   `yaml:"valuesFile"`
-	APIVersions           []string `yaml:"apiVersions"`
+	Name                  string      `yaml:"name"`
+	ReleaseName           string      `yaml:"releaseName"`
+	RepositoryName        string      `yaml:"repositoryName"`
+	Version               string      `yaml:"version"`
+	TargetNamespace       string      `yaml:"targetNamespace"`
+	CreateNamespace       bool        `yaml:"createNamespace"`
+	InstallationNamespace string      `yaml:"installationNamespace"`
+	ValuesFile            string      `yaml:"valuesFile"`
+	APIVersions           []string    `yaml:"apiVersions"`
+	CRDHandling           string      `yaml:"crdHandling"`
+	ImagePaths            []ImagePath `yaml:"imagePaths"`
+}
+
+// IncludeCRDs reports whether the CRDs of the chart are rendered along with its templates.
+func (c *HelmChart) IncludeCRDs() bool {
+	return c.CRDHandling == CRDHandlingInclude || c.CRDHandling == CRDHandlingSeparate
+}
+
+// ImagePath locates the container images referenced by custom resources of the given kind,
+// e.g. `spec.image` or `spec.components[*].image`.
+type ImagePath struct {
+	Kind string `yaml:"kind"`
+	Path string `yaml:"path"`
 }
 
 type HelmRepository struct {
@@ -258,7 +330,14 @@ type HelmAuthentication struct {
 
 var ErrorInvalidSchemaVersion = errors.New("invalid schema version")
 
-func ParseDefinition(data []byte) (*Definition, error) {
+// ParseDefinition decodes the image definition, resolving any secret references it contains.
+// Secret files are located relative to the given image configuration directory.
+func ParseDefinition(data []byte, configDir string) (*Definition, error) {
+	data, err := resolveDefinitionSecrets(data, configDir)
+	if err != nil {
+		return nil, err
+	}
+
 	var definition Definition
 
 	decoder := yaml.NewDecoder(bytes.NewReader(data))
@@ -275,3 +354,33 @@ func ParseDefinition(data []byte) (*Definition, error) {
 
 	return &definition, nil
 }
+
+func resolveDefinitionSecrets(data []byte, configDir string) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil || len(document.Content) == 0 {
+		// Leave reporting of malformed definitions to the strict decoding
+		return data, nil
+	}
+
+	root := document.Content[0]
+
+	found, err := resolveSecrets(root, configDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the image definition: %w", err)
+	}
+
+	if !found {
+		return data, nil
+	}
+
+	if v := mappingValue(root, apiVersionKey); v != nil && strings.Compare(v.Value, SecretsAPIVersion) < 0 {
+		return nil, fmt.Errorf("could not parse the image definition: secret references are only available in API version >= %s", SecretsAPIVersion)
+	}
+
+	resolved, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, fmt.Errorf("encoding resolved definition: %w", err)
+	}
+
+	return resolved, nil
+}
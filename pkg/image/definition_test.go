@@ -15,7 +15,7 @@ func TestParse(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test
-	definition, err := ParseDefinition(configData)
+	definition, err := ParseDefinition(configData, "")
 
 	// Verify
 	require.NoError(t, err)
@@ -243,7 +243,7 @@ func TestParseBadConfig_InvalidFormat(t *testing.T) {
 	badData := []byte("Not actually YAML")
 
 	// Test
-	_, err := ParseDefinition(badData)
+	_, err := ParseDefinition(badData, "")
 
 	// Verify
 	require.Error(t, err)
@@ -261,7 +261,7 @@ operatingSystem:
     zone: Europe/London
 `
 
-	_, err := ParseDefinition([]byte(badConfig))
+	_, err := ParseDefinition([]byte(badConfig), "")
 
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "could not parse the image definition")
@@ -274,7 +274,7 @@ func TestParseDefinition_InvalidSchemaVersion(t *testing.T) {
 apiVersion: 10.0
 `
 
-	_, err := ParseDefinition([]byte(badConfig))
+	_, err := ParseDefinition([]byte(badConfig), "")
 
 	require.ErrorIs(t, err, ErrorInvalidSchemaVersion)
 }
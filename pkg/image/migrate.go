@@ -0,0 +1,257 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"gopkg.in/yaml.v3"
+)
+
+// MigrationNote describes a change applied to a definition during a migration,
+// or a change in behavior the user should review.
+type MigrationNote struct {
+	// Version is the API version which introduced the change.
+	Version string
+	// Path is the location of the affected field in the definition, if any.
+	Path    string
+	Message string
+}
+
+func (n MigrationNote) String() string {
+	if n.Path == "" {
+		return fmt.Sprintf("[%s] %s", n.Version, n.Message)
+	}
+
+	return fmt.Sprintf("[%s] %s: %s", n.Version, n.Path, n.Message)
+}
+
+// migrationStep transforms a definition from the previous API version to the given one.
+type migrationStep struct {
+	version string
+	migrate func(root *yaml.Node) []MigrationNote
+}
+
+// migrationSteps lists the transformations needed between consecutive API versions.
+// Every version is registered, since versions compatible with their predecessor may still
+// change the behavior of existing definitions in ways which cannot be migrated automatically.
+var migrationSteps = []migrationStep{
+	{version: "1.1", migrate: migrateTo11},
+	{version: "1.2", migrate: migrateTo12},
+	{version: "1.3", migrate: migrateTo13},
+	{version: "1.4", migrate: migrateTo14},
+}
+
+// MigrateDefinition upgrades the definition to the target API version by applying the
+// transformation steps of every version in between. Comments and key ordering are preserved.
+//
+// The returned notes describe the applied changes, along with any changes in behavior
+// between the versions which cannot be migrated automatically.
+func MigrateDefinition(data []byte, targetVersion string) ([]byte, []MigrationNote, error) {
+	if !version.IsSchemaVersionSupported(targetVersion) {
+		return nil, nil, fmt.Errorf("unsupported target API version: %s", targetVersion)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("could not parse the image definition: %w", err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("could not parse the image definition: expected a map")
+	}
+
+	root := document.Content[0]
+
+	apiVersion := mappingValue(root, apiVersionKey)
+	if apiVersion == nil {
+		return nil, nil, fmt.Errorf("field `%s` must be defined", apiVersionKey)
+	}
+
+	currentVersion := apiVersion.Value
+	if !version.IsSchemaVersionSupported(currentVersion) {
+		return nil, nil, ErrorInvalidSchemaVersion
+	}
+
+	switch strings.Compare(currentVersion, targetVersion) {
+	case 0:
+		return data, nil, nil
+	case 1:
+		return nil, nil, fmt.Errorf("definitions cannot be migrated to an older API version (%s -> %s)", currentVersion, targetVersion)
+	}
+
+	var notes []MigrationNote
+
+	for _, step := range migrationSteps {
+		if strings.Compare(step.version, currentVersion) <= 0 || strings.Compare(step.version, targetVersion) > 0 {
+			continue
+		}
+
+		notes = append(notes, step.migrate(root)...)
+	}
+
+	apiVersion.Value = targetVersion
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&document); err != nil {
+		return nil, nil, fmt.Errorf("encoding migrated definition: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, nil, fmt.Errorf("encoding migrated definition: %w", err)
+	}
+
+	return buf.Bytes(), notes, nil
+}
+
+func migrateTo11(root *yaml.Node) []MigrationNote {
+	const stepVersion = "1.1"
+
+	notes := []MigrationNote{
+		{
+			Version: stepVersion,
+			Path:    "image.baseImage",
+			Message: "Base images must be SL Micro 6.0 or newer, SLE Micro 5.5 base images are only supported by EIB 1.0.x",
+		},
+	}
+
+	if mappingPath(root, "kubernetes", "helm", "charts") != nil {
+		notes = append(notes, MigrationNote{
+			Version: stepVersion,
+			Path:    "kubernetes.helm.charts",
+			Message: "Helm charts are no longer installed with the '-chart' suffix, " +
+				"resources referring to the previous HelmChart names must be updated",
+		})
+	}
+
+	if mappingPath(root, "operatingSystem", "packages", "sccRegistrationCode") == nil {
+		notes = append(notes, MigrationNote{
+			Version: stepVersion,
+			Path:    "operatingSystem.packages.sccRegistrationCode",
+			Message: "Elemental configurations require a registration code to install the Elemental RPMs, " +
+				"unless the RPMs are side-loaded in the image configuration directory",
+		})
+	}
+
+	operatingSystem := mappingValue(root, "operatingSystem")
+	if operatingSystem == nil || operatingSystem.Kind != yaml.MappingNode {
+		return notes
+	}
+
+	// FIPS mode is enabled through a dedicated field instead of the kernel arguments
+	kernelArgs := mappingValue(operatingSystem, "kernelArgs")
+	if kernelArgs == nil || kernelArgs.Kind != yaml.SequenceNode {
+		return notes
+	}
+
+	fipsIndex := slices.IndexFunc(kernelArgs.Content, func(n *yaml.Node) bool {
+		return n.Value == "fips=1"
+	})
+	if fipsIndex < 0 {
+		return notes
+	}
+
+	kernelArgs.Content = slices.Delete(kernelArgs.Content, fipsIndex, fipsIndex+1)
+	if len(kernelArgs.Content) == 0 {
+		removeMappingKey(operatingSystem, "kernelArgs")
+	}
+
+	if enableFIPS := mappingValue(operatingSystem, "enableFIPS"); enableFIPS != nil {
+		enableFIPS.Tag = "!!bool"
+		enableFIPS.Value = "true"
+	} else {
+		operatingSystem.Content = append(operatingSystem.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "enableFIPS"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+		)
+	}
+
+	notes = append(notes, MigrationNote{
+		Version: stepVersion,
+		Path:    "operatingSystem.enableFIPS",
+		Message: "Replaced the 'fips=1' kernel argument with the dedicated 'enableFIPS' field",
+	})
+
+	return notes
+}
+
+func migrateTo12(root *yaml.Node) []MigrationNote {
+	const stepVersion = "1.2"
+
+	var notes []MigrationNote
+
+	if mappingPath(root, "kubernetes", "network", "apiVIP") != nil {
+		notes = append(notes, MigrationNote{
+			Version: stepVersion,
+			Path:    "kubernetes.network.apiVIP",
+			Message: "The API VIP is served by MetalLB 0.1.0+up0.14.9 (upgraded from 0.14.9), " +
+				"MetalLB resources provided alongside the definition should be reviewed",
+		})
+	}
+
+	return notes
+}
+
+func migrateTo13(root *yaml.Node) []MigrationNote {
+	const stepVersion = "1.3"
+
+	var notes []MigrationNote
+
+	if mappingValue(root, "kubernetes") != nil {
+		notes = append(notes, MigrationNote{
+			Version: stepVersion,
+			Path:    "kubernetes",
+			Message: "HelmChartConfig resources should be placed in '/var/lib/rancher/{rke2/k3s}/server/manifests' " +
+				"using the 'os-files' directory rather than in the 'kubernetes/manifests' directory",
+		})
+	}
+
+	return notes
+}
+
+func migrateTo14(root *yaml.Node) []MigrationNote {
+	const stepVersion = "1.4"
+
+	var notes []MigrationNote
+
+	if mappingPath(root, "kubernetes", "helm", "charts") != nil || mappingPath(root, "kubernetes", "manifests") != nil {
+		notes = append(notes, MigrationNote{
+			Version: stepVersion,
+			Path:    "embeddedArtifactRegistry",
+			Message: "Container images referenced by the custom resources of common operators and by " +
+				"'RELATED_IMAGE_*' environment variables are embedded in the artifact registry, " +
+				"use the 'images' command to review the embedded images",
+		})
+	}
+
+	return notes
+}
+
+// mappingPath returns the value of the nested fields, or nil if any of them is missing.
+func mappingPath(root *yaml.Node, keys ...string) *yaml.Node {
+	node := root
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		node = mappingValue(node, key)
+	}
+
+	return node
+}
+
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+	}
+}
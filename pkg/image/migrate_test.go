@@ -0,0 +1,211 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDefinition(t *testing.T) {
+	definition := `# Edge cluster definition
+apiVersion: "1.0"
+image:
+  imageType: iso
+  arch: x86_64
+  # SL Micro base image
+  baseImage: SL-Micro.x86_64-6.0-Base-SelfInstall-GM2.install.iso
+  outputImageName: eib-image.iso
+operatingSystem:
+  kernelArgs:
+    - fips=1
+    - console=ttyS0 # serial console
+  users:
+    - username: root
+      encryptedPassword: $6$jHugJNNd3HElGsUZ$eodjVe4te5ps44SVcWshdfWizrP.xAyd71CVEXazBJ/.v799/WRCBXxfYmunlBO2yp1hm/zb4r8EmnrrNCF.P/
+`
+
+	expected := `# Edge cluster definition
+apiVersion: "1.4"
+image:
+  imageType: iso
+  arch: x86_64
+  # SL Micro base image
+  baseImage: SL-Micro.x86_64-6.0-Base-SelfInstall-GM2.install.iso
+  outputImageName: eib-image.iso
+operatingSystem:
+  kernelArgs:
+    - console=ttyS0 # serial console
+  users:
+    - username: root
+      encryptedPassword: $6$jHugJNNd3HElGsUZ$eodjVe4te5ps44SVcWshdfWizrP.xAyd71CVEXazBJ/.v799/WRCBXxfYmunlBO2yp1hm/zb4r8EmnrrNCF.P/
+  enableFIPS: true
+`
+
+	migrated, notes, err := MigrateDefinition([]byte(definition), "1.4")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, string(migrated))
+	assert.Equal(t, []MigrationNote{
+		{
+			Version: "1.1",
+			Path:    "image.baseImage",
+			Message: "Base images must be SL Micro 6.0 or newer, SLE Micro 5.5 base images are only supported by EIB 1.0.x",
+		},
+		{
+			Version: "1.1",
+			Path:    "operatingSystem.packages.sccRegistrationCode",
+			Message: "Elemental configurations require a registration code to install the Elemental RPMs, " +
+				"unless the RPMs are side-loaded in the image configuration directory",
+		},
+		{
+			Version: "1.1",
+			Path:    "operatingSystem.enableFIPS",
+			Message: "Replaced the 'fips=1' kernel argument with the dedicated 'enableFIPS' field",
+		},
+	}, notes)
+
+	def, err := ParseDefinition(migrated, "")
+	require.NoError(t, err)
+
+	assert.True(t, def.OperatingSystem.EnableFIPS)
+	assert.Equal(t, []string{"console=ttyS0"}, def.OperatingSystem.KernelArgs)
+}
+
+func TestMigrateDefinitionFIPSOnlyKernelArgument(t *testing.T) {
+	definition := `apiVersion: "1.0"
+operatingSystem:
+  kernelArgs:
+    - fips=1
+  enableFIPS: false
+`
+
+	migrated, _, err := MigrateDefinition([]byte(definition), "1.1")
+	require.NoError(t, err)
+
+	expected := `apiVersion: "1.1"
+operatingSystem:
+  enableFIPS: true
+`
+	assert.Equal(t, expected, string(migrated))
+}
+
+func TestMigrateDefinitionNotesOnly(t *testing.T) {
+	definition := `apiVersion: "1.2"
+kubernetes:
+  version: v1.30.3+k3s1
+`
+
+	migrated, notes, err := MigrateDefinition([]byte(definition), "1.3")
+	require.NoError(t, err)
+
+	assert.Equal(t, "apiVersion: \"1.3\"\nkubernetes:\n  version: v1.30.3+k3s1\n", string(migrated))
+	assert.Equal(t, []MigrationNote{
+		{
+			Version: "1.3",
+			Path:    "kubernetes",
+			Message: "HelmChartConfig resources should be placed in '/var/lib/rancher/{rke2/k3s}/server/manifests' " +
+				"using the 'os-files' directory rather than in the 'kubernetes/manifests' directory",
+		},
+	}, notes)
+}
+
+func TestMigrateDefinitionAllVersions(t *testing.T) {
+	definition := `apiVersion: "1.0"
+image:
+  imageType: raw
+  arch: x86_64
+  baseImage: SL-Micro.x86_64-6.0-Base-GM2.raw
+  outputImageName: eib-image.raw
+operatingSystem:
+  packages:
+    sccRegistrationCode: registration-code
+kubernetes:
+  version: v1.30.3+rke2r1
+  network:
+    apiVIP: 192.168.122.100
+  helm:
+    charts:
+      - name: apache
+        repositoryName: apache-repo
+        version: 10.7.0
+    repositories:
+      - name: apache-repo
+        url: oci://registry-1.docker.io/bitnamicharts
+`
+
+	migrated, notes, err := MigrateDefinition([]byte(definition), "1.4")
+	require.NoError(t, err)
+
+	var versions, paths []string
+	for _, note := range notes {
+		versions = append(versions, note.Version)
+		paths = append(paths, note.Path)
+	}
+
+	assert.Equal(t, []string{"1.1", "1.1", "1.2", "1.3", "1.4"}, versions)
+	assert.Equal(t, []string{
+		"image.baseImage",
+		"kubernetes.helm.charts",
+		"kubernetes.network.apiVIP",
+		"kubernetes",
+		"embeddedArtifactRegistry",
+	}, paths)
+
+	def, err := ParseDefinition(migrated, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.4", def.APIVersion)
+	assert.Equal(t, "registration-code", def.OperatingSystem.Packages.RegCode)
+}
+
+func TestMigrateDefinitionSameVersion(t *testing.T) {
+	definition := "apiVersion: \"1.3\"\n# unchanged\n"
+
+	migrated, notes, err := MigrateDefinition([]byte(definition), "1.3")
+	require.NoError(t, err)
+
+	assert.Equal(t, definition, string(migrated))
+	assert.Empty(t, notes)
+}
+
+func TestMigrateDefinitionErrors(t *testing.T) {
+	tests := map[string]struct {
+		definition    string
+		targetVersion string
+		expectedError string
+	}{
+		"unsupported target version": {
+			definition:    `apiVersion: "1.0"`,
+			targetVersion: "2.0",
+			expectedError: "unsupported target API version: 2.0",
+		},
+		"unsupported source version": {
+			definition:    `apiVersion: "0.9"`,
+			targetVersion: "1.4",
+			expectedError: "invalid schema version",
+		},
+		"missing API version": {
+			definition:    "image:\n  arch: x86_64\n",
+			targetVersion: "1.4",
+			expectedError: "field `apiVersion` must be defined",
+		},
+		"downgrade": {
+			definition:    `apiVersion: "1.3"`,
+			targetVersion: "1.1",
+			expectedError: "definitions cannot be migrated to an older API version (1.3 -> 1.1)",
+		},
+		"malformed definition": {
+			definition:    "- apiVersion",
+			targetVersion: "1.4",
+			expectedError: "could not parse the image definition: expected a map",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := MigrateDefinition([]byte(test.definition), test.targetVersion)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package image
+
+import (
+	"slices"
+	"strings"
+)
+
+// NodeOverride returns the override for the given hostname or nil if no such override exists.
+func (d *Definition) NodeOverride(hostname string) *NodeOverride {
+	for i := range d.NodeOverrides {
+		if d.NodeOverrides[i].Hostname == hostname {
+			return &d.NodeOverrides[i]
+		}
+	}
+
+	return nil
+}
+
+// NodeOperatingSystem returns the effective operating system configuration for the given hostname.
+//
+// Fields specified in the node override are merged over the shared operating system configuration as follows:
+//   - Groups and users replace entries with the same name and are otherwise appended
+//   - Kernel arguments replace arguments with the same key and are otherwise appended
+//   - Systemd units are appended, with a unit enabled (disabled) by the node being removed
+//     from the shared list of disabled (enabled) units
+//
+// The shared configuration is returned as-is if there is no override for the given hostname.
+func (d *Definition) NodeOperatingSystem(hostname string) *OperatingSystem {
+	override := d.NodeOverride(hostname)
+	if override == nil {
+		return &d.OperatingSystem
+	}
+
+	os := d.OperatingSystem
+	os.KernelArgs = mergeKernelArgs(d.OperatingSystem.KernelArgs, override.OperatingSystem.KernelArgs)
+	os.Groups = mergeGroups(d.OperatingSystem.Groups, override.OperatingSystem.Groups)
+	os.Users = mergeUsers(d.OperatingSystem.Users, override.OperatingSystem.Users)
+	os.Systemd = mergeSystemd(&d.OperatingSystem.Systemd, &override.OperatingSystem.Systemd)
+
+	return &os
+}
+
+func mergeKernelArgs(shared, node []string) []string {
+	if len(node) == 0 {
+		return shared
+	}
+
+	argKey := func(arg string) string {
+		key, _, _ := strings.Cut(arg, "=")
+		return key
+	}
+
+	var args []string
+	for _, arg := range shared {
+		if !slices.ContainsFunc(node, func(nodeArg string) bool { return argKey(nodeArg) == argKey(arg) }) {
+			args = append(args, arg)
+		}
+	}
+
+	return append(args, node...)
+}
+
+func mergeGroups(shared, node []OperatingSystemGroup) []OperatingSystemGroup {
+	if len(node) == 0 {
+		return shared
+	}
+
+	var groups []OperatingSystemGroup
+	for _, group := range shared {
+		if !slices.ContainsFunc(node, func(g OperatingSystemGroup) bool { return g.Name == group.Name }) {
+			groups = append(groups, group)
+		}
+	}
+
+	return append(groups, node...)
+}
+
+func mergeUsers(shared, node []OperatingSystemUser) []OperatingSystemUser {
+	if len(node) == 0 {
+		return shared
+	}
+
+	var users []OperatingSystemUser
+	for _, user := range shared {
+		if !slices.ContainsFunc(node, func(u OperatingSystemUser) bool { return u.Username == user.Username }) {
+			users = append(users, user)
+		}
+	}
+
+	return append(users, node...)
+}
+
+func mergeSystemd(shared, node *Systemd) Systemd {
+	var systemd Systemd
+
+	for _, unit := range shared.Enable {
+		if !slices.Contains(node.Disable, unit) {
+			systemd.Enable = append(systemd.Enable, unit)
+		}
+	}
+
+	for _, unit := range shared.Disable {
+		if !slices.Contains(node.Enable, unit) {
+			systemd.Disable = append(systemd.Disable, unit)
+		}
+	}
+
+	for _, unit := range node.Enable {
+		if !slices.Contains(systemd.Enable, unit) {
+			systemd.Enable = append(systemd.Enable, unit)
+		}
+	}
+
+	for _, unit := range node.Disable {
+		if !slices.Contains(systemd.Disable, unit) {
+			systemd.Disable = append(systemd.Disable, unit)
+		}
+	}
+
+	return systemd
+}
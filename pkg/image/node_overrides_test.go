@@ -0,0 +1,83 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeOperatingSystem(t *testing.T) {
+	def := Definition{
+		OperatingSystem: OperatingSystem{
+			KernelArgs: []string{"alpha=foo", "beta=bar", "baz"},
+			Groups: []OperatingSystemGroup{
+				{Name: "group1"},
+				{Name: "group2", GID: 1000},
+			},
+			Users: []OperatingSystemUser{
+				{Username: "alpha", EncryptedPassword: "alpha123"},
+				{Username: "beta", EncryptedPassword: "beta123"},
+			},
+			Systemd: Systemd{
+				Enable:  []string{"enable0", "enable1"},
+				Disable: []string{"disable0"},
+			},
+			Keymap: "us",
+		},
+		NodeOverrides: []NodeOverride{
+			{
+				Hostname: "node1.suse.com",
+				OperatingSystem: OperatingSystemNodeOverride{
+					KernelArgs: []string{"beta=qux", "gamma"},
+					Groups: []OperatingSystemGroup{
+						{Name: "group2", GID: 2000},
+						{Name: "group3"},
+					},
+					Users: []OperatingSystemUser{
+						{Username: "beta", EncryptedPassword: "beta456"},
+						{Username: "gamma", EncryptedPassword: "gamma123"},
+					},
+					Systemd: Systemd{
+						Enable:  []string{"disable0", "enable2"},
+						Disable: []string{"enable1"},
+					},
+				},
+			},
+			{
+				Hostname: "node2.suse.com",
+			},
+		},
+	}
+
+	os := def.NodeOperatingSystem("node1.suse.com")
+	require.NotNil(t, os)
+
+	assert.Equal(t, []string{"alpha=foo", "baz", "beta=qux", "gamma"}, os.KernelArgs)
+	assert.Equal(t, []OperatingSystemGroup{
+		{Name: "group1"},
+		{Name: "group2", GID: 2000},
+		{Name: "group3"},
+	}, os.Groups)
+	assert.Equal(t, []OperatingSystemUser{
+		{Username: "alpha", EncryptedPassword: "alpha123"},
+		{Username: "beta", EncryptedPassword: "beta456"},
+		{Username: "gamma", EncryptedPassword: "gamma123"},
+	}, os.Users)
+	assert.Equal(t, []string{"enable0", "disable0", "enable2"}, os.Systemd.Enable)
+	assert.Equal(t, []string{"enable1"}, os.Systemd.Disable)
+	assert.Equal(t, "us", os.Keymap)
+
+	// The shared configuration must not be modified
+	assert.Equal(t, []string{"alpha=foo", "beta=bar", "baz"}, def.OperatingSystem.KernelArgs)
+	assert.Equal(t, []string{"enable0", "enable1"}, def.OperatingSystem.Systemd.Enable)
+
+	os = def.NodeOperatingSystem("node2.suse.com")
+	assert.Equal(t, def.OperatingSystem.KernelArgs, os.KernelArgs)
+	assert.Equal(t, def.OperatingSystem.Users, os.Users)
+	assert.Equal(t, def.OperatingSystem.Groups, os.Groups)
+	assert.Equal(t, def.OperatingSystem.Systemd, os.Systemd)
+
+	os = def.NodeOperatingSystem("node3.suse.com")
+	assert.Same(t, &def.OperatingSystem, os)
+}
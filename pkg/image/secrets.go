@@ -0,0 +1,160 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	secretFromEnvKey  = "fromEnv"
+	secretFromFileKey = "fromFile"
+
+	// anyElement matches each of the elements of a list in a secret field path.
+	anyElement = "*"
+)
+
+// SecretsAPIVersion is the API version which introduced secret references.
+const SecretsAPIVersion = "1.4"
+
+// secretFields lists the paths to the fields which may be specified
+// using a secret reference instead of a plaintext value.
+var secretFields = [][]string{
+	{"operatingSystem", "packages", "sccRegistrationCode"},
+	{"operatingSystem", "suma", "activationKey"},
+	{"operatingSystem", "rawConfiguration", "luksKey"},
+	{"kubernetes", "helm", "repositories", anyElement, "authentication", "password"},
+	{"embeddedArtifactRegistry", "registries", anyElement, "authentication", "password"},
+}
+
+// SecretFieldPaths returns the paths to the fields which may be specified using a secret reference,
+// e.g. `kubernetes.helm.repositories.*.authentication.password`, where `*` matches each list element.
+func SecretFieldPaths() []string {
+	var paths []string
+	for _, path := range secretFields {
+		paths = append(paths, strings.Join(path, "."))
+	}
+
+	return paths
+}
+
+// resolveSecrets replaces all secret references in the definition with the values they point to.
+// Secret files are located relative to the image configuration directory.
+//
+// Returns whether any references were found. The resolved values are intentionally
+// left out of any returned errors so that they cannot be leaked in the logs.
+func resolveSecrets(root *yaml.Node, configDir string) (bool, error) {
+	var found bool
+
+	for _, path := range secretFields {
+		for _, node := range findNodes(root, path) {
+			if node.Kind != yaml.MappingNode {
+				continue
+			}
+
+			field := strings.Join(path, ".")
+
+			value, err := resolveSecret(node, configDir)
+			if err != nil {
+				return false, fmt.Errorf("resolving secret for field '%s': %w", field, err)
+			}
+
+			*node = yaml.Node{
+				Kind:  yaml.ScalarNode,
+				Tag:   "!!str",
+				Value: value,
+			}
+			found = true
+		}
+	}
+
+	return found, nil
+}
+
+func resolveSecret(node *yaml.Node, configDir string) (string, error) {
+	if len(node.Content) != 2 {
+		return "", fmt.Errorf("secret reference must specify exactly one of '%s' or '%s'", secretFromEnvKey, secretFromFileKey)
+	}
+
+	key, value := node.Content[0].Value, node.Content[1].Value
+	if value == "" {
+		return "", fmt.Errorf("secret reference '%s' cannot be empty", key)
+	}
+
+	switch key {
+	case secretFromEnvKey:
+		secret, ok := os.LookupEnv(value)
+		if !ok {
+			return "", fmt.Errorf("environment variable '%s' is not set", value)
+		}
+
+		return secret, nil
+	case secretFromFileKey:
+		path := value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(configDir, path)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			var pathErr *os.PathError
+			if errors.As(err, &pathErr) {
+				return "", fmt.Errorf("reading secret file '%s': %w", value, pathErr.Err)
+			}
+
+			return "", fmt.Errorf("reading secret file '%s'", value)
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("unknown secret reference '%s', must be one of '%s' or '%s'", key, secretFromEnvKey, secretFromFileKey)
+	}
+}
+
+func findNodes(node *yaml.Node, path []string) []*yaml.Node {
+	if len(path) == 0 {
+		return []*yaml.Node{node}
+	}
+
+	var nodes []*yaml.Node
+
+	switch {
+	case path[0] == anyElement && node.Kind == yaml.SequenceNode:
+		for _, n := range node.Content {
+			nodes = append(nodes, findNodes(n, path[1:])...)
+		}
+	case node.Kind == yaml.MappingNode:
+		if n := mappingValue(node, path[0]); n != nil {
+			nodes = findNodes(n, path[1:])
+		}
+	}
+
+	return nodes
+}
+
+// Secrets returns the values of all sensitive fields in the definition,
+// regardless of whether they have been specified using a secret reference.
+func (d *Definition) Secrets() []string {
+	var values []string
+
+	operatingSystem := d.OperatingSystem
+	values = append(values,
+		operatingSystem.Packages.RegCode,
+		operatingSystem.Suma.ActivationKey,
+		operatingSystem.RawConfiguration.LUKSKey)
+
+	for _, repo := range d.Kubernetes.Helm.Repositories {
+		values = append(values, repo.Authentication.Password)
+	}
+
+	for _, registry := range d.EmbeddedArtifactRegistry.Registries {
+		values = append(values, registry.Authentication.Password)
+	}
+
+	return slices.DeleteFunc(values, func(v string) bool { return v == "" })
+}
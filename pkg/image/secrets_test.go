@@ -0,0 +1,150 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secretsDefinition = `
+apiVersion: "1.4"
+operatingSystem:
+  packages:
+    sccRegistrationCode:
+      fromEnv: EIB_TEST_REGCODE
+  suma:
+    host: suma.suse.com
+    activationKey:
+      fromFile: secrets/suma.key
+  rawConfiguration:
+    luksKey: plaintext-luks
+kubernetes:
+  helm:
+    repositories:
+      - name: first
+        url: https://first.suse.com
+        authentication:
+          username: user
+          password:
+            fromEnv: EIB_TEST_HELM_PASS
+      - name: second
+        url: https://second.suse.com
+embeddedArtifactRegistry:
+  registries:
+    - uri: registry.suse.com
+      authentication:
+        username: user
+        password:
+          fromFile: secrets/registry.pass
+`
+
+func TestParseDefinition_Secrets(t *testing.T) {
+	// Setup
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "secrets"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "secrets", "suma.key"), []byte("suma-key\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "secrets", "registry.pass"), []byte("registry-pass"), 0o600))
+
+	t.Setenv("EIB_TEST_REGCODE", "regcode")
+	t.Setenv("EIB_TEST_HELM_PASS", "helm-pass")
+
+	// Test
+	definition, err := ParseDefinition([]byte(secretsDefinition), configDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	assert.Equal(t, "regcode", definition.OperatingSystem.Packages.RegCode)
+	assert.Equal(t, "suma-key", definition.OperatingSystem.Suma.ActivationKey)
+	assert.Equal(t, "plaintext-luks", definition.OperatingSystem.RawConfiguration.LUKSKey)
+
+	require.Len(t, definition.Kubernetes.Helm.Repositories, 2)
+	assert.Equal(t, "helm-pass", definition.Kubernetes.Helm.Repositories[0].Authentication.Password)
+	assert.Equal(t, "", definition.Kubernetes.Helm.Repositories[1].Authentication.Password)
+
+	require.Len(t, definition.EmbeddedArtifactRegistry.Registries, 1)
+	assert.Equal(t, "registry-pass", definition.EmbeddedArtifactRegistry.Registries[0].Authentication.Password)
+}
+
+func TestParseDefinition_SecretErrors(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("EIB_TEST_SECRET", "top-secret")
+
+	tests := map[string]struct {
+		Definition    string
+		ExpectedError string
+	}{
+		"Unset Environment Variable": {
+			Definition: `
+apiVersion: "1.4"
+operatingSystem:
+  packages:
+    sccRegistrationCode:
+      fromEnv: EIB_TEST_UNSET
+`,
+			ExpectedError: "resolving secret for field 'operatingSystem.packages.sccRegistrationCode': environment variable 'EIB_TEST_UNSET' is not set",
+		},
+		"Missing File": {
+			Definition: `
+apiVersion: "1.4"
+operatingSystem:
+  suma:
+    activationKey:
+      fromFile: missing.key
+`,
+			ExpectedError: "resolving secret for field 'operatingSystem.suma.activationKey': reading secret file 'missing.key': no such file or directory",
+		},
+		"Unknown Reference": {
+			Definition: `
+apiVersion: "1.4"
+operatingSystem:
+  rawConfiguration:
+    luksKey:
+      fromVault: luks
+`,
+			ExpectedError: "unknown secret reference 'fromVault', must be one of 'fromEnv' or 'fromFile'",
+		},
+		"Multiple References": {
+			Definition: `
+apiVersion: "1.4"
+operatingSystem:
+  rawConfiguration:
+    luksKey:
+      fromEnv: EIB_TEST_SECRET
+      fromFile: luks.key
+`,
+			ExpectedError: "secret reference must specify exactly one of 'fromEnv' or 'fromFile'",
+		},
+		"Unsupported API Version": {
+			Definition: `
+apiVersion: "1.3"
+operatingSystem:
+  rawConfiguration:
+    luksKey:
+      fromEnv: EIB_TEST_SECRET
+`,
+			ExpectedError: "secret references are only available in API version >= 1.4",
+		},
+		"Unsupported Field": {
+			Definition: `
+apiVersion: "1.4"
+image:
+  baseImage:
+    fromEnv: EIB_TEST_SECRET
+`,
+			ExpectedError: "cannot unmarshal !!map into string",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseDefinition([]byte(test.Definition), configDir)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, test.ExpectedError)
+			assert.NotContains(t, err.Error(), "top-secret")
+		})
+	}
+}
@@ -28,6 +28,7 @@ func validateElemental(ctx *image.Context) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Elemental config directory could not be read",
 			Error:       err,
+			RuleID:      "elemental/config-dir-unreadable",
 		})
 		return failures
 	}
@@ -49,6 +50,7 @@ func validateElementalDir(elementalConfigDir string) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Elemental config directory could not be read",
 			Error:       err,
+			RuleID:      "elemental/config-dir-unreadable",
 		})
 
 		return failures
@@ -58,16 +60,19 @@ func validateElementalDir(elementalConfigDir string) []FailedValidation {
 	case 0:
 		failures = append(failures, FailedValidation{
 			UserMessage: "Elemental config directory should not be present if it is empty",
+			RuleID:      "elemental/config-dir-empty",
 		})
 	case 1:
 		if elementalConfigDirEntries[0].Name() != elementalConfigFilename {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Elemental config file should only be named `%s`", elementalConfigFilename),
+				RuleID:      "elemental/config-file-name-invalid",
 			})
 		}
 	default:
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Elemental config directory should only contain a singular '%s' file", elementalConfigFilename),
+			RuleID:      "elemental/config-dir-unexpected-files",
 		})
 	}
 
@@ -82,6 +87,7 @@ func validateElementalConfiguration(ctx *image.Context) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "RPM directory could not be read",
 			Error:       err,
+			RuleID:      "elemental/rpm-dir-unreadable",
 		})
 	}
 
@@ -102,11 +108,14 @@ func validateElementalConfiguration(ctx *image.Context) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Operating system package registration code field must be defined when using Elemental "+
 					"or the %s RPMs must be manually side-loaded", combustion.ElementalPackages),
+				RuleID: "elemental/packages-unavailable",
+				Path:   "operatingSystem.packages.sccRegistrationCode",
 			})
 		}
 	} else if len(foundPackages) != len(combustion.ElementalPackages) {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Not all of the necessary Elemental packages are provided, packages found: %s, packages missing: %s", foundPackages, notFoundPackages),
+			RuleID:      "elemental/packages-incomplete",
 		})
 	}
 
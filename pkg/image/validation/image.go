@@ -8,8 +8,6 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/suse-edge/edge-image-builder/pkg/log"
-
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -35,23 +33,31 @@ func validateImage(ctx *image.Context) []FailedValidation {
 	if def.Image.OutputImageName == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'outputImageName' field is required in the 'image' section.",
+			RuleID:      "image/output-image-name-required",
+			Path:        "image.outputImageName",
 		})
 	}
 
 	if def.Image.ImageType == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'imageType' field is required in the 'image' section.",
+			RuleID:      "image/image-type-required",
+			Path:        "image.imageType",
 		})
 	} else if !slices.Contains(validImageTypes, def.Image.ImageType) {
 		msg := fmt.Sprintf("The 'imageType' field must be one of: %s", strings.Join(validImageTypes, ", "))
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "image/image-type-invalid",
+			Path:        "image.imageType",
 		})
 	}
 
 	if def.Image.BaseImage == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'baseImage' field is required in the 'image' section.",
+			RuleID:      "image/base-image-required",
+			Path:        "image.baseImage",
 		})
 	} else {
 		baseImageFilename := filepath.Join(ctx.ImageConfigDir, "base-images", def.Image.BaseImage)
@@ -61,12 +67,16 @@ func validateImage(ctx *image.Context) []FailedValidation {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be found.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					RuleID:      "image/base-image-not-found",
+					Path:        "image.baseImage",
 				})
 			} else {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be read. See the logs for more information.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
 					Error:       err,
+					RuleID:      "image/base-image-unreadable",
+					Path:        "image.baseImage",
 				})
 			}
 		}
@@ -82,6 +92,8 @@ func validateArch(def *image.Definition) []FailedValidation {
 	if def.Image.Arch == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'arch' field is required in the 'image' section.",
+			RuleID:      "image/arch-required",
+			Path:        "image.arch",
 		})
 
 		return failures
@@ -89,14 +101,22 @@ func validateArch(def *image.Definition) []FailedValidation {
 		msg := fmt.Sprintf("The 'arch' field must be one of: %s", strings.Join(validArchTypes, ", "))
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "image/arch-invalid",
+			Path:        "image.arch",
 		})
 
 		return failures
 	}
 
 	if runtime.GOARCH != def.Image.Arch.Short() {
-		log.Auditf("Image build may fail as host architecture does not match the defined architecture of the "+
-			"output image.\nDetected: %s, Defined: %s", runtime.GOARCH, def.Image.Arch)
+		msg := fmt.Sprintf("Image build may fail as host architecture does not match the defined architecture of the "+
+			"output image. Detected: %s, Defined: %s", runtime.GOARCH, def.Image.Arch)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			RuleID:      "image/arch-host-mismatch",
+			Path:        "image.arch",
+			Severity:    SeverityWarning,
+		})
 	}
 
 	return failures
@@ -109,6 +129,8 @@ func validateImageConfigDrive(def *image.Definition) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.outputImageName' field is not valid for generating config drives. The name of the output " +
 				"file should be defined through the '--output' argument.",
+			RuleID: "image/config-drive-field-invalid",
+			Path:   "image.outputImageName",
 		})
 	}
 
@@ -116,6 +138,8 @@ func validateImageConfigDrive(def *image.Definition) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.imageType' field is not valid for generating config drives. The output type " +
 				"should be defined through the '--output-type' argument.",
+			RuleID: "image/config-drive-field-invalid",
+			Path:   "image.imageType",
 		})
 	}
 
@@ -123,12 +147,16 @@ func validateImageConfigDrive(def *image.Definition) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.arch' field is not valid for generating config drives. The architecture of the generated " +
 				"config drive should be defined through the '--arch' argument.",
+			RuleID: "image/config-drive-field-invalid",
+			Path:   "image.arch",
 		})
 	}
 
 	if def.Image.BaseImage != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.baseImage' field is not valid for generating config drives.",
+			RuleID:      "image/config-drive-field-invalid",
+			Path:        "image.baseImage",
 		})
 	}
 
@@ -3,10 +3,12 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -25,6 +27,18 @@ const (
 
 var validNodeTypes = []string{image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent}
 
+var validVIPProviders = []string{image.VIPProviderMetalLB, image.VIPProviderKubeVIP, image.VIPProviderNone}
+
+var validCRDHandlings = []string{image.CRDHandlingSkip, image.CRDHandlingInclude, image.CRDHandlingSeparate}
+
+var (
+	// labelKeyRegex matches label keys with an optional DNS subdomain prefix, e.g. `node-role.kubernetes.io/worker`.
+	labelKeyRegex   = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	labelValueRegex = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+	taintRegex      = regexp.MustCompile(`^[^=:\s]+(=[^:\s]*)?:(NoSchedule|PreferNoSchedule|NoExecute)$`)
+	imagePathRegex  = regexp.MustCompile(image.ImagePathPattern)
+)
+
 func validateKubernetes(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
 
@@ -35,9 +49,11 @@ func validateKubernetes(ctx *image.Context) []FailedValidation {
 		return failures
 	}
 
+	failures = append(failures, validateKubernetesVersion(&def.Kubernetes)...)
 	failures = append(failures, validateNetworkingConfig(&def.Kubernetes, combustion.KubernetesConfigPath(ctx))...)
 	failures = append(failures, validateNetwork(&def.Kubernetes)...)
 	failures = append(failures, validateNodes(&def.Kubernetes)...)
+	failures = append(failures, validateNodeConfigs(&def.Kubernetes)...)
 	failures = append(failures, validateManifestURLs(&def.Kubernetes)...)
 	failures = append(failures, validateHelm(&def.Kubernetes, combustion.HelmValuesPath(ctx), combustion.HelmCertsPath(ctx))...)
 
@@ -48,6 +64,27 @@ func isKubernetesDefined(k8s *image.Kubernetes) bool {
 	return k8s.Version != ""
 }
 
+func validateKubernetesVersion(k8s *image.Kubernetes) []FailedValidation {
+	distro, channel, ok := kubernetes.ParseVersionChannel(k8s.Version)
+	if !ok {
+		return nil
+	}
+
+	if (distro != image.KubernetesDistroRKE2 && distro != image.KubernetesDistroK3S) || channel == "" {
+		msg := fmt.Sprintf("The Kubernetes release channel '%s' must be in the '<distribution>:<channel>' format, "+
+			"where the distribution is one of: %s, %s", k8s.Version, image.KubernetesDistroRKE2, image.KubernetesDistroK3S)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				RuleID:      "kubernetes/version-channel-invalid",
+				Path:        "kubernetes.version",
+			},
+		}
+	}
+
+	return nil
+}
+
 func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	var failures []FailedValidation
 
@@ -61,10 +98,12 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	var nodeNames []string
 	var initialisers []*image.Node
 
-	for _, node := range k8s.Nodes {
+	for i, node := range k8s.Nodes {
 		if node.Hostname == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'hostname' field is required for entries in the 'nodes' section.",
+				RuleID:      "kubernetes/node-hostname-required",
+				Path:        fmt.Sprintf("kubernetes.nodes[%d].hostname", i),
 			})
 		}
 
@@ -73,6 +112,8 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", options)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "kubernetes/node-type-invalid",
+				Path:        fmt.Sprintf("kubernetes.nodes[%d].type", i),
 			})
 		}
 
@@ -84,6 +125,8 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 				msg := fmt.Sprintf("The node labeled with 'initialiser' must be of type '%s'.", image.KubernetesNodeTypeServer)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					RuleID:      "kubernetes/node-initializer-type-invalid",
+					Path:        fmt.Sprintf("kubernetes.nodes[%d].type", i),
 				})
 			}
 		}
@@ -97,6 +140,8 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 		msg := fmt.Sprintf("The 'nodes' section contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "kubernetes/node-hostname-duplicate",
+			Path:        "kubernetes.nodes",
 		})
 	}
 
@@ -104,25 +149,100 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 		msg := fmt.Sprintf("There must be at least one node of type '%s' defined.", image.KubernetesNodeTypeServer)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "kubernetes/node-server-required",
+			Path:        "kubernetes.nodes",
 		})
 	}
 
 	if len(initialisers) > 1 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Only one node may be specified as the cluster initializer.",
+			RuleID:      "kubernetes/node-initializer-duplicate",
+			Path:        "kubernetes.nodes",
 		})
 	}
 
+	if kubernetes.ServersCount(k8s.Nodes) == 2 {
+		failures = append(failures, FailedValidation{
+			UserMessage: "Kubernetes clusters consisting of two server nodes cannot form a highly available architecture.",
+			RuleID:      "kubernetes/node-servers-not-highly-available",
+			Path:        "kubernetes.nodes",
+			Severity:    SeverityWarning,
+		})
+	}
+
+	return failures
+}
+
+func validateNodeConfigs(k8s *image.Kubernetes) []FailedValidation {
+	var failures []FailedValidation
+
+	for i, node := range k8s.Nodes {
+		for _, key := range slices.Sorted(maps.Keys(node.Labels)) {
+			if !labelKeyRegex.MatchString(key) || !labelValueRegex.MatchString(node.Labels[key]) {
+				msg := fmt.Sprintf("The label '%s=%s' of node '%s' is not a valid Kubernetes label.", key, node.Labels[key], node.Hostname)
+				failures = append(failures, FailedValidation{
+					UserMessage: msg,
+					RuleID:      "kubernetes/node-label-invalid",
+					Path:        fmt.Sprintf("kubernetes.nodes[%d].labels", i),
+				})
+			}
+		}
+
+		for j, taint := range node.Taints {
+			if !taintRegex.MatchString(taint) {
+				msg := fmt.Sprintf("The taint '%s' of node '%s' must be in the 'key[=value]:effect' format, "+
+					"where the effect is one of: NoSchedule, PreferNoSchedule, NoExecute", taint, node.Hostname)
+				failures = append(failures, FailedValidation{
+					UserMessage: msg,
+					RuleID:      "kubernetes/node-taint-invalid",
+					Path:        fmt.Sprintf("kubernetes.nodes[%d].taints[%d]", i, j),
+				})
+			}
+		}
+
+		for _, key := range kubernetes.ReservedNodeConfigKeys {
+			if _, ok := node.Config[key]; ok {
+				msg := fmt.Sprintf("The '%s' option is shared by all nodes of the cluster and cannot be configured for node '%s'.", key, node.Hostname)
+				failures = append(failures, FailedValidation{
+					UserMessage: msg,
+					RuleID:      "kubernetes/node-config-reserved-key",
+					Path:        fmt.Sprintf("kubernetes.nodes[%d].config.%s", i, key),
+				})
+			}
+		}
+	}
+
 	return failures
 }
 
 func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 	var failures []FailedValidation
 
+	if k8s.Network.VIPProvider != "" && !slices.Contains(validVIPProviders, k8s.Network.VIPProvider) {
+		options := strings.Join(validVIPProviders, ", ")
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("The 'vipProvider' field in the 'network' section must be one of: %s", options),
+			RuleID:      "kubernetes/network-vip-provider-invalid",
+			Path:        "kubernetes.network.vipProvider",
+		})
+	}
+
 	if k8s.Network.APIVIP4 == "" && k8s.Network.APIVIP6 == "" {
 		if len(k8s.Nodes) > 1 {
 			failures = append(failures, FailedValidation{
 				UserMessage: "At least one of the (`apiVIP`, `apiVIP6`) fields is required in the 'network' section for multi node clusters.",
+				RuleID:      "kubernetes/network-api-vip-required",
+				Path:        "kubernetes.network",
+			})
+		}
+
+		if k8s.Network.VIPProvider != "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'vipProvider' field in the 'network' section has no effect without the (`apiVIP`, `apiVIP6`) fields.",
+				RuleID:      "kubernetes/network-vip-provider-unused",
+				Path:        "kubernetes.network.vipProvider",
+				Severity:    SeverityWarning,
 			})
 		}
 
@@ -135,6 +255,8 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Invalid address value %q for field 'apiVIP'.", k8s.Network.APIVIP4),
 				Error:       err,
+				RuleID:      "kubernetes/network-api-vip-invalid",
+				Path:        "kubernetes.network.apiVIP",
 			})
 
 			return failures
@@ -143,6 +265,8 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 		if !ip4.Is4() {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Only IPv4 addresses are valid for field 'apiVIP'.",
+				RuleID:      "kubernetes/network-api-vip-invalid",
+				Path:        "kubernetes.network.apiVIP",
 			})
 		}
 
@@ -150,6 +274,8 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("Non-unicast cluster API address (%s) for field 'apiVIP' is invalid.", k8s.Network.APIVIP4)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "kubernetes/network-api-vip-invalid",
+				Path:        "kubernetes.network.apiVIP",
 			})
 		}
 	}
@@ -160,6 +286,8 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Invalid address value %q for field 'apiVIP6'.", k8s.Network.APIVIP6),
 				Error:       err,
+				RuleID:      "kubernetes/network-api-vip6-invalid",
+				Path:        "kubernetes.network.apiVIP6",
 			})
 
 			return failures
@@ -168,6 +296,8 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 		if !ip6.Is6() {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Only IPv6 addresses are valid for field 'apiVIP6'.",
+				RuleID:      "kubernetes/network-api-vip6-invalid",
+				Path:        "kubernetes.network.apiVIP6",
 			})
 		}
 
@@ -175,6 +305,8 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("Non-unicast cluster API address (%s) for field 'apiVIP6' is invalid.", k8s.Network.APIVIP6)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "kubernetes/network-api-vip6-invalid",
+				Path:        "kubernetes.network.apiVIP6",
 			})
 		}
 	}
@@ -191,10 +323,13 @@ func validateNetworkingConfig(k8s *image.Kubernetes, kubernetesConfigPath string
 			failures = append(failures, FailedValidation{
 				UserMessage: "Kubernetes server config could not be read",
 				Error:       err,
+				RuleID:      "kubernetes/server-config-unreadable",
 			})
 		} else if isDualStackConfigured(k8s) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Kubernetes server config could not be found at '%s'; dual-stack configuration requires a valid cluster-cidr and service-cidr.", kubernetesConfigPath),
+				RuleID:      "kubernetes/server-config-required",
+				Path:        "kubernetes.network",
 			})
 		}
 
@@ -206,6 +341,7 @@ func validateNetworkingConfig(k8s *image.Kubernetes, kubernetesConfigPath string
 		failures = append(failures, FailedValidation{
 			UserMessage: "Parsing kubernetes server config file failed",
 			Error:       err,
+			RuleID:      "kubernetes/server-config-invalid",
 		})
 
 		return failures
@@ -234,6 +370,7 @@ func validateCIDRConfig(k8s *image.Kubernetes, serverConfig map[string]any) []Fa
 			(!*clusterCIDRIPv6Priority && *serviceCIDRIPv6Priority) {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Kubernetes server config cluster-cidr cannot prioritize one address family while service-cidr prioritizes another; both must have the same priority",
+				RuleID:      "kubernetes/server-config-cidr-priority-mismatch",
 			})
 		}
 	}
@@ -256,6 +393,7 @@ func validateCIDRs(k8s *image.Kubernetes, cidrs []string, configField string) (i
 	case isDualStackConfigured(k8s) && len(cidrs) != 2:
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Kubernetes server config must contain a valid %s when configuring dual-stack", configField),
+			RuleID:      "kubernetes/server-config-cidr-required",
 		})
 		return nil, failures
 	case len(cidrs) == 0:
@@ -266,6 +404,7 @@ func validateCIDRs(k8s *image.Kubernetes, cidrs []string, configField string) (i
 	default:
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Kubernetes server config %s cannot contain more than two addresses", configField),
+			RuleID:      "kubernetes/server-config-address-count",
 		})
 		return nil, failures
 	}
@@ -295,6 +434,7 @@ func validateCIDRs(k8s *image.Kubernetes, cidrs []string, configField string) (i
 		if (cidr1.Is4() && cidr2.Is4()) || (cidr1.Is6() && cidr2.Is6()) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Kubernetes server config %s cannot contain addresses of the same IP address family; one must be IPv4, and the other IPv6", configField),
+				RuleID:      "kubernetes/server-config-address-family",
 			})
 		}
 	}
@@ -316,6 +456,7 @@ func validateNodeIP(k8s *image.Kubernetes, serverConfig map[string]any) []Failed
 	if kubernetes.ServersCount(k8s.Nodes) > 1 {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Kubernetes server config %s can not be specified when there is more than one Kubernetes server node", configField),
+			RuleID:      "kubernetes/server-config-node-ip-unsupported",
 		})
 		return failures
 	}
@@ -340,11 +481,13 @@ func validateNodeIP(k8s *image.Kubernetes, serverConfig map[string]any) []Failed
 		if (ip1.Is4() && ip2.Is4()) || (ip1.Is6() && ip2.Is6()) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Kubernetes server config %s cannot contain addresses of the same IP address family; one must be IPv4, and the other IPv6", configField),
+				RuleID:      "kubernetes/server-config-address-family",
 			})
 		}
 	default:
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Kubernetes server config %s cannot contain more than two addresses", configField),
+			RuleID:      "kubernetes/server-config-address-count",
 		})
 	}
 
@@ -359,6 +502,7 @@ func validateIP(ip string, configField string, parseAddress func(ip string) (net
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Kubernetes server config %s value '%s' could not be parsed", configField, ip),
 			Error:       err,
+			RuleID:      "kubernetes/server-config-address-invalid",
 		})
 		return netip.Addr{}, failures
 	}
@@ -366,6 +510,7 @@ func validateIP(ip string, configField string, parseAddress func(ip string) (net
 	if !addr.IsGlobalUnicast() {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Kubernetes server config %s value '%s' must be a valid unicast address", configField, ip),
+			RuleID:      "kubernetes/server-config-address-invalid",
 		})
 	}
 
@@ -380,10 +525,12 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 	}
 
 	seenManifests := make(map[string]bool)
-	for _, manifest := range k8s.Manifests.URLs {
+	for i, manifest := range k8s.Manifests.URLs {
 		if !strings.HasPrefix(manifest, "http") {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Entries in 'urls' must begin with either 'http://' or 'https://'.",
+				RuleID:      "kubernetes/manifest-url-invalid",
+				Path:        fmt.Sprintf("kubernetes.manifests.urls[%d]", i),
 			})
 		}
 
@@ -391,6 +538,8 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("The 'urls' field contains duplicate entries: %s", manifest)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "kubernetes/manifest-url-duplicate",
+				Path:        fmt.Sprintf("kubernetes.manifests.urls[%d]", i),
 			})
 		}
 
@@ -410,6 +559,8 @@ func validateHelm(k8s *image.Kubernetes, valuesDir, certsDir string) []FailedVal
 	if len(k8s.Helm.Repositories) == 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm charts defined with no Helm repositories defined.",
+			RuleID:      "kubernetes/helm-repositories-required",
+			Path:        "kubernetes.helm.repositories",
 		})
 
 		return failures
@@ -424,14 +575,16 @@ func validateHelm(k8s *image.Kubernetes, valuesDir, certsDir string) []FailedVal
 
 	seenHelmRepos := make(map[string]bool)
 	for i := range k8s.Helm.Charts {
-		failures = append(failures, validateChart(&k8s.Helm.Charts[i], helmRepositoryNames, valuesDir)...)
+		chartFailures := validateChart(&k8s.Helm.Charts[i], helmRepositoryNames, valuesDir)
+		failures = append(failures, qualifyPaths(chartFailures, fmt.Sprintf("kubernetes.helm.charts[%d]", i))...)
 
 		seenHelmRepos[k8s.Helm.Charts[i].RepositoryName] = true
 	}
 
-	for _, repo := range k8s.Helm.Repositories {
+	for i, repo := range k8s.Helm.Repositories {
 		r := repo
-		failures = append(failures, validateRepo(&r, seenHelmRepos, certsDir)...)
+		repoFailures := validateRepo(&r, seenHelmRepos, certsDir)
+		failures = append(failures, qualifyPaths(repoFailures, fmt.Sprintf("kubernetes.helm.repositories[%d]", i))...)
 	}
 
 	return failures
@@ -443,36 +596,80 @@ func validateChart(chart *image.HelmChart, repositoryNames []string, valuesDir s
 	if chart.Name == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm chart 'name' field must be defined.",
+			RuleID:      "kubernetes/helm-chart-name-required",
+			Path:        "name",
 		})
 	}
 
 	if chart.RepositoryName == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' field for %q must be defined.", chart.Name),
+			RuleID:      "kubernetes/helm-chart-repository-required",
+			Path:        "repositoryName",
 		})
 	} else if !slices.Contains(repositoryNames, chart.RepositoryName) {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' %q for Helm chart %q does not match the name of any defined repository.", chart.RepositoryName, chart.Name),
+			RuleID:      "kubernetes/helm-chart-repository-unknown",
+			Path:        "repositoryName",
 		})
 	}
 
 	if chart.Version == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'version' field for %q field must be defined.", chart.Name),
+			RuleID:      "kubernetes/helm-chart-version-required",
+			Path:        "version",
 		})
 	}
 
 	if chart.CreateNamespace && chart.TargetNamespace == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'createNamespace' field for %q cannot be true without 'targetNamespace' being defined.", chart.Name),
+			RuleID:      "kubernetes/helm-chart-target-namespace-required",
+			Path:        "createNamespace",
 		})
 	}
 
+	if chart.CRDHandling != "" && !slices.Contains(validCRDHandlings, chart.CRDHandling) {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("Helm chart 'crdHandling' field for %q must be one of: %s", chart.Name, strings.Join(validCRDHandlings, ", ")),
+			RuleID:      "kubernetes/helm-chart-crd-handling-invalid",
+			Path:        "crdHandling",
+		})
+	}
+
+	failures = append(failures, validateHelmChartImagePaths(chart)...)
 	failures = append(failures, validateHelmChartValues(chart.Name, chart.ValuesFile, valuesDir)...)
 
 	return failures
 }
 
+func validateHelmChartImagePaths(chart *image.HelmChart) []FailedValidation {
+	var failures []FailedValidation
+
+	for i, imagePath := range chart.ImagePaths {
+		if imagePath.Kind == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Helm chart 'imagePaths' entries for %q must define the 'kind' field.", chart.Name),
+				RuleID:      "kubernetes/helm-chart-image-path-kind-required",
+				Path:        fmt.Sprintf("imagePaths[%d].kind", i),
+			})
+		}
+
+		if !imagePathRegex.MatchString(imagePath.Path) {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Helm chart 'imagePaths' entry %q for %q is invalid; "+
+					"paths consist of dot separated fields, optionally suffixed with '[*]' to select every item of a list.", imagePath.Path, chart.Name),
+				RuleID: "kubernetes/helm-chart-image-path-invalid",
+				Path:   fmt.Sprintf("imagePaths[%d].path", i),
+			})
+		}
+	}
+
+	return failures
+}
+
 func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, certsDir string) []FailedValidation {
 	var failures []FailedValidation
 
@@ -481,6 +678,8 @@ func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, cer
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository URL '%s' could not be parsed.", repo.URL),
 			Error:       err,
+			RuleID:      "kubernetes/helm-repository-url-invalid",
+			Path:        "url",
 		})
 
 		return failures
@@ -501,10 +700,14 @@ func validateHelmRepoName(repo *image.HelmRepository, seenHelmRepos map[string]b
 	if repo.Name == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm repository 'name' field must be defined.",
+			RuleID:      "kubernetes/helm-repository-name-required",
+			Path:        "name",
 		})
 	} else if !seenHelmRepos[repo.Name] {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'name' field for %q must match the 'repositoryName' field in at least one defined Helm chart.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-unused",
+			Path:        "name",
 		})
 	}
 
@@ -517,10 +720,14 @@ func validateHelmRepoURL(parsedURL *url.URL, repo *image.HelmRepository) []Faile
 	if repo.URL == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must be defined.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-url-required",
+			Path:        "url",
 		})
 	} else if parsedURL.Scheme != httpScheme && parsedURL.Scheme != httpsScheme && parsedURL.Scheme != ociScheme {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must begin with either 'oci://', 'http://', or 'https://'.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-url-invalid",
+			Path:        "url",
 		})
 	}
 
@@ -533,12 +740,16 @@ func validateHelmRepoAuth(repo *image.HelmRepository) []FailedValidation {
 	if repo.Authentication.Username != "" && repo.Authentication.Password == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'password' field not defined for %q.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-password-required",
+			Path:        "authentication.password",
 		})
 	}
 
 	if repo.Authentication.Username == "" && repo.Authentication.Password != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'username' field not defined for %q.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-username-required",
+			Path:        "authentication.username",
 		})
 	}
 
@@ -551,42 +762,56 @@ func validateHelmRepoArgs(parsedURL *url.URL, repo *image.HelmRepository) []Fail
 	if repo.SkipTLSVerify && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'plainHTTP' and 'skipTLSVerify' fields for %q cannot both be true.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-tls-conflict",
+			Path:        "skipTLSVerify",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && !repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'plainHTTP' field is false.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-tls-conflict",
+			Path:        "plainHTTP",
 		})
 	}
 
 	if parsedURL.Scheme == httpsScheme && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'https://' but 'plainHTTP' field is true.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-tls-conflict",
+			Path:        "plainHTTP",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.SkipTLSVerify {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'skipTLSVerify' field is true.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-tls-conflict",
+			Path:        "skipTLSVerify",
 		})
 	}
 
 	if repo.SkipTLSVerify && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'skipTLSVerify' is true.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-tls-conflict",
+			Path:        "caFile",
 		})
 	}
 
 	if repo.PlainHTTP && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'plainHTTP' is true.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-tls-conflict",
+			Path:        "caFile",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'caFile' field is defined.", repo.Name),
+			RuleID:      "kubernetes/helm-repository-tls-conflict",
+			Path:        "caFile",
 		})
 	}
 
@@ -605,6 +830,8 @@ func validateHelmRepoCert(repoName, certFile, certsDir string) []FailedValidatio
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'caFile' field for %q must be the name of a valid cert file/bundle with one of the following extensions: %s",
 				repoName, strings.Join(validExtensions, ", ")),
+			RuleID: "kubernetes/helm-repository-ca-file-invalid",
+			Path:   "caFile",
 		})
 		return failures
 	}
@@ -615,11 +842,15 @@ func validateHelmRepoCert(repoName, certFile, certsDir string) []FailedValidatio
 		if errors.Is(err, os.ErrNotExist) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm repo cert file/bundle '%s' could not be found at '%s'.", certFile, certFilePath),
+				RuleID:      "kubernetes/helm-repository-ca-file-not-found",
+				Path:        "caFile",
 			})
 		} else {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm repo cert file/bundle '%s' could not be read", certFile),
 				Error:       err,
+				RuleID:      "kubernetes/helm-repository-ca-file-unreadable",
+				Path:        "caFile",
 			})
 		}
 	}
@@ -637,6 +868,8 @@ func validateHelmChartValues(chartName, valuesFile, valuesDir string) []FailedVa
 	if filepath.Ext(valuesFile) != ".yaml" && filepath.Ext(valuesFile) != ".yml" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'valuesFile' field for %q must be the name of a valid yaml file ending in '.yaml' or '.yml'.", chartName),
+			RuleID:      "kubernetes/helm-chart-values-file-invalid",
+			Path:        "valuesFile",
 		})
 		return failures
 	}
@@ -647,11 +880,15 @@ func validateHelmChartValues(chartName, valuesFile, valuesDir string) []FailedVa
 		if errors.Is(err, os.ErrNotExist) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm chart values file '%s' could not be found at '%s'.", valuesFile, valuesFilePath),
+				RuleID:      "kubernetes/helm-chart-values-file-not-found",
+				Path:        "valuesFile",
 			})
 		} else {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm chart values file '%s' could not be read.", valuesFile),
 				Error:       err,
+				RuleID:      "kubernetes/helm-chart-values-file-unreadable",
+				Path:        "valuesFile",
 			})
 		}
 	}
@@ -675,6 +912,8 @@ func validateHelmChartDuplicates(charts []image.HelmChart) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm charts with the same 'name' require a unique 'releaseName'. "+
 					"Duplicate found:\n"+"Name: '%s', Release name: '%s'", chart.Name, chart.ReleaseName),
+				RuleID: "kubernetes/helm-chart-release-name-duplicate",
+				Path:   fmt.Sprintf("kubernetes.helm.charts[%d].releaseName", i),
 			})
 		}
 
@@ -692,23 +931,30 @@ func validateAdditionalArtifacts(ctx *image.Context) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Kubernetes manifests directory could not be read",
 			Error:       err,
+			RuleID:      "kubernetes/manifests-dir-unreadable",
 		})
 	}
 
 	if len(dirEntries) != 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Kubernetes version must be defined when local manifests are configured",
+			RuleID:      "kubernetes/version-required",
+			Path:        "kubernetes.version",
 		})
 	}
 
 	if len(ctx.ImageDefinition.Kubernetes.Helm.Charts) != 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Kubernetes version must be defined when Helm charts are specified",
+			RuleID:      "kubernetes/version-required",
+			Path:        "kubernetes.version",
 		})
 	}
 	if len(ctx.ImageDefinition.Kubernetes.Manifests.URLs) != 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Kubernetes version must be defined when manifest URLs are specified",
+			RuleID:      "kubernetes/version-required",
+			Path:        "kubernetes.version",
 		})
 	}
 
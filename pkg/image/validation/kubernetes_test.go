@@ -168,6 +168,47 @@ func TestIsKubernetesDefined(t *testing.T) {
 	assert.False(t, result)
 }
 
+func TestValidateKubernetesVersion(t *testing.T) {
+	tests := map[string]struct {
+		version         string
+		expectedFailure bool
+	}{
+		"Version": {
+			version: "v1.30.3+rke2r1",
+		},
+		"RKE2 channel": {
+			version: "rke2:stable",
+		},
+		"K3s channel": {
+			version: "k3s:v1.30",
+		},
+		"Unknown distribution": {
+			version:         "rancher:stable",
+			expectedFailure: true,
+		},
+		"Missing channel": {
+			version:         "rke2:",
+			expectedFailure: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateKubernetesVersion(&image.Kubernetes{Version: test.version})
+
+			if !test.expectedFailure {
+				assert.Empty(t, failures)
+				return
+			}
+
+			require.Len(t, failures, 1)
+			assert.Equal(t, fmt.Sprintf("The Kubernetes release channel '%s' must be in the '<distribution>:<channel>' format, "+
+				"where the distribution is one of: rke2, k3s", test.version), failures[0].UserMessage)
+			assert.Equal(t, "kubernetes/version-channel-invalid", failures[0].RuleID)
+		})
+	}
+}
+
 func TestValidateNodes(t *testing.T) {
 	tests := map[string]struct {
 		K8s                    image.Kubernetes
@@ -203,7 +244,7 @@ func TestValidateNodes(t *testing.T) {
 						Type:     image.KubernetesNodeTypeServer,
 					},
 					{
-						Type: image.KubernetesNodeTypeServer,
+						Type: image.KubernetesNodeTypeAgent,
 					},
 				},
 			},
@@ -328,6 +369,29 @@ func TestValidateNodes(t *testing.T) {
 			},
 			ExpectedFailedMessages: []string{
 				"Only one node may be specified as the cluster initializer.",
+				"Kubernetes clusters consisting of two server nodes cannot form a highly available architecture.",
+			},
+		},
+		`two server nodes`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "foo",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "bar",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "baz",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Kubernetes clusters consisting of two server nodes cannot form a highly available architecture.",
 			},
 		},
 	}
@@ -351,6 +415,106 @@ func TestValidateNodes(t *testing.T) {
 	}
 }
 
+func TestValidateNodeConfigs(t *testing.T) {
+	tests := map[string]struct {
+		K8s                    image.Kubernetes
+		ExpectedFailedMessages []string
+	}{
+		`valid`: {
+			K8s: image.Kubernetes{
+				Nodes: []image.Node{
+					{
+						Hostname: "host1",
+						Type:     image.KubernetesNodeTypeServer,
+						Labels: map[string]string{
+							"node-role.kubernetes.io/ingress": "",
+							"accelerator":                     "nvidia",
+						},
+						Taints: []string{"CriticalAddonsOnly=true:NoExecute", "dedicated:NoSchedule"},
+						Config: map[string]any{
+							"node-ip": "192.168.122.10",
+						},
+					},
+				},
+			},
+		},
+		`invalid labels`: {
+			K8s: image.Kubernetes{
+				Nodes: []image.Node{
+					{
+						Hostname: "host1",
+						Type:     image.KubernetesNodeTypeServer,
+						Labels: map[string]string{
+							"-invalid": "value",
+							"zone":     "a b",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The label '-invalid=value' of node 'host1' is not a valid Kubernetes label.",
+				"The label 'zone=a b' of node 'host1' is not a valid Kubernetes label.",
+			},
+		},
+		`invalid taints`: {
+			K8s: image.Kubernetes{
+				Nodes: []image.Node{
+					{
+						Hostname: "host1",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "host2",
+						Type:     image.KubernetesNodeTypeAgent,
+						Taints:   []string{"dedicated=gpu", "dedicated=gpu:Never"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The taint 'dedicated=gpu' of node 'host2' must be in the 'key[=value]:effect' format, " +
+					"where the effect is one of: NoSchedule, PreferNoSchedule, NoExecute",
+				"The taint 'dedicated=gpu:Never' of node 'host2' must be in the 'key[=value]:effect' format, " +
+					"where the effect is one of: NoSchedule, PreferNoSchedule, NoExecute",
+			},
+		},
+		`reserved config keys`: {
+			K8s: image.Kubernetes{
+				Nodes: []image.Node{
+					{
+						Hostname: "host1",
+						Type:     image.KubernetesNodeTypeServer,
+						Config: map[string]any{
+							"token":        "foobar",
+							"cluster-init": true,
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'token' option is shared by all nodes of the cluster and cannot be configured for node 'host1'.",
+				"The 'cluster-init' option is shared by all nodes of the cluster and cannot be configured for node 'host1'.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := test.K8s
+			failures := validateNodeConfigs(&k)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
 func TestValidateManifestURLs(t *testing.T) {
 	tests := map[string]struct {
 		K8s                    image.Kubernetes
@@ -609,6 +773,63 @@ func TestValidateHelmCharts(t *testing.T) {
 				"Helm chart 'createNamespace' field for \"apache\" cannot be true without 'targetNamespace' being defined.",
 			},
 		},
+		`helm chart valid CRD handling and image paths`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "operator",
+							RepositoryName: "operator-repo",
+							Version:        "1.0.0",
+							CRDHandling:    image.CRDHandlingSeparate,
+							ImagePaths: []image.ImagePath{
+								{Kind: "Cluster", Path: "spec.image"},
+								{Kind: "Cluster", Path: "spec.components[*].image"},
+							},
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "operator-repo",
+							URL:  "oci://registry.example.com/charts",
+						},
+					},
+				},
+			},
+		},
+		`helm chart invalid CRD handling and image paths`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "operator",
+							RepositoryName: "operator-repo",
+							Version:        "1.0.0",
+							CRDHandling:    "install",
+							ImagePaths: []image.ImagePath{
+								{Path: "spec.image"},
+								{Kind: "Cluster", Path: "spec..image"},
+								{Kind: "Cluster", Path: "spec.components[0].image"},
+							},
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "operator-repo",
+							URL:  "oci://registry.example.com/charts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart 'crdHandling' field for \"operator\" must be one of: skip, include, separate",
+				"Helm chart 'imagePaths' entries for \"operator\" must define the 'kind' field.",
+				"Helm chart 'imagePaths' entry \"spec..image\" for \"operator\" is invalid; " +
+					"paths consist of dot separated fields, optionally suffixed with '[*]' to select every item of a list.",
+				"Helm chart 'imagePaths' entry \"spec.components[0].image\" for \"operator\" is invalid; " +
+					"paths consist of dot separated fields, optionally suffixed with '[*]' to select every item of a list.",
+			},
+		},
 		`helm chart duplicate name no release name`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
@@ -1179,6 +1400,35 @@ func TestValidateNetwork(t *testing.T) {
 				},
 			},
 		},
+		`valid VIP provider`: {
+			K8s: image.Kubernetes{
+				Network: image.Network{
+					APIVIP4:     "192.168.1.1",
+					VIPProvider: image.VIPProviderKubeVIP,
+				},
+			},
+		},
+		`invalid VIP provider`: {
+			K8s: image.Kubernetes{
+				Network: image.Network{
+					APIVIP4:     "192.168.1.1",
+					VIPProvider: "keepalived",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'vipProvider' field in the 'network' section must be one of: metallb, kube-vip, none",
+			},
+		},
+		`VIP provider without VIP`: {
+			K8s: image.Kubernetes{
+				Network: image.Network{
+					VIPProvider: image.VIPProviderNone,
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'vipProvider' field in the 'network' section has no effect without the (`apiVIP`, `apiVIP6`) fields.",
+			},
+		},
 		`invalid IPv4`: {
 			K8s: image.Kubernetes{
 				Network: image.Network{
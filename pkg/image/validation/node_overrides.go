@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	nodeOverridesComponent = "Node Overrides"
+)
+
+func validateNodeOverrides(ctx *image.Context) []FailedValidation {
+	def := ctx.ImageDefinition
+
+	var failures []FailedValidation
+
+	var hostnames []string
+	for _, node := range def.Kubernetes.Nodes {
+		hostnames = append(hostnames, node.Hostname)
+	}
+
+	var overrideHostnames []string
+	for i, override := range def.NodeOverrides {
+		if override.Hostname == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'hostname' field is required for entries in the 'nodeOverrides' section.",
+				RuleID:      "node-overrides/hostname-required",
+				Path:        fmt.Sprintf("nodeOverrides[%d].hostname", i),
+			})
+			continue
+		}
+
+		overrideHostnames = append(overrideHostnames, override.Hostname)
+
+		if len(hostnames) > 1 && !slices.Contains(hostnames, override.Hostname) {
+			msg := fmt.Sprintf("Node override '%s' does not match any of the hostnames in the 'kubernetes.nodes' section.", override.Hostname)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				RuleID:      "node-overrides/hostname-unknown",
+				Path:        fmt.Sprintf("nodeOverrides[%d].hostname", i),
+			})
+		}
+
+		nodeFailures := validateNodeOperatingSystem(ctx, &override)
+		failures = append(failures, qualifyPaths(nodeFailures, fmt.Sprintf("nodeOverrides[%d]", i))...)
+	}
+
+	if duplicates := findDuplicates(overrideHostnames); len(duplicates) > 0 {
+		duplicateValues := strings.Join(duplicates, ", ")
+		msg := fmt.Sprintf("The 'nodeOverrides' section contains duplicate entries: %s", duplicateValues)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			RuleID:      "node-overrides/hostname-duplicate",
+			Path:        "nodeOverrides",
+		})
+	}
+
+	return failures
+}
+
+func validateNodeOperatingSystem(ctx *image.Context, override *image.NodeOverride) []FailedValidation {
+	// Only validate the values specified in the override itself, as the shared
+	// operating system configuration is already validated on its own
+	os := &image.OperatingSystem{
+		KernelArgs: override.OperatingSystem.KernelArgs,
+		Groups:     override.OperatingSystem.Groups,
+		Users:      override.OperatingSystem.Users,
+		Systemd:    override.OperatingSystem.Systemd,
+	}
+
+	var failures []FailedValidation
+
+	failures = append(failures, validateSystemd(os)...)
+	failures = append(failures, validateGroups(os)...)
+	failures = append(failures, validateUsers(os)...)
+
+	if !ctx.IsConfigDrive {
+		failures = append(failures, validateKernelArgs(os)...)
+	} else if len(os.KernelArgs) != 0 {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'nodeOverrides.operatingSystem.kernelArgs' field is not valid for generating config drives.",
+			RuleID:      "node-overrides/config-drive-field-invalid",
+			Path:        "operatingSystem.kernelArgs",
+		})
+	}
+
+	for i := range failures {
+		failures[i].UserMessage = fmt.Sprintf("Node '%s': %s", override.Hostname, failures[i].UserMessage)
+	}
+
+	return failures
+}
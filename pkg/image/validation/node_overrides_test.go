@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateNodeOverrides(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+		IsConfigDrive          bool
+	}{
+		`no overrides defined`: {
+			Definition: image.Definition{},
+		},
+		`all valid`: {
+			Definition: image.Definition{
+				Kubernetes: image.Kubernetes{
+					Nodes: []image.Node{
+						{Hostname: "node1.suse.com", Type: image.KubernetesNodeTypeServer},
+						{Hostname: "node2.suse.com", Type: image.KubernetesNodeTypeAgent},
+					},
+				},
+				NodeOverrides: []image.NodeOverride{
+					{
+						Hostname: "node1.suse.com",
+						OperatingSystem: image.OperatingSystemNodeOverride{
+							KernelArgs: []string{"foo=bar"},
+							Groups:     []image.OperatingSystemGroup{{Name: "gpu"}},
+							Users: []image.OperatingSystemUser{
+								{
+									Username:          "alpha",
+									EncryptedPassword: "alpha123",
+								},
+							},
+							Systemd: image.Systemd{
+								Enable: []string{"nvidia-persistenced"},
+							},
+						},
+					},
+					{
+						Hostname: "node2.suse.com",
+					},
+				},
+			},
+		},
+		`all invalid`: {
+			Definition: image.Definition{
+				Kubernetes: image.Kubernetes{
+					Nodes: []image.Node{
+						{Hostname: "node1.suse.com", Type: image.KubernetesNodeTypeServer},
+						{Hostname: "node2.suse.com", Type: image.KubernetesNodeTypeAgent},
+					},
+				},
+				NodeOverrides: []image.NodeOverride{
+					{
+						Hostname: "node1.suse.com",
+						OperatingSystem: image.OperatingSystemNodeOverride{
+							KernelArgs: []string{"foo="},
+							Users: []image.OperatingSystemUser{
+								{
+									Username: "alpha",
+								},
+							},
+							Systemd: image.Systemd{
+								Enable:  []string{"foo"},
+								Disable: []string{"foo"},
+							},
+						},
+					},
+					{
+						Hostname: "node1.suse.com",
+					},
+					{
+						Hostname: "node3.suse.com",
+					},
+					{},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field is required for entries in the 'nodeOverrides' section.",
+				"Node override 'node3.suse.com' does not match any of the hostnames in the 'kubernetes.nodes' section.",
+				"The 'nodeOverrides' section contains duplicate entries: node1.suse.com",
+				"Node 'node1.suse.com': Kernel arguments must be specified as 'key=value'.",
+				"Node 'node1.suse.com': User 'alpha' must have either a password or at least one SSH key.",
+				"Node 'node1.suse.com': Systemd conflict found, 'foo' is both enabled and disabled.",
+			},
+		},
+		`config drive kernel args`: {
+			Definition: image.Definition{
+				NodeOverrides: []image.NodeOverride{
+					{
+						Hostname: "node1.suse.com",
+						OperatingSystem: image.OperatingSystemNodeOverride{
+							KernelArgs: []string{"foo=bar"},
+						},
+					},
+				},
+			},
+			IsConfigDrive: true,
+			ExpectedFailedMessages: []string{
+				"Node 'node1.suse.com': The 'nodeOverrides.operatingSystem.kernelArgs' field is not valid for generating config drives.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			ctx := image.Context{
+				ImageDefinition: &def,
+				IsConfigDrive:   test.IsConfigDrive,
+			}
+			failures := validateNodeOverrides(&ctx)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
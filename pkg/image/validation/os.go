@@ -41,7 +41,7 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
 	seenKeys := make(map[string]bool)
-	for _, arg := range os.KernelArgs {
+	for i, arg := range os.KernelArgs {
 		key := arg
 
 		parts := strings.SplitN(arg, "=", 2)
@@ -51,12 +51,16 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 			if key == "" || value == "" {
 				failures = append(failures, FailedValidation{
 					UserMessage: "Kernel arguments must be specified as 'key=value'.",
+					RuleID:      "os/kernel-arg-invalid",
+					Path:        fmt.Sprintf("operatingSystem.kernelArgs[%d]", i),
 				})
 			}
 
 			if (key == "fips" && value == "1") && !os.EnableFIPS {
 				failures = append(failures, FailedValidation{
 					UserMessage: "FIPS mode has been specified via kernel arguments, please use the 'enableFIPS: true' option instead.",
+					RuleID:      "os/kernel-arg-fips",
+					Path:        fmt.Sprintf("operatingSystem.kernelArgs[%d]", i),
 				})
 			}
 		}
@@ -64,6 +68,8 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 		if _, exists := seenKeys[key]; exists {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Duplicate kernel argument found: %s", key),
+				RuleID:      "os/kernel-arg-duplicate",
+				Path:        fmt.Sprintf("operatingSystem.kernelArgs[%d]", i),
 			})
 		}
 		seenKeys[key] = true
@@ -80,6 +86,8 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("Systemd enable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/systemd-enable-duplicate",
+			Path:        "operatingSystem.systemd.enable",
 		})
 	}
 
@@ -88,15 +96,19 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("Systemd disable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/systemd-disable-duplicate",
+			Path:        "operatingSystem.systemd.disable",
 		})
 	}
 
 	for _, enableItem := range os.Systemd.Enable {
-		for _, disableItem := range os.Systemd.Disable {
+		for i, disableItem := range os.Systemd.Disable {
 			if enableItem == disableItem {
 				msg := fmt.Sprintf("Systemd conflict found, '%s' is both enabled and disabled.", enableItem)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					RuleID:      "os/systemd-conflict",
+					Path:        fmt.Sprintf("operatingSystem.systemd.disable[%d]", i),
 				})
 			}
 		}
@@ -111,10 +123,12 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 	// The script is idempotent and will not fail on creating a duplicate group,
 	// but for consistency validate that duplicates aren't in the definition.
 	seenGroupNames := make(map[string]bool)
-	for _, group := range os.Groups {
+	for i, group := range os.Groups {
 		if group.Name == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'name' field is required for all entries under 'groups'.",
+				RuleID:      "os/group-name-required",
+				Path:        fmt.Sprintf("operatingSystem.groups[%d].name", i),
 			})
 		}
 
@@ -122,6 +136,8 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("Duplicate group name found: %s", group.Name)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "os/group-duplicate",
+				Path:        fmt.Sprintf("operatingSystem.groups[%d].name", i),
 			})
 		}
 		seenGroupNames[group.Name] = true
@@ -134,10 +150,12 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
 	seenUsernames := make(map[string]bool)
-	for _, user := range os.Users {
+	for i, user := range os.Users {
 		if user.Username == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'username' field is required for all entries under 'users'.",
+				RuleID:      "os/user-username-required",
+				Path:        fmt.Sprintf("operatingSystem.users[%d].username", i),
 			})
 		}
 
@@ -145,12 +163,16 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("User '%s' must have either a password or at least one SSH key.", user.Username)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "os/user-credentials-required",
+				Path:        fmt.Sprintf("operatingSystem.users[%d]", i),
 			})
 		}
 
 		if !user.CreateHomeDir && len(user.SSHKeys) > 0 {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'createHomeDir' attribute must be set to 'true' if at least one SSH key is specified.",
+				RuleID:      "os/user-home-dir-required",
+				Path:        fmt.Sprintf("operatingSystem.users[%d].createHomeDir", i),
 			})
 		}
 
@@ -158,6 +180,8 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("Duplicate username found: %s", user.Username)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "os/user-duplicate",
+				Path:        fmt.Sprintf("operatingSystem.users[%d].username", i),
 			})
 		}
 		seenUsernames[user.Username] = true
@@ -175,16 +199,22 @@ func validateSuma(os *image.OperatingSystem) []FailedValidation {
 	if os.Suma.Host == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'host' field is required for the 'suma' section.",
+			RuleID:      "os/suma-host-required",
+			Path:        "operatingSystem.suma.host",
 		})
 	}
 	if strings.HasPrefix(os.Suma.Host, "http") {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The suma 'host' field may not contain 'http://' or 'https://'",
+			RuleID:      "os/suma-host-invalid",
+			Path:        "operatingSystem.suma.host",
 		})
 	}
 	if os.Suma.ActivationKey == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'activationKey' field is required for the 'suma' section.",
+			RuleID:      "os/suma-activation-key-required",
+			Path:        "operatingSystem.suma.activationKey",
 		})
 	}
 
@@ -197,6 +227,8 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 	if slices.Contains(os.Packages.PKGList, "") {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'packageList' field cannot contain empty values.",
+			RuleID:      "os/package-empty",
+			Path:        "operatingSystem.packages.packageList",
 		})
 	}
 
@@ -205,6 +237,27 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("The 'packageList' field contains duplicate packages: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/package-duplicate",
+			Path:        "operatingSystem.packages.packageList",
+		})
+	}
+
+	if os.Packages.NoGPGCheck {
+		failures = append(failures, FailedValidation{
+			UserMessage: "Running EIB with disabled GPG validation is intended for development purposes only.",
+			RuleID:      "os/gpg-check-disabled",
+			Path:        "operatingSystem.packages.noGPGCheck",
+			Severity:    SeverityWarning,
+		})
+	}
+
+	if len(os.Packages.PKGList) > 0 && os.Packages.RegCode == "" && len(os.Packages.AdditionalRepos) == 0 {
+		msg := "No SUSE registration code or additional repositories provided, package resolution may fail if you're using SLE Micro as the base image."
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			RuleID:      "os/package-repositories-missing",
+			Path:        "operatingSystem.packages.packageList",
+			Severity:    SeverityWarning,
 		})
 	}
 
@@ -213,11 +266,13 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 	if len(os.Packages.AdditionalRepos) > 0 {
 		var repoURLs []string
 
-		for _, repo := range os.Packages.AdditionalRepos {
+		for i, repo := range os.Packages.AdditionalRepos {
 			if repo.URL == "" {
 				msg := "The 'url' field is required for all entries under 'additionalRepos'."
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					RuleID:      "os/repo-url-required",
+					Path:        fmt.Sprintf("operatingSystem.packages.additionalRepos[%d].url", i),
 				})
 			}
 
@@ -225,6 +280,8 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 				msg := "The 'priority' field for 'additionalRepos' must be a value between 0 and 99."
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					RuleID:      "os/repo-priority-invalid",
+					Path:        fmt.Sprintf("operatingSystem.packages.additionalRepos[%d].priority", i),
 				})
 			}
 
@@ -236,6 +293,8 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("The 'additionalRepos' field contains duplicate repos: %s", duplicateValues)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "os/repo-duplicate",
+				Path:        "operatingSystem.packages.additionalRepos",
 			})
 		}
 	}
@@ -250,6 +309,8 @@ func validateIsoConfig(def *image.Definition) []FailedValidation {
 		msg := fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/install-device-unsupported",
+			Path:        "operatingSystem.isoConfiguration.installDevice",
 		})
 	}
 
@@ -264,6 +325,8 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 			msg := fmt.Sprintf("The 'luksKey' field should only be defined for '%s' encrypted images.", image.TypeRAW)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "os/luks-key-unsupported",
+				Path:        "operatingSystem.rawConfiguration.luksKey",
 			})
 		}
 
@@ -271,6 +334,8 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 			msg := fmt.Sprintf("The 'expandEncryptedPartition' field can only be defined for '%s' encrypted images.", image.TypeRAW)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "os/expand-encrypted-partition-unsupported",
+				Path:        "operatingSystem.rawConfiguration.expandEncryptedPartition",
 			})
 		}
 
@@ -278,6 +343,8 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 			msg := fmt.Sprintf("The 'diskSize' field can only be defined for '%s' images.", image.TypeRAW)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "os/disk-size-unsupported",
+				Path:        "operatingSystem.rawConfiguration.diskSize",
 			})
 		}
 
@@ -288,6 +355,8 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 		msg := "The 'expandEncryptedPartition' field cannot be 'true' when 'luksKey' is not defined."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/expand-encrypted-partition-without-luks-key",
+			Path:        "operatingSystem.rawConfiguration.expandEncryptedPartition",
 		})
 	}
 
@@ -295,6 +364,8 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 		msg := "The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/disk-size-invalid",
+			Path:        "operatingSystem.rawConfiguration.diskSize",
 		})
 	}
 
@@ -312,6 +383,8 @@ func validateTimeSync(os *image.OperatingSystem) []FailedValidation {
 		msg := "If you're wanting to wait for NTP synchronization at boot, please ensure that you provide at least one NTP time source."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/ntp-sources-required",
+			Path:        "operatingSystem.time.ntp",
 		})
 	}
 
@@ -329,6 +402,16 @@ func validateFIPS(os *image.OperatingSystem) []FailedValidation {
 		msg := "To enable FIPS you must either provide an SCC registration code or link an additional repository that contains the `patterns-base-fips` package."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			RuleID:      "os/fips-packages-unavailable",
+			Path:        "operatingSystem.enableFIPS",
+		})
+	} else if os.Packages.RegCode == "" {
+		msg := "FIPS enabled with no SUSE registration code provided. Package resolution may fail if additional repositories do not contain the `patterns-base-fips` package."
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			RuleID:      "os/fips-registration-code-missing",
+			Path:        "operatingSystem.enableFIPS",
+			Severity:    SeverityWarning,
 		})
 	}
 
@@ -351,6 +434,8 @@ func validateOperatingSystemConfigDrive(def *image.Definition) []FailedValidatio
 		if isValueNonZero(rootValue, field.Chain) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("The '%s' field is not valid for generating config drives.", field.Key),
+				RuleID:      "os/config-drive-field-invalid",
+				Path:        field.Key,
 			})
 		}
 	}
@@ -587,11 +587,28 @@ func TestPackages(t *testing.T) {
 		`empty package`: {
 			Packages: image.Packages{
 				PKGList: []string{"foo", "bar", ""},
+				RegCode: "regcode",
 			},
 			ExpectedFailedMessages: []string{
 				"The 'packageList' field cannot contain empty values.",
 			},
 		},
+		`packages without repositories`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo"},
+			},
+			ExpectedFailedMessages: []string{
+				"No SUSE registration code or additional repositories provided, package resolution may fail if you're using SLE Micro as the base image.",
+			},
+		},
+		`disabled GPG check`: {
+			Packages: image.Packages{
+				NoGPGCheck: true,
+			},
+			ExpectedFailedMessages: []string{
+				"Running EIB with disabled GPG validation is intended for development purposes only.",
+			},
+		},
 		`duplicate packages`: {
 			Packages: image.Packages{
 				PKGList: []string{"foo", "bar", "foo", "bar", "baz"},
@@ -994,6 +1011,9 @@ func TestValidateFIPS(t *testing.T) {
 					},
 				},
 			},
+			ExpectedFailedMessages: []string{
+				"FIPS enabled with no SUSE registration code provided. Package resolution may fail if additional repositories do not contain the `patterns-base-fips` package.",
+			},
 		},
 	}
 
@@ -2,9 +2,11 @@ package validation
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/containers/image/v5/docker/reference"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
 )
 
 const (
@@ -16,6 +18,7 @@ func validateEmbeddedArtifactRegistry(ctx *image.Context) []FailedValidation {
 
 	failures = append(failures, validateRegistries(&ctx.ImageDefinition.EmbeddedArtifactRegistry)...)
 	failures = append(failures, validateContainerImages(&ctx.ImageDefinition.EmbeddedArtifactRegistry)...)
+	failures = append(failures, validateImageRules(&ctx.ImageDefinition.EmbeddedArtifactRegistry)...)
 
 	return failures
 }
@@ -24,10 +27,12 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 	var failures []FailedValidation
 
 	seenContainerImages := make(map[string]bool)
-	for _, cImage := range ear.ContainerImages {
+	for i, cImage := range ear.ContainerImages {
 		if cImage.Name == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'name' field is required for each entry in 'images'.",
+				RuleID:      "registry/image-name-required",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.images[%d].name", i),
 			})
 		}
 
@@ -35,9 +40,50 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 			msg := fmt.Sprintf("Duplicate image name '%s' found in the 'images' section.", cImage.Name)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "registry/image-duplicate",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.images[%d].name", i),
 			})
 		}
 		seenContainerImages[cImage.Name] = true
+
+		if strings.Contains(cImage.Name, "sha256:") {
+			msg := fmt.Sprintf("Container image '%s' is referenced by digest. Please be sure that the digest is a manifest "+
+				"digest (the digest of the container image) and NOT an index digest (the digest of the image platform). "+
+				"The embedded artifact registry will fail at boot time if an index/platform specific digest is provided.", cImage.Name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				RuleID:      "registry/image-digest",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.images[%d].name", i),
+				Severity:    SeverityWarning,
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateImageRules(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
+	var failures []FailedValidation
+
+	for i := range ear.ImageRules {
+		if ear.ImageRules[i].Selector == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'selector' field is required for each entry in 'imageRules'.",
+				RuleID:      "registry/image-rule-selector-required",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.imageRules[%d].selector", i),
+			})
+
+			continue
+		}
+
+		if err := registry.ValidateImageRule(&ear.ImageRules[i]); err != nil {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Image rule with selector '%s' is invalid.", ear.ImageRules[i].Selector),
+				Error:       err,
+				RuleID:      "registry/image-rule-invalid",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.imageRules[%d]", i),
+			})
+		}
 	}
 
 	return failures
@@ -56,10 +102,12 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 	var failures []FailedValidation
 
 	seenRegistryURLs := make(map[string]bool)
-	for _, registry := range ear.Registries {
+	for i, registry := range ear.Registries {
 		if registry.URI == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'uri' field is required for each entry in 'embeddedArtifactRegistry.registries'.",
+				RuleID:      "registry/uri-required",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.registries[%d].uri", i),
 			})
 		}
 
@@ -68,6 +116,8 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Embedded artifact registry URI '%s' could not be parsed.", registry.URI),
 				Error:       err,
+				RuleID:      "registry/uri-invalid",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.registries[%d].uri", i),
 			})
 
 			continue
@@ -77,6 +127,8 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 			msg := fmt.Sprintf("Duplicate registry URI '%s' found in the 'embeddedArtifactRegistry.registries' section.", registry.URI)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				RuleID:      "registry/uri-duplicate",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.registries[%d].uri", i),
 			})
 		}
 
@@ -89,16 +141,20 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 func validateCredentials(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 	var failures []FailedValidation
 
-	for _, registry := range ear.Registries {
+	for i, registry := range ear.Registries {
 		if registry.Authentication.Username == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'username' field is required for each entry in 'embeddedArtifactRegistry.registries.credentials'.",
+				RuleID:      "registry/username-required",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.registries[%d].authentication.username", i),
 			})
 		}
 
 		if registry.Authentication.Password == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'password' field is required for each entry in 'embeddedArtifactRegistry.registries.credentials'.",
+				RuleID:      "registry/password-required",
+				Path:        fmt.Sprintf("embeddedArtifactRegistry.registries[%d].authentication.password", i),
 			})
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -125,6 +126,21 @@ func TestValidateContainerImages(t *testing.T) {
 				"Duplicate image name 'bar' found in the 'images' section.",
 			},
 		},
+		`image with digest`: {
+			Registry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{
+					{
+						Name: "registry.suse.com/rancher/hardened-coredns@sha256:3f2b6e9d",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Container image 'registry.suse.com/rancher/hardened-coredns@sha256:3f2b6e9d' is referenced by digest. " +
+					"Please be sure that the digest is a manifest digest (the digest of the container image) and NOT an " +
+					"index digest (the digest of the image platform). The embedded artifact registry will fail at boot " +
+					"time if an index/platform specific digest is provided.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -145,6 +161,44 @@ func TestValidateContainerImages(t *testing.T) {
 	}
 }
 
+func TestValidateImageRules(t *testing.T) {
+	ear := image.EmbeddedArtifactRegistry{
+		ImageRules: []image.ImageRule{
+			{
+				Kind:     "Database",
+				Selector: "spec",
+				Template: "{{ .repository }}:{{ .version }}",
+			},
+			{
+				Selector: "..imageName",
+			},
+			{
+				Kind: "Database",
+			},
+			{
+				Selector: "spec.containers[",
+			},
+			{
+				Selector: "spec",
+				Template: "{{ .repository",
+			},
+		},
+	}
+
+	failures := validateImageRules(&ear)
+	require.Len(t, failures, 3)
+
+	assert.Equal(t, "The 'selector' field is required for each entry in 'imageRules'.", failures[0].UserMessage)
+	assert.Equal(t, "embeddedArtifactRegistry.imageRules[2].selector", failures[0].Path)
+
+	assert.Equal(t, "Image rule with selector 'spec.containers[' is invalid.", failures[1].UserMessage)
+	assert.EqualError(t, failures[1].Error, "parsing selector: unterminated '[' in 'spec.containers['")
+	assert.Equal(t, "embeddedArtifactRegistry.imageRules[3]", failures[1].Path)
+
+	assert.Equal(t, "Image rule with selector 'spec' is invalid.", failures[2].UserMessage)
+	assert.ErrorContains(t, failures[2].Error, "parsing template")
+}
+
 func TestValidateRegistries(t *testing.T) {
 	tests := map[string]struct {
 		Registry               image.EmbeddedArtifactRegistry
@@ -0,0 +1,229 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Finding is the machine-readable representation of a single failed validation.
+type Finding struct {
+	Component string   `json:"component"`
+	RuleID    string   `json:"ruleId"`
+	Severity  Severity `json:"severity"`
+	Message   string   `json:"message"`
+	Detail    string   `json:"detail,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	Line      int      `json:"line,omitempty"`
+	Column    int      `json:"column,omitempty"`
+}
+
+// Report collects the findings of validating a single image definition.
+type Report struct {
+	DefinitionFile string    `json:"definitionFile"`
+	Valid          bool      `json:"valid"`
+	Findings       []Finding `json:"findings"`
+}
+
+// NewReport converts the failed validations into findings ordered by component.
+// The definition data, as read from the definition file, is used to locate the line and column of each finding;
+// findings which cannot be located in it are reported without a position.
+//
+// The composed data is the definition after merging it over the definitions it extends, if any. Findings are then
+// only located if they refer to the same field in both, so that fields inherited from the extended definitions
+// are not reported at an unrelated position of the definition file.
+func NewReport(failures map[string][]FailedValidation, definitionFile string, definitionData, composedData []byte) *Report {
+	root := rootNode(definitionData)
+
+	var composedRoot *yaml.Node
+	if composedData != nil && !bytes.Equal(definitionData, composedData) {
+		composedRoot = rootNode(composedData)
+	}
+
+	componentNames := make([]string, 0, len(failures))
+	for c := range failures {
+		componentNames = append(componentNames, c)
+	}
+	slices.Sort(componentNames)
+
+	report := &Report{
+		DefinitionFile: definitionFile,
+		Findings:       []Finding{},
+	}
+
+	for _, componentName := range componentNames {
+		for _, failure := range failures[componentName] {
+			finding := Finding{
+				Component: componentName,
+				RuleID:    failure.RuleID,
+				Severity:  failure.Level(),
+				Message:   failure.UserMessage,
+				Path:      failure.Path,
+			}
+
+			if failure.Error != nil {
+				finding.Detail = log.Redact(failure.Error.Error())
+			}
+
+			node := locate(root, failure.Path)
+			if composedRoot != nil {
+				node = locateComposed(root, composedRoot, failure.Path)
+			}
+
+			if node != nil {
+				finding.Line, finding.Column = node.Line, node.Column
+			}
+
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	report.Valid = !slices.ContainsFunc(report.Findings, func(f Finding) bool {
+		return f.Severity == SeverityError
+	})
+
+	return report
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("encoding validation report: %w", err)
+	}
+
+	return nil
+}
+
+func rootNode(data []byte) *yaml.Node {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil || len(document.Content) == 0 {
+		return nil
+	}
+
+	return document.Content[0]
+}
+
+// locate finds the YAML node the path refers to, e.g. `kubernetes.helm.charts[2].valuesFile`.
+// Fields are located by their key, while list entries are located by the entry itself.
+// If the path is only partially present in the definition, the deepest matching node is returned.
+//
+// Paths which do not index into a list (e.g. `kubernetes.helm.charts.apiVersions`)
+// resolve to the first list entry containing the field.
+func locate(root *yaml.Node, path string) *yaml.Node {
+	if root == nil || path == "" {
+		return nil
+	}
+
+	var found *yaml.Node
+
+	node := root
+	for _, segment := range splitPath(path) {
+		next, located := followSegment(node, segment)
+		if next == nil {
+			break
+		}
+
+		found, node = located, next
+	}
+
+	return found
+}
+
+// locateComposed finds the YAML node the path refers to in the definition file only if the path is fully present
+// in it and refers to the same field of the composed definition. The entries of extended definitions precede
+// the ones of the definition file in composed lists, hence lists are only followed if the file defines all entries.
+func locateComposed(root, composedRoot *yaml.Node, path string) *yaml.Node {
+	if root == nil || composedRoot == nil || path == "" {
+		return nil
+	}
+
+	var found *yaml.Node
+
+	node, composed := root, composedRoot
+	for _, segment := range splitPath(path) {
+		if node.Kind == yaml.SequenceNode && len(node.Content) != len(composed.Content) {
+			return nil
+		}
+
+		next, located := followSegment(node, segment)
+		composedNext, _ := followSegment(composed, segment)
+		if next == nil || composedNext == nil {
+			return nil
+		}
+
+		found, node, composed = located, next, composedNext
+	}
+
+	return found
+}
+
+// followSegment returns the node a single segment of a path refers to along with the node locating it,
+// i.e. the key of a field or the entry of a list. Both are nil if the segment is not present.
+func followSegment(node *yaml.Node, segment string) (next, located *yaml.Node) {
+	if index, err := strconv.Atoi(segment); err == nil {
+		if node.Kind != yaml.SequenceNode || index < 0 || index >= len(node.Content) {
+			return nil, nil
+		}
+
+		return node.Content[index], node.Content[index]
+	}
+
+	if node.Kind == yaml.SequenceNode {
+		entry := slices.IndexFunc(node.Content, func(n *yaml.Node) bool {
+			return mappingKey(n, segment) != nil
+		})
+		if entry < 0 {
+			return nil, nil
+		}
+
+		node = node.Content[entry]
+	}
+
+	key := mappingKey(node, segment)
+	if key == nil {
+		return nil, nil
+	}
+
+	return mappingValue(node, segment), key
+}
+
+// splitPath splits the path into field names and list indexes,
+// e.g. `charts[2].valuesFile` is split into `charts`, `2` and `valuesFile`.
+func splitPath(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool {
+		return r == '.' || r == '[' || r == ']'
+	})
+}
+
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
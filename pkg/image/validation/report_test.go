@@ -0,0 +1,428 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"gopkg.in/yaml.v3"
+)
+
+const reportDefinition = `apiVersion: "1.4"
+image:
+  imageType: iso
+  arch: x86_64
+kubernetes:
+  version: v1.30.3+rke2r1
+  helm:
+    charts:
+      - name: apache
+        repositoryName: apache-repo
+        version: 10.7.0
+      - name: metallb
+        repositoryName: suse-edge
+        version: 0.14.3
+      - name: endpoint-copier-operator
+        repositoryName: suse-edge
+        version: 0.2.0
+        valuesFile: values.txt
+    repositories:
+      - name: apache-repo
+        url: oci://registry-1.docker.io/bitnamicharts
+      - name: suse-edge
+        url: https://suse-edge.github.io/charts
+`
+
+func TestLocate(t *testing.T) {
+	root := decodeRoot(t, reportDefinition)
+
+	tests := map[string]struct {
+		path           string
+		expectedLine   int
+		expectedColumn int
+	}{
+		"top level field": {
+			path:           "image.arch",
+			expectedLine:   4,
+			expectedColumn: 3,
+		},
+		"list entry": {
+			path:           "kubernetes.helm.charts[1]",
+			expectedLine:   12,
+			expectedColumn: 9,
+		},
+		"field of list entry": {
+			path:           "kubernetes.helm.charts[2].valuesFile",
+			expectedLine:   18,
+			expectedColumn: 9,
+		},
+		"missing field resolves to the closest parent": {
+			path:           "kubernetes.helm.charts[0].valuesFile",
+			expectedLine:   9,
+			expectedColumn: 9,
+		},
+		"field of unindexed list": {
+			path:           "kubernetes.helm.charts.valuesFile",
+			expectedLine:   18,
+			expectedColumn: 9,
+		},
+		"out of range index": {
+			path:           "kubernetes.helm.repositories[5].url",
+			expectedLine:   19,
+			expectedColumn: 5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			node := locate(root, test.path)
+			require.NotNil(t, node)
+
+			assert.Equal(t, test.expectedLine, node.Line)
+			assert.Equal(t, test.expectedColumn, node.Column)
+		})
+	}
+}
+
+func TestLocateMissing(t *testing.T) {
+	root := decodeRoot(t, reportDefinition)
+
+	assert.Nil(t, locate(root, ""))
+	assert.Nil(t, locate(root, "operatingSystem.users[0].username"))
+	assert.Nil(t, locate(nil, "image.arch"))
+}
+
+func TestNewReport(t *testing.T) {
+	// Setup
+	failures := map[string][]FailedValidation{
+		k8sComponent: {
+			{
+				UserMessage: "Helm chart 'valuesFile' field for \"endpoint-copier-operator\" must be the name of a valid yaml file ending in '.yaml' or '.yml'.",
+				RuleID:      "kubernetes/helm-chart-values-file-invalid",
+				Path:        "kubernetes.helm.charts[2].valuesFile",
+			},
+		},
+		imageComponent: {
+			{
+				UserMessage: "The 'baseImage' field is required in the 'image' section.",
+				RuleID:      "image/base-image-required",
+				Path:        "image.baseImage",
+			},
+			{
+				UserMessage: "Elemental config directory could not be read",
+				RuleID:      "elemental/config-dir-unreadable",
+				Error:       errors.New("permission denied"),
+			},
+		},
+	}
+
+	// Test
+	report := NewReport(failures, "definition.yaml", []byte(reportDefinition), nil)
+
+	// Verify
+	assert.Equal(t, "definition.yaml", report.DefinitionFile)
+	assert.False(t, report.Valid)
+
+	expected := []Finding{
+		{
+			Component: imageComponent,
+			RuleID:    "image/base-image-required",
+			Severity:  SeverityError,
+			Message:   "The 'baseImage' field is required in the 'image' section.",
+			Path:      "image.baseImage",
+			Line:      2,
+			Column:    1,
+		},
+		{
+			Component: imageComponent,
+			RuleID:    "elemental/config-dir-unreadable",
+			Severity:  SeverityError,
+			Message:   "Elemental config directory could not be read",
+			Detail:    "permission denied",
+		},
+		{
+			Component: k8sComponent,
+			RuleID:    "kubernetes/helm-chart-values-file-invalid",
+			Severity:  SeverityError,
+			Message:   "Helm chart 'valuesFile' field for \"endpoint-copier-operator\" must be the name of a valid yaml file ending in '.yaml' or '.yml'.",
+			Path:      "kubernetes.helm.charts[2].valuesFile",
+			Line:      18,
+			Column:    9,
+		},
+	}
+	assert.Equal(t, expected, report.Findings)
+}
+
+func TestNewReportComposedDefinition(t *testing.T) {
+	// Setup
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "base.yaml"), []byte(`
+image:
+  arch: x86_64
+kubernetes:
+  helm:
+    charts:
+      - name: apache
+        repositoryName: apache-repo
+        version: 10.7.0
+`), 0o600))
+
+	definition := []byte(`apiVersion: "1.4"
+extends:
+  - base.yaml
+image:
+  imageType: iso
+kubernetes:
+  helm:
+    charts:
+      - name: endpoint-copier-operator
+        valuesFile: values.txt
+`)
+
+	composed, err := image.ComposeDefinition(definition, configDir)
+	require.NoError(t, err)
+
+	failures := map[string][]FailedValidation{
+		imageComponent: {
+			{RuleID: "image/image-type-invalid", Path: "image.imageType"},
+			{RuleID: "image/arch-invalid", Path: "image.arch"},
+			{RuleID: "image/base-image-required", Path: "image.baseImage"},
+		},
+		k8sComponent: {
+			{RuleID: "kubernetes/helm-chart-values-file-invalid", Path: "kubernetes.helm.charts[1].valuesFile"},
+		},
+	}
+
+	// Test
+	report := NewReport(failures, "definition.yaml", definition, composed)
+
+	// Verify
+	positions := map[string][2]int{}
+	for _, finding := range report.Findings {
+		positions[finding.Path] = [2]int{finding.Line, finding.Column}
+	}
+
+	assert.Equal(t, map[string][2]int{
+		// Defined in the definition file
+		"image.imageType": {5, 3},
+		// Inherited from the extended definition
+		"image.arch": {},
+		// Missing from either definition
+		"image.baseImage": {},
+		// The entries of the extended definition precede the ones of the definition file
+		"kubernetes.helm.charts[1].valuesFile": {},
+	}, positions)
+}
+
+func TestNewReportValid(t *testing.T) {
+	report := NewReport(map[string][]FailedValidation{}, "definition.yaml", []byte(reportDefinition), nil)
+
+	assert.True(t, report.Valid)
+	assert.Empty(t, report.Findings)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+	assert.JSONEq(t, `{"definitionFile": "definition.yaml", "valid": true, "findings": []}`, buf.String())
+}
+
+func TestNewReportWarningsOnly(t *testing.T) {
+	failures := map[string][]FailedValidation{
+		osComponent: {
+			{
+				UserMessage: "Running EIB with disabled GPG validation is intended for development purposes only.",
+				RuleID:      "os/gpg-check-disabled",
+				Path:        "operatingSystem.packages.noGPGCheck",
+				Severity:    SeverityWarning,
+			},
+		},
+	}
+
+	report := NewReport(failures, "definition.yaml", []byte(reportDefinition), nil)
+
+	assert.True(t, report.Valid)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, SeverityWarning, report.Findings[0].Severity)
+
+	TreatWarningsAsErrors(failures)
+
+	report = NewReport(failures, "definition.yaml", []byte(reportDefinition), nil)
+
+	assert.False(t, report.Valid)
+	assert.Equal(t, SeverityError, report.Findings[0].Severity)
+}
+
+func TestReportFromValidation(t *testing.T) {
+	// Setup
+	def, err := image.ParseDefinition([]byte(reportDefinition), "")
+	require.NoError(t, err)
+
+	ctx := &image.Context{
+		ImageConfigDir:  t.TempDir(),
+		ImageDefinition: def,
+	}
+
+	// Test
+	report := NewReport(ValidateDefinition(ctx), "definition.yaml", []byte(reportDefinition), nil)
+
+	// Verify
+	var valuesFinding *Finding
+	for i := range report.Findings {
+		if report.Findings[i].RuleID == "kubernetes/helm-chart-values-file-invalid" {
+			valuesFinding = &report.Findings[i]
+		}
+	}
+
+	require.NotNil(t, valuesFinding)
+	assert.Equal(t, k8sComponent, valuesFinding.Component)
+	assert.Equal(t, "kubernetes.helm.charts[2].valuesFile", valuesFinding.Path)
+	assert.Equal(t, 18, valuesFinding.Line)
+	assert.Equal(t, 9, valuesFinding.Column)
+
+	for _, finding := range report.Findings {
+		assert.NotEmpty(t, finding.RuleID, finding.Message)
+	}
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	// Setup
+	report := &Report{
+		DefinitionFile: "definition.yaml",
+		Findings: []Finding{
+			{
+				Component: imageComponent,
+				RuleID:    "image/arch-required",
+				Severity:  SeverityError,
+				Message:   "The 'arch' field is required in the 'image' section.",
+				Path:      "image.arch",
+				Line:      3,
+				Column:    3,
+			},
+		},
+	}
+
+	// Test
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+
+	// Verify
+	expected := `{
+  "definitionFile": "definition.yaml",
+  "valid": false,
+  "findings": [
+    {
+      "component": "Image",
+      "ruleId": "image/arch-required",
+      "severity": "error",
+      "message": "The 'arch' field is required in the 'image' section.",
+      "path": "image.arch",
+      "line": 3,
+      "column": 3
+    }
+  ]
+}`
+	assert.JSONEq(t, expected, buf.String())
+}
+
+func TestReportWriteSARIF(t *testing.T) {
+	// Setup
+	report := &Report{
+		DefinitionFile: "definition.yaml",
+		Findings: []Finding{
+			{
+				Component: imageComponent,
+				RuleID:    "image/arch-required",
+				Severity:  SeverityError,
+				Message:   "The 'arch' field is required in the 'image' section.",
+				Path:      "image.arch",
+				Line:      3,
+				Column:    3,
+			},
+			{
+				Component: elementalComponent,
+				RuleID:    "elemental/config-dir-empty",
+				Severity:  SeverityError,
+				Message:   "Elemental config directory should not be present if it is empty",
+			},
+		},
+	}
+
+	// Test
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSARIF(&buf, "1.2.0"))
+
+	// Verify
+	expected := `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "edge-image-builder",
+          "version": "1.2.0",
+          "informationUri": "https://github.com/suse-edge/edge-image-builder",
+          "rules": [
+            {"id": "image/arch-required", "properties": {"component": "Image"}},
+            {"id": "elemental/config-dir-empty", "properties": {"component": "Elemental"}}
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "image/arch-required",
+          "level": "error",
+          "message": {"text": "The 'arch' field is required in the 'image' section."},
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {"uri": "definition.yaml"},
+                "region": {"startLine": 3, "startColumn": 3}
+              },
+              "logicalLocations": [{"fullyQualifiedName": "image.arch"}]
+            }
+          ]
+        },
+        {
+          "ruleId": "elemental/config-dir-empty",
+          "level": "error",
+          "message": {"text": "Elemental config directory should not be present if it is empty"},
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {"uri": "definition.yaml"}
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+	assert.JSONEq(t, expected, buf.String())
+}
+
+func TestQualifyPaths(t *testing.T) {
+	failures := []FailedValidation{
+		{Path: "valuesFile"},
+		{Path: "[1]"},
+		{},
+	}
+
+	qualified := qualifyPaths(failures, "kubernetes.helm.charts[2]")
+
+	assert.Equal(t, "kubernetes.helm.charts[2].valuesFile", qualified[0].Path)
+	assert.Equal(t, "kubernetes.helm.charts[2][1]", qualified[1].Path)
+	assert.Equal(t, "kubernetes.helm.charts[2]", qualified[2].Path)
+}
+
+func decodeRoot(t *testing.T, definition string) *yaml.Node {
+	var document yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(definition), &document))
+
+	return document.Content[0]
+}
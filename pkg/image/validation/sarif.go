@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	toolName           = "edge-image-builder"
+	toolInformationURI = "https://github.com/suse-edge/edge-image-builder"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID         string              `json:"id"`
+	Properties sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleProperties struct {
+	Component string `json:"component"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteSARIF writes the report in the Static Analysis Results Interchange Format (SARIF) 2.1.0.
+func (r *Report) WriteSARIF(w io.Writer, toolVersion string) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				Version:        toolVersion,
+				InformationURI: toolInformationURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	var ruleIDs []string
+	for _, finding := range r.Findings {
+		if !slices.Contains(ruleIDs, finding.RuleID) {
+			ruleIDs = append(ruleIDs, finding.RuleID)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:         finding.RuleID,
+				Properties: sarifRuleProperties{Component: finding.Component},
+			})
+		}
+
+		run.Results = append(run.Results, r.sarifResult(finding))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	sarif := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	if err := encoder.Encode(sarif); err != nil {
+		return fmt.Errorf("encoding SARIF validation report: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Report) sarifResult(finding Finding) sarifResult {
+	result := sarifResult{
+		RuleID:  finding.RuleID,
+		Level:   sarifLevel(finding.Severity),
+		Message: sarifMessage{Text: finding.Message},
+	}
+
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: r.DefinitionFile},
+		},
+	}
+
+	if finding.Line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{
+			StartLine:   finding.Line,
+			StartColumn: finding.Column,
+		}
+	}
+
+	if finding.Path != "" {
+		location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: finding.Path}}
+	}
+
+	result.Locations = []sarifLocation{location}
+
+	return result
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
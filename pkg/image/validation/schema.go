@@ -0,0 +1,375 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	secretReferenceDef = "secretReference"
+	cniTypeDef         = "cniType"
+)
+
+// fieldSchema holds the schema details of a definition field which cannot be derived from its Go type.
+type fieldSchema struct {
+	description string
+	enum        []string
+	pattern     string
+}
+
+// schemaFields describes every field of the image.Definition struct tree,
+// keyed by the name of the Go type containing the field and the field name itself.
+//
+// All fields must be listed here, so that new additions to the definition are reflected in the schema.
+var schemaFields = map[string]fieldSchema{
+	"Definition.APIVersion":               {description: "Version of the image definition schema."},
+	"Definition.Image":                    {description: "Details of the base image and the image which will be built."},
+	"Definition.OperatingSystem":          {description: "Configuration of the operating system."},
+	"Definition.EmbeddedArtifactRegistry": {description: "Container images and registries to embed in the built image."},
+	"Definition.Kubernetes":               {description: "Configuration of the Kubernetes cluster to install."},
+	"Definition.NodeOverrides":            {description: "Operating system configuration applied to individual nodes, matched by hostname."},
+
+	"Image.ImageType":       {description: "Type of the image to build.", enum: []string{image.TypeISO, image.TypeRAW}},
+	"Image.Arch":            {description: "Architecture of the base image.", enum: []string{string(image.ArchTypeX86), string(image.ArchTypeARM)}},
+	"Image.BaseImage":       {description: "Name of the base image file under the `base-images` directory."},
+	"Image.OutputImageName": {description: "Name of the built image file."},
+
+	"OperatingSystem.KernelArgs":       {description: "Kernel arguments to set on boot."},
+	"OperatingSystem.Groups":           {description: "Groups to create."},
+	"OperatingSystem.Users":            {description: "Users to create."},
+	"OperatingSystem.Systemd":          {description: "Systemd units to enable or disable."},
+	"OperatingSystem.Suma":             {description: "SUSE Multi-Linux Manager registration."},
+	"OperatingSystem.Packages":         {description: "Packages to install and the repositories providing them."},
+	"OperatingSystem.IsoConfiguration": {description: "Configuration specific to ISO images."},
+	"OperatingSystem.RawConfiguration": {description: "Configuration specific to RAW images."},
+	"OperatingSystem.Time":             {description: "Timezone and NTP configuration."},
+	"OperatingSystem.Proxy":            {description: "Proxy configuration of the operating system."},
+	"OperatingSystem.Keymap":           {description: "Virtual console keymap."},
+	"OperatingSystem.EnableFIPS":       {description: "Enables FIPS mode."},
+
+	"OperatingSystemNodeOverride.KernelArgs": {description: "Kernel arguments to set on boot of the node."},
+	"OperatingSystemNodeOverride.Groups":     {description: "Groups to create on the node."},
+	"OperatingSystemNodeOverride.Users":      {description: "Users to create on the node."},
+	"OperatingSystemNodeOverride.Systemd":    {description: "Systemd units to enable or disable on the node."},
+
+	"IsoConfiguration.InstallDevice": {description: "Block device the ISO image installs to, e.g. `/dev/sda`."},
+
+	"RawConfiguration.DiskSize":                 {description: "Size the RAW image is resized to, e.g. `32G`.", pattern: image.DiskSizePattern},
+	"RawConfiguration.LUKSKey":                  {description: "LUKS key of an encrypted base image."},
+	"RawConfiguration.ExpandEncryptedPartition": {description: "Expands the encrypted partition to fill the disk."},
+
+	"Packages.NoGPGCheck":      {description: "Disables GPG validation of all packages. Not intended for production use."},
+	"Packages.EnableExtras":    {description: "Enables the SUSE Linux Micro Extras repository."},
+	"Packages.PKGList":         {description: "Packages to install."},
+	"Packages.AdditionalRepos": {description: "Third party repositories to install the packages from."},
+	"Packages.RegCode":         {description: "SUSE Customer Center registration code used to access the SUSE repositories."},
+
+	"AddRepo.URL":      {description: "URL of the repository."},
+	"AddRepo.Unsigned": {description: "Disables GPG validation of the repository."},
+	"AddRepo.Priority": {description: "Priority of the repository, from 1 (highest) to 99 (lowest)."},
+
+	"OperatingSystemUser.Username":          {description: "Name of the user."},
+	"OperatingSystemUser.UID":               {description: "User ID."},
+	"OperatingSystemUser.EncryptedPassword": {description: "Encrypted password of the user, e.g. as generated by `openssl passwd -6`."},
+	"OperatingSystemUser.SSHKeys":           {description: "Public SSH keys authorized for the user."},
+	"OperatingSystemUser.PrimaryGroup":      {description: "Primary group of the user."},
+	"OperatingSystemUser.SecondaryGroups":   {description: "Additional groups the user belongs to."},
+	"OperatingSystemUser.CreateHomeDir":     {description: "Creates a home directory for the user."},
+
+	"OperatingSystemGroup.Name": {description: "Name of the group."},
+	"OperatingSystemGroup.GID":  {description: "Group ID."},
+
+	"Systemd.Enable":  {description: "Systemd units to enable."},
+	"Systemd.Disable": {description: "Systemd units to disable."},
+
+	"Suma.Host":          {description: "Host of the SUSE Multi-Linux Manager server."},
+	"Suma.ActivationKey": {description: "Activation key used to register the system."},
+
+	"Time.Timezone":         {description: "Timezone of the system, e.g. `Europe/London`."},
+	"Time.NtpConfiguration": {description: "NTP sources of the system."},
+
+	"NtpConfiguration.ForceWait": {description: "Waits for the time to be synchronized before starting any other services."},
+	"NtpConfiguration.Pools":     {description: "NTP pools to use."},
+	"NtpConfiguration.Servers":   {description: "NTP servers to use."},
+
+	"Proxy.HTTPProxy":  {description: "Proxy used for HTTP connections."},
+	"Proxy.HTTPSProxy": {description: "Proxy used for HTTPS connections."},
+	"Proxy.NoProxy":    {description: "Hosts which are accessed without the proxy."},
+
+	"EmbeddedArtifactRegistry.ContainerImages": {description: "Container images to embed."},
+	"EmbeddedArtifactRegistry.Registries":      {description: "Registries to pull the container images from."},
+	"EmbeddedArtifactRegistry.ImageRules": {
+		description: "Rules extracting the container images referenced by the manifests and Helm charts, " +
+			"in addition to the containers of their workloads and the built-in rules.",
+	},
+
+	"ContainerImage.Name": {description: "Reference of the container image, e.g. `hello-world:latest`."},
+
+	"ImageRule.Kind":     {description: "Kind of the resources the rule applies to. Defaults to all kinds."},
+	"ImageRule.Selector": {description: "JSONPath-like selector of the values referencing images, e.g. `spec.imageName`, `spec.containers[*].image` or `..imageName`."},
+	"ImageRule.Template": {description: "Template building the image from each selected value, e.g. `{{ .repository }}:{{ .version }}`."},
+
+	"Registry.URI":            {description: "URI of the registry."},
+	"Registry.Authentication": {description: "Credentials for the registry."},
+
+	"RegistryAuthentication.Username": {description: "Username used to authenticate to the registry."},
+	"RegistryAuthentication.Password": {description: "Password used to authenticate to the registry."},
+
+	"Kubernetes.Version":   {description: "Version of the RKE2 or K3s distribution to install, e.g. `v1.30.3+rke2r1`, or a release channel resolved at build time, e.g. `rke2:stable`."},
+	"Kubernetes.Network":   {description: "Network configuration of the cluster."},
+	"Kubernetes.Nodes":     {description: "Nodes of a multi-node cluster."},
+	"Kubernetes.Manifests": {description: "Manifests to apply after the cluster is installed."},
+	"Kubernetes.Helm":      {description: "Helm charts to install after the cluster is installed."},
+
+	"Network.APIHost": {description: "Hostname of the cluster API."},
+	"Network.APIVIP4": {description: "IPv4 virtual IP address of the cluster API."},
+	"Network.APIVIP6": {description: "IPv6 virtual IP address of the cluster API."},
+	"Network.VIPProvider": {
+		description: "Component serving the virtual IP addresses of the cluster API. Defaults to `metallb`.",
+		enum:        []string{image.VIPProviderMetalLB, image.VIPProviderKubeVIP, image.VIPProviderNone},
+	},
+
+	"Node.Hostname":    {description: "Hostname of the node."},
+	"Node.Type":        {description: "Type of the node.", enum: []string{image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent}},
+	"Node.Initialiser": {description: "Marks the server node which initializes the cluster."},
+	"Node.Labels":      {description: "Labels registered with the node when it joins the cluster."},
+	"Node.Taints":      {description: "Taints registered with the node when it joins the cluster, in the `key[=value]:effect` format."},
+	"Node.Config":      {description: "Kubernetes configuration options which override the ones shared by all nodes of the same type."},
+
+	"NodeOverride.Hostname":        {description: "Hostname of the node the configuration is applied to."},
+	"NodeOverride.OperatingSystem": {description: "Operating system configuration of the node."},
+
+	"Manifests.URLs": {description: "URLs of the manifests to download."},
+
+	"Helm.Charts":       {description: "Helm charts to install."},
+	"Helm.Repositories": {description: "Repositories providing the Helm charts."},
+
+	"HelmChart.Name":                  {description: "Name of the chart in the repository."},
+	"HelmChart.ReleaseName":           {description: "Name of the Helm release. Defaults to the chart name."},
+	"HelmChart.RepositoryName":        {description: "Name of the repository providing the chart."},
+	"HelmChart.Version":               {description: "Version of the chart."},
+	"HelmChart.TargetNamespace":       {description: "Namespace the chart is deployed to."},
+	"HelmChart.CreateNamespace":       {description: "Creates the target namespace if it does not exist."},
+	"HelmChart.InstallationNamespace": {description: "Namespace of the Helm controller job installing the chart."},
+	"HelmChart.ValuesFile":            {description: "Name of the values file under the `kubernetes/helm/values` directory."},
+	"HelmChart.APIVersions":           {description: "Kubernetes API versions used when templating the chart."},
+	"HelmChart.CRDHandling": {
+		description: "Handling of the CRDs of the chart. `skip` leaves them to the Helm controller, `include` renders them along with the chart, " +
+			"`separate` also installs them in a manifest of their own ahead of the chart. Defaults to `skip`.",
+		enum: []string{image.CRDHandlingSkip, image.CRDHandlingInclude, image.CRDHandlingSeparate},
+	},
+	"HelmChart.ImagePaths": {description: "Locations of the container images referenced by the custom resources of the chart."},
+
+	"ImagePath.Kind": {description: "Kind of the custom resources referencing the images."},
+	"ImagePath.Path": {
+		description: "Dot separated path of the image fields, e.g. `spec.image`. Fields suffixed with `[*]` select every item of a list.",
+		pattern:     image.ImagePathPattern,
+	},
+
+	"HelmRepository.Name":           {description: "Name of the repository, referenced by the charts."},
+	"HelmRepository.URL":            {description: "URL of the repository. OCI repositories use the `oci://` scheme."},
+	"HelmRepository.Authentication": {description: "Credentials for the repository."},
+	"HelmRepository.PlainHTTP":      {description: "Connects to the repository over plain HTTP."},
+	"HelmRepository.SkipTLSVerify":  {description: "Skips verification of the repository TLS certificate."},
+	"HelmRepository.CAFile":         {description: "Name of the CA certificate file under the `kubernetes/helm/certs` directory."},
+
+	"HelmAuthentication.Username": {description: "Username used to authenticate to the repository."},
+	"HelmAuthentication.Password": {description: "Password used to authenticate to the repository."},
+}
+
+// DefinitionSchema generates the JSON Schema of the image definition for the given API version.
+// Fields introduced by later API versions are left out of the schema.
+func DefinitionSchema(apiVersion string) (map[string]any, error) {
+	if !version.IsSchemaVersionSupported(apiVersion) {
+		return nil, fmt.Errorf("unsupported API version: %s", apiVersion)
+	}
+
+	g := schemaGenerator{
+		apiVersion:   apiVersion,
+		secretFields: image.SecretFieldPaths(),
+	}
+
+	schema, err := g.objectSchema(reflect.TypeOf(image.Definition{}), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := schema["properties"].(map[string]any)
+	properties["apiVersion"] = map[string]any{
+		"description": schemaFields["Definition.APIVersion"].description,
+		"const":       apiVersion,
+	}
+
+	if g.availableIn(image.ExtendsAPIVersion) {
+		properties["extends"] = map[string]any{
+			"description": "Image definitions, relative to the configuration directory, which this definition is merged over.",
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+		}
+	}
+
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = "Edge Image Builder image definition"
+	schema["description"] = fmt.Sprintf("Image definition schema for API version %s.", apiVersion)
+	schema["required"] = []string{"apiVersion"}
+	schema["$defs"] = g.definitions()
+
+	return schema, nil
+}
+
+type schemaGenerator struct {
+	apiVersion   string
+	secretFields []string
+}
+
+func (g schemaGenerator) availableIn(apiVersion string) bool {
+	return strings.Compare(g.apiVersion, apiVersion) >= 0
+}
+
+// objectSchema generates the schema of the given struct type located under the Go field chain
+// and the YAML key path (with `*` standing for every element of a list).
+func (g schemaGenerator) objectSchema(t reflect.Type, chain, path []string) (map[string]any, error) {
+	properties := map[string]any{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "" {
+			return nil, fmt.Errorf("field %s.%s has no YAML key", t.Name(), field.Name)
+		}
+
+		fieldChain := append(slices.Clone(chain), field.Name)
+		if !g.fieldAvailable(fieldChain) {
+			continue
+		}
+
+		meta, ok := schemaFields[t.Name()+"."+field.Name]
+		if !ok {
+			return nil, fmt.Errorf("field %s.%s is not described in the schema", t.Name(), field.Name)
+		}
+
+		fieldPath := append(slices.Clone(path), key)
+
+		property, err := g.typeSchema(field.Type, fieldChain, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+
+		property["description"] = meta.description
+		if len(meta.enum) != 0 {
+			property["enum"] = meta.enum
+		}
+		if meta.pattern != "" {
+			property["pattern"] = meta.pattern
+		}
+
+		if g.availableIn(image.SecretsAPIVersion) && slices.Contains(g.secretFields, strings.Join(fieldPath, ".")) {
+			property = map[string]any{
+				"description": meta.description,
+				"oneOf": []any{
+					map[string]any{"type": "string"},
+					map[string]any{"$ref": "#/$defs/" + secretReferenceDef},
+				},
+			}
+		}
+
+		properties[key] = property
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}, nil
+}
+
+func (g schemaGenerator) typeSchema(t reflect.Type, chain, path []string) (map[string]any, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Slice:
+		items, err := g.typeSchema(t.Elem(), chain, append(slices.Clone(path), "*"))
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		values, err := g.typeSchema(t.Elem(), chain, append(slices.Clone(path), "*"))
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Interface:
+		// Free-form values accept any YAML type
+		return map[string]any{}, nil
+	case reflect.Struct:
+		return g.objectSchema(t, chain, path)
+	default:
+		return nil, fmt.Errorf("unsupported field type: %s", t.Kind())
+	}
+}
+
+// fieldAvailable checks whether the field is available in the API version of the schema.
+func (g schemaGenerator) fieldAvailable(chain []string) bool {
+	for apiVersion, fields := range definitionFields {
+		if g.availableIn(apiVersion) {
+			continue
+		}
+
+		for _, field := range fields {
+			if slices.Equal(field.Chain, chain) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (g schemaGenerator) definitions() map[string]any {
+	defs := map[string]any{
+		// The CNI is configured through the `cni` option in `kubernetes/config/server.yaml`
+		// rather than the definition itself. Its supported values are published for reference.
+		cniTypeDef: map[string]any{
+			"description": "CNI set in the `cni` option of the Kubernetes server configuration file.",
+			"type":        "string",
+			"enum":        []string{image.CNITypeNone, image.CNITypeCilium, image.CNITypeCanal, image.CNITypeCalico},
+		},
+	}
+
+	if g.availableIn(image.SecretsAPIVersion) {
+		defs[secretReferenceDef] = map[string]any{
+			"description": "Reference to a secret stored outside of the definition.",
+			"type":        "object",
+			"properties": map[string]any{
+				"fromEnv": map[string]any{
+					"description": "Name of the environment variable holding the secret.",
+					"type":        "string",
+				},
+				"fromFile": map[string]any{
+					"description": "Path of the file holding the secret, relative to the configuration directory.",
+					"type":        "string",
+				},
+			},
+			"minProperties":        1,
+			"maxProperties":        1,
+			"additionalProperties": false,
+		}
+	}
+
+	return defs
+}
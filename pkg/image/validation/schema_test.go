@@ -0,0 +1,185 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestSchemaFieldsDescribeDefinition(t *testing.T) {
+	// Setup
+	definitionFieldNames := map[string]bool{}
+	collectFieldNames(reflect.TypeOf(image.Definition{}), definitionFieldNames)
+
+	// Test
+	for name := range definitionFieldNames {
+		meta, ok := schemaFields[name]
+
+		// Verify
+		if assert.True(t, ok, "field %s must be described in the definition schema", name) {
+			assert.NotEmpty(t, meta.description, "field %s must have a description", name)
+		}
+	}
+
+	for name := range schemaFields {
+		assert.True(t, definitionFieldNames[name], "schema describes unknown field %s", name)
+	}
+}
+
+func collectFieldNames(t reflect.Type, names map[string]bool) {
+	switch t.Kind() {
+	case reflect.Slice:
+		collectFieldNames(t.Elem(), names)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			names[t.Name()+"."+t.Field(i).Name] = true
+			collectFieldNames(t.Field(i).Type, names)
+		}
+	default:
+	}
+}
+
+func TestDefinitionSchemaFieldAvailability(t *testing.T) {
+	tests := map[string]struct {
+		apiVersion string
+		present    []string
+		absent     []string
+	}{
+		"1.0": {
+			apiVersion: "1.0",
+			present:    []string{"image.imageType", "operatingSystem.users.*.username", "kubernetes.helm.charts.*.valuesFile"},
+			absent: []string{
+				"operatingSystem.enableFIPS",
+				"kubernetes.helm.charts.*.apiVersions",
+				"kubernetes.network.apiVIP6",
+				"embeddedArtifactRegistry.registries",
+				"operatingSystem.packages.additionalRepos.*.priority",
+				"nodeOverrides",
+				"extends",
+			},
+		},
+		"1.2": {
+			apiVersion: "1.2",
+			present:    []string{"operatingSystem.enableFIPS", "kubernetes.network.apiVIP6", "embeddedArtifactRegistry.registries.*.uri"},
+			absent:     []string{"operatingSystem.packages.additionalRepos.*.priority", "nodeOverrides"},
+		},
+		"1.3": {
+			apiVersion: "1.3",
+			present:    []string{"operatingSystem.packages.additionalRepos.*.priority"},
+			absent:     []string{"nodeOverrides", "extends", "kubernetes.nodes.*.labels", "kubernetes.nodes.*.config"},
+		},
+		"1.4": {
+			apiVersion: "1.4",
+			present: []string{
+				"nodeOverrides.*.operatingSystem.users.*.username",
+				"extends",
+				"kubernetes.nodes.*.labels",
+				"kubernetes.nodes.*.taints",
+				"kubernetes.nodes.*.config",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema, err := DefinitionSchema(test.apiVersion)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.apiVersion, schemaProperty(t, schema, "apiVersion")["const"])
+
+			for _, path := range test.present {
+				assert.NotNil(t, schemaProperty(t, schema, path), "%s must be present", path)
+			}
+
+			for _, path := range test.absent {
+				assert.Nil(t, schemaProperty(t, schema, path), "%s must be absent", path)
+			}
+		})
+	}
+}
+
+func TestDefinitionSchemaConstraints(t *testing.T) {
+	schema, err := DefinitionSchema("1.3")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	assert.Equal(t, []string{"iso", "raw"}, schemaProperty(t, schema, "image.imageType")["enum"])
+	assert.Equal(t, []string{"x86_64", "aarch64"}, schemaProperty(t, schema, "image.arch")["enum"])
+	assert.Equal(t, []string{"server", "agent"}, schemaProperty(t, schema, "kubernetes.nodes.*.type")["enum"])
+	assert.Equal(t, image.DiskSizePattern, schemaProperty(t, schema, "operatingSystem.rawConfiguration.diskSize")["pattern"])
+	assert.Equal(t, "integer", schemaProperty(t, schema, "operatingSystem.users.*.uid")["type"])
+	assert.Equal(t, "boolean", schemaProperty(t, schema, "operatingSystem.time.ntp.forceWait")["type"])
+
+	defs := schema["$defs"].(map[string]any)
+	cni := defs["cniType"].(map[string]any)
+	assert.Equal(t, []string{"none", "cilium", "canal", "calico"}, cni["enum"])
+	assert.NotContains(t, defs, "secretReference")
+
+	password := schemaProperty(t, schema, "kubernetes.helm.repositories.*.authentication.password")
+	assert.Equal(t, "string", password["type"])
+}
+
+func TestDefinitionSchemaMaps(t *testing.T) {
+	schema, err := DefinitionSchema("1.4")
+	require.NoError(t, err)
+
+	labels := schemaProperty(t, schema, "kubernetes.nodes.*.labels")
+	assert.Equal(t, "object", labels["type"])
+	assert.Equal(t, map[string]any{"type": "string"}, labels["additionalProperties"])
+	assert.Equal(t, map[string]any{}, schemaProperty(t, schema, "kubernetes.nodes.*.config")["additionalProperties"])
+}
+
+func TestDefinitionSchemaSecretReferences(t *testing.T) {
+	schema, err := DefinitionSchema("1.4")
+	require.NoError(t, err)
+
+	assert.Contains(t, schema["$defs"], "secretReference")
+
+	for _, path := range image.SecretFieldPaths() {
+		property := schemaProperty(t, schema, path)
+		require.NotNil(t, property, "%s must be present", path)
+
+		assert.Equal(t, []any{
+			map[string]any{"type": "string"},
+			map[string]any{"$ref": "#/$defs/secretReference"},
+		}, property["oneOf"], path)
+	}
+
+	// The schema must be serializable
+	_, err = json.Marshal(schema)
+	assert.NoError(t, err)
+}
+
+func TestDefinitionSchemaUnsupportedVersion(t *testing.T) {
+	_, err := DefinitionSchema("0.9")
+	assert.EqualError(t, err, "unsupported API version: 0.9")
+}
+
+// schemaProperty looks up the schema of the field at the given path, with `*` referring to the list items.
+func schemaProperty(t *testing.T, schema map[string]any, path string) map[string]any {
+	t.Helper()
+
+	current := schema
+	for _, segment := range splitPath(path) {
+		var next any
+		if segment == "*" {
+			next = current["items"]
+		} else if properties, ok := current["properties"].(map[string]any); ok {
+			next = properties[segment]
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		current = next.(map[string]any)
+	}
+
+	return current
+}
@@ -1,12 +1,40 @@
 package validation
 
 import (
+	"strings"
+
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
 type FailedValidation struct {
 	UserMessage string
 	Error       error
+	// RuleID is a stable identifier of the check which produced the failure.
+	RuleID string
+	// Path is the location of the offending field in the image definition,
+	// e.g. `kubernetes.helm.charts[2].valuesFile`. Failures which cannot be
+	// attributed to a single field leave it empty or point to the enclosing section.
+	Path string
+	// Severity determines whether the failure prevents the image from being built.
+	// Failures which do not specify a severity are errors.
+	Severity Severity
+}
+
+// Level returns the severity of the failure.
+func (f *FailedValidation) Level() Severity {
+	if f.Severity == "" {
+		return SeverityError
+	}
+
+	return f.Severity
 }
 
 type validateComponent func(ctx *image.Context) []FailedValidation
@@ -15,12 +43,13 @@ func ValidateDefinition(ctx *image.Context) map[string][]FailedValidation {
 	failures := map[string][]FailedValidation{}
 
 	validations := map[string]validateComponent{
-		versionComponent:   validateVersion,
-		imageComponent:     validateImage,
-		osComponent:        validateOperatingSystem,
-		registryComponent:  validateEmbeddedArtifactRegistry,
-		k8sComponent:       validateKubernetes,
-		elementalComponent: validateElemental,
+		versionComponent:       validateVersion,
+		imageComponent:         validateImage,
+		osComponent:            validateOperatingSystem,
+		registryComponent:      validateEmbeddedArtifactRegistry,
+		k8sComponent:           validateKubernetes,
+		elementalComponent:     validateElemental,
+		nodeOverridesComponent: validateNodeOverrides,
 	}
 	for componentName, v := range validations {
 		componentFailures := v(ctx)
@@ -33,6 +62,34 @@ func ValidateDefinition(ctx *image.Context) map[string][]FailedValidation {
 	return failures
 }
 
+// TreatWarningsAsErrors raises the severity of all warnings to errors.
+func TreatWarningsAsErrors(failures map[string][]FailedValidation) {
+	for _, componentFailures := range failures {
+		for i := range componentFailures {
+			if componentFailures[i].Severity == SeverityWarning {
+				componentFailures[i].Severity = SeverityError
+			}
+		}
+	}
+}
+
+// qualifyPaths prefixes the paths of the failures with the location of the
+// definition section they were found in.
+func qualifyPaths(failures []FailedValidation, prefix string) []FailedValidation {
+	for i := range failures {
+		switch {
+		case failures[i].Path == "":
+			failures[i].Path = prefix
+		case strings.HasPrefix(failures[i].Path, "["):
+			failures[i].Path = prefix + failures[i].Path
+		default:
+			failures[i].Path = prefix + "." + failures[i].Path
+		}
+	}
+
+	return failures
+}
+
 func findDuplicates(items []string) []string {
 	var duplicates []string
 
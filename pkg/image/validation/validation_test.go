@@ -116,3 +116,19 @@ func TestValidateDefinition(t *testing.T) {
 		})
 	}
 }
+
+func TestTreatWarningsAsErrors(t *testing.T) {
+	failures := map[string][]FailedValidation{
+		osComponent: {
+			{UserMessage: "error"},
+			{UserMessage: "warning", Severity: SeverityWarning},
+			{UserMessage: "info", Severity: SeverityInfo},
+		},
+	}
+
+	TreatWarningsAsErrors(failures)
+
+	assert.Equal(t, SeverityError, failures[osComponent][0].Level())
+	assert.Equal(t, SeverityError, failures[osComponent][1].Level())
+	assert.Equal(t, SeverityInfo, failures[osComponent][2].Level())
+}
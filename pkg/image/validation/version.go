@@ -34,6 +34,16 @@ var definitionFields = map[string][]imageDefinitionField{
 		{Key: "embeddedArtifactRegistry.registries", Chain: []string{"EmbeddedArtifactRegistry", "Registries"}},
 	},
 	"1.3": {{Key: "operatingSystem.packages.additionalRepos.priority", Chain: []string{"OperatingSystem", "Packages", "AdditionalRepos", "Priority"}}},
+	"1.4": {
+		{Key: "nodeOverrides", Chain: []string{"NodeOverrides"}},
+		{Key: "kubernetes.nodes.labels", Chain: []string{"Kubernetes", "Nodes", "Labels"}},
+		{Key: "kubernetes.nodes.taints", Chain: []string{"Kubernetes", "Nodes", "Taints"}},
+		{Key: "kubernetes.nodes.config", Chain: []string{"Kubernetes", "Nodes", "Config"}},
+		{Key: "kubernetes.network.vipProvider", Chain: []string{"Kubernetes", "Network", "VIPProvider"}},
+		{Key: "kubernetes.helm.charts.crdHandling", Chain: []string{"Kubernetes", "Helm", "Charts", "CRDHandling"}},
+		{Key: "kubernetes.helm.charts.imagePaths", Chain: []string{"Kubernetes", "Helm", "Charts", "ImagePaths"}},
+		{Key: "embeddedArtifactRegistry.imageRules", Chain: []string{"EmbeddedArtifactRegistry", "ImageRules"}},
+	},
 }
 
 func validateVersion(ctx *image.Context) []FailedValidation {
@@ -49,6 +59,8 @@ func validateVersion(ctx *image.Context) []FailedValidation {
 			if isValueNonZero(rootValue, field.Chain) {
 				failures = append(failures, FailedValidation{
 					UserMessage: fmt.Sprintf("Field `%s` is only available in API version >= %s", field.Key, apiVersion),
+					RuleID:      "version/field-unavailable",
+					Path:        field.Key,
 				})
 			}
 		}
@@ -167,6 +167,61 @@ func TestValidateVersion(t *testing.T) {
 				},
 			},
 		},
+		`invalid 1.3 definition`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.3",
+				NodeOverrides: []image.NodeOverride{
+					{
+						Hostname: "node1.suse.com",
+					},
+				},
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ImageRules: []image.ImageRule{{Selector: "spec.imageName"}},
+				},
+				Kubernetes: image.Kubernetes{
+					Network: image.Network{
+						VIPProvider: image.VIPProviderKubeVIP,
+					},
+					Helm: image.Helm{
+						Charts: []image.HelmChart{
+							{
+								Name:        "operator",
+								CRDHandling: image.CRDHandlingInclude,
+								ImagePaths:  []image.ImagePath{{Kind: "Cluster", Path: "spec.image"}},
+							},
+						},
+					},
+					Nodes: []image.Node{
+						{
+							Hostname: "node1.suse.com",
+							Labels:   map[string]string{"zone": "a"},
+							Taints:   []string{"dedicated:NoSchedule"},
+							Config:   map[string]any{"node-ip": "192.168.122.10"},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Field `nodeOverrides` is only available in API version >= 1.4",
+				"Field `kubernetes.nodes.labels` is only available in API version >= 1.4",
+				"Field `kubernetes.nodes.taints` is only available in API version >= 1.4",
+				"Field `kubernetes.nodes.config` is only available in API version >= 1.4",
+				"Field `kubernetes.network.vipProvider` is only available in API version >= 1.4",
+				"Field `kubernetes.helm.charts.crdHandling` is only available in API version >= 1.4",
+				"Field `kubernetes.helm.charts.imagePaths` is only available in API version >= 1.4",
+				"Field `embeddedArtifactRegistry.imageRules` is only available in API version >= 1.4",
+			},
+		},
+		`valid new fields for 1.4`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.4",
+				NodeOverrides: []image.NodeOverride{
+					{
+						Hostname: "node1.suse.com",
+					},
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
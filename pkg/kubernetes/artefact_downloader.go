@@ -7,7 +7,9 @@ import (
 	"io"
 	"io/fs"
 	"net/url"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
@@ -21,7 +23,6 @@ import (
 const (
 	rke2Binary     = "rke2.linux-%s.tar.gz"
 	rke2CoreImages = "rke2-images-core.linux-%s.tar.zst"
-	rke2Checksums  = "sha256sum-%s.txt"
 
 	rke2CalicoImages  = "rke2-images-calico.linux-%s.tar.zst"
 	rke2CanalImages   = "rke2-images-canal.linux-%s.tar.zst"
@@ -31,17 +32,49 @@ const (
 
 	k3sBinary = "k3s"
 	k3sImages = "k3s-airgap-images-%s.tar.zst"
+
+	checksumsFile = "sha256sum-%s.txt"
 )
 
 type cache interface {
+	Lock(artefact string) (unlock func(), err error)
 	Get(artefact string) (filepath string, err error)
-	Put(artefact string, reader io.Reader) error
+	Put(artefact, source string, reader io.Reader) error
+}
+
+// artefactReport records the artefacts which have been verified against the checksums published with their release.
+type artefactReport interface {
+	RecordVerifiedArtefact(name, source, digest string)
 }
 
+// ArtefactDownloader retrieves the artefacts of RKE2 and K3s releases, verifying each of them
+// against the checksum file published with the release.
 type ArtefactDownloader struct {
 	Cache          cache
+	Report         artefactReport
 	Rke2ReleaseURL string
 	K3sReleaseURL  string
+	// Rke2Mirrors and K3sMirrors list the base URLs which are tried in turn
+	// if downloading from the respective release URL fails.
+	Rke2Mirrors []string
+	K3sMirrors  []string
+	// Parallelism is the maximum number of artefacts downloaded concurrently.
+	Parallelism int
+}
+
+// releaseSource is the location of the releases of a Kubernetes distribution along with its mirrors.
+type releaseSource struct {
+	url     string
+	mirrors []string
+}
+
+// artefactURLs returns the URL of the given artefact along with its URLs on the mirrors.
+func (s releaseSource) artefactURLs(version, artefact string) (url string, mirrorURLs []string) {
+	for _, mirror := range s.mirrors {
+		mirrorURLs = append(mirrorURLs, ArtefactURL(mirror, version, artefact))
+	}
+
+	return ArtefactURL(s.url, version, artefact), mirrorURLs
 }
 
 func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, ingressController string, installPath, imagesPath string) error {
@@ -58,16 +91,20 @@ func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni
 		return fmt.Errorf("gathering RKE2 image artefacts: %w", err)
 	}
 
-	if err = d.downloadArtefacts(artefacts, d.Rke2ReleaseURL, version, imagesPath); err != nil {
-		return fmt.Errorf("downloading RKE2 image artefacts: %w", err)
-	}
+	source := releaseSource{url: d.Rke2ReleaseURL, mirrors: d.Rke2Mirrors}
 
-	artefacts = rke2InstallerArtefacts(arch)
-	if err = d.downloadArtefacts(artefacts, d.Rke2ReleaseURL, version, installPath); err != nil {
-		return fmt.Errorf("downloading RKE2 install artefacts: %w", err)
+	// The checksum file is part of the install artefacts since the install script verifies the binary against it
+	sums, err := d.fetchChecksums(source, version, arch, installPath)
+	if err != nil {
+		return fmt.Errorf("downloading RKE2 checksums: %w", err)
 	}
 
-	return nil
+	installArtefacts := slices.DeleteFunc(rke2InstallerArtefacts(arch), func(artefact string) bool {
+		return artefact == ChecksumsArtefact(arch)
+	})
+
+	downloads := append(artefactDownloads(artefacts, imagesPath), artefactDownloads(installArtefacts, installPath)...)
+	return d.downloadArtefacts(downloads, source, version, sums)
 }
 
 func rke2InstallerArtefacts(arch image.Arch) []string {
@@ -75,7 +112,7 @@ func rke2InstallerArtefacts(arch image.Arch) []string {
 
 	return []string{
 		fmt.Sprintf(rke2Binary, artefactArch),
-		fmt.Sprintf(rke2Checksums, artefactArch),
+		ChecksumsArtefact(arch),
 	}
 }
 
@@ -116,17 +153,26 @@ func (d ArtefactDownloader) DownloadK3sArtefacts(arch image.Arch, version, insta
 		return fmt.Errorf("invalid k3s version: '%s'", version)
 	}
 
-	artefacts := k3sImageArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, d.K3sReleaseURL, version, imagesPath); err != nil {
-		return fmt.Errorf("downloading k3s image artefacts: %w", err)
+	// The checksum file is not shipped with the image since the install path may only contain the binary
+	checksumsDir, err := os.MkdirTemp("", "eib-k3s-checksums-")
+	if err != nil {
+		return fmt.Errorf("creating k3s checksums dir: %w", err)
 	}
+	defer func() {
+		if err = os.RemoveAll(checksumsDir); err != nil {
+			zap.S().Warnf("Removing k3s checksums dir '%s' failed: %v", checksumsDir, err)
+		}
+	}()
 
-	artefacts = k3sInstallerArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, d.K3sReleaseURL, version, installPath); err != nil {
-		return fmt.Errorf("downloading k3s install artefacts: %w", err)
+	source := releaseSource{url: d.K3sReleaseURL, mirrors: d.K3sMirrors}
+
+	sums, err := d.fetchChecksums(source, version, arch, checksumsDir)
+	if err != nil {
+		return fmt.Errorf("downloading k3s checksums: %w", err)
 	}
 
-	return nil
+	downloads := append(artefactDownloads(k3sImageArtefacts(arch), imagesPath), artefactDownloads(k3sInstallerArtefacts(arch), installPath)...)
+	return d.downloadArtefacts(downloads, source, version, sums)
 }
 
 func k3sInstallerArtefacts(arch image.Arch) []string {
@@ -150,33 +196,144 @@ func k3sImageArtefacts(arch image.Arch) []string {
 	}
 }
 
-func (d ArtefactDownloader) downloadArtefacts(artefacts []string, releaseURL, version, destinationPath string) error {
+// RKE2Artefacts returns the names of the image and installer artefacts required for an RKE2 installation.
+func RKE2Artefacts(arch image.Arch, cni string, multusEnabled bool, ingressController string) (images, installer []string, err error) {
+	images, err = rke2ImageArtefacts(cni, multusEnabled, ingressController, arch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return images, rke2InstallerArtefacts(arch), nil
+}
+
+// K3sArtefacts returns the names of the image and installer artefacts required for a K3s installation.
+func K3sArtefacts(arch image.Arch) (images, installer []string) {
+	return k3sImageArtefacts(arch), k3sInstallerArtefacts(arch)
+}
+
+// ArtefactURL returns the location of the artefact of the given Kubernetes release.
+func ArtefactURL(releaseURL, version, artefact string) string {
+	return fmt.Sprintf("%s/%s/%s", releaseURL, url.QueryEscape(version), url.QueryEscape(artefact))
+}
+
+// artefactDownload is an artefact of a release along with the directory it is stored in.
+type artefactDownload struct {
+	artefact        string
+	destinationPath string
+}
+
+func artefactDownloads(artefacts []string, destinationPath string) []artefactDownload {
+	var downloads []artefactDownload
 	for _, artefact := range artefacts {
-		url := fmt.Sprintf("%s/%s/%s", releaseURL, url.QueryEscape(version), url.QueryEscape(artefact))
-		path := filepath.Join(destinationPath, artefact)
-		cacheKey := cacheIdentifier(version, artefact)
+		downloads = append(downloads, artefactDownload{artefact: artefact, destinationPath: destinationPath})
+	}
 
-		copied, err := d.copyArtefactFromCache(cacheKey, path)
+	return downloads
+}
+
+// downloadArtefacts fetches the artefacts concurrently, verifying each of them against its checksum.
+func (d ArtefactDownloader) downloadArtefacts(downloads []artefactDownload, source releaseSource, version string, sums checksums) error {
+	// Checksums are looked up before any download starts, so that none is wasted on an unverifiable release
+	checksumsByArtefact := map[string]string{}
+	for _, download := range downloads {
+		checksum, err := sums.lookup(download.artefact)
 		if err != nil {
-			return fmt.Errorf("retrieving artefact '%s' from cache: %w", artefact, err)
+			return fmt.Errorf("verifying artefact '%s': %w", download.artefact, err)
 		}
 
-		if !copied {
-			if err = d.downloadArtefact(url, path, cacheKey); err != nil {
-				return fmt.Errorf("downloading artefact '%s': %w", artefact, err)
+		checksumsByArtefact[download.artefact] = checksum
+	}
+
+	scheduler := http.NewScheduler(d.Parallelism)
+
+	for _, download := range downloads {
+		scheduler.Go(func() error {
+			artefact := download.artefact
+			checksum := checksumsByArtefact[artefact]
+
+			url, mirrorURLs := source.artefactURLs(version, artefact)
+			path := filepath.Join(download.destinationPath, artefact)
+			cacheKey := ArtefactCacheIdentifier(version, artefact)
+
+			if err := fetchArtefact(d.Cache, url, mirrorURLs, path, cacheKey, checksum); err != nil {
+				return fmt.Errorf("fetching artefact '%s': %w", artefact, err)
 			}
+
+			if d.Report != nil {
+				d.Report.RecordVerifiedArtefact(artefact, url, "sha256:"+checksum)
+			}
+
+			return nil
+		})
+	}
+
+	return scheduler.Wait()
+}
+
+// fetchArtefact copies the artefact from the cache, downloading it first if necessary.
+// The cache entry is locked throughout, so that concurrent builds sharing the cache
+// wait for an in-flight download instead of duplicating it.
+//
+// Unless empty, the artefact is verified against the given SHA-256 checksum. Downloaded artefacts
+// which do not match it are not stored in the cache.
+//
+// The mirror URLs are tried in turn if downloading the artefact from the given URL fails.
+func fetchArtefact(c cache, url string, mirrorURLs []string, path, cacheKey, checksum string) error {
+	if c != nil {
+		unlock, err := c.Lock(cacheKey)
+		if err != nil {
+			return fmt.Errorf("locking cache entry: %w", err)
 		}
+		defer unlock()
+	}
+
+	copied, err := copyArtefactFromCache(c, cacheKey, path)
+	if err != nil {
+		return fmt.Errorf("retrieving artefact from cache: %w", err)
+	}
+
+	if copied {
+		if err = verifyArtefact(path, checksum); err != nil {
+			return fmt.Errorf("verifying cached artefact, remove it with 'eib cache remove %s': %w", cacheKey, err)
+		}
+
+		return nil
+	}
+
+	if err = downloadArtefact(c, url, mirrorURLs, path, cacheKey, checksum); err != nil {
+		return err
+	}
+
+	if err = verifyArtefact(path, checksum); err != nil {
+		return fmt.Errorf("verifying downloaded artefact: %w", err)
+	}
+
+	return nil
+}
+
+func verifyArtefact(path, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	actual, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+
+	if actual != checksum {
+		return checksumMismatch(checksum, actual)
 	}
 
 	return nil
 }
 
-func (d ArtefactDownloader) copyArtefactFromCache(cacheKey, destPath string) (bool, error) {
-	if d.Cache == nil {
+func copyArtefactFromCache(c cache, cacheKey, destPath string) (bool, error) {
+	if c == nil {
 		return false, nil
 	}
 
-	sourcePath, err := d.Cache.Get(cacheKey)
+	sourcePath, err := c.Get(cacheKey)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return false, nil
@@ -194,9 +351,9 @@ func (d ArtefactDownloader) copyArtefactFromCache(cacheKey, destPath string) (bo
 	return true, nil
 }
 
-func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
-	if d.Cache == nil {
-		if err := http.DownloadFile(context.Background(), url, path, nil); err != nil {
+func downloadArtefact(c cache, url string, mirrorURLs []string, path, cacheKey, checksum string) error {
+	if c == nil {
+		if err := http.DownloadFile(context.Background(), url, path, nil, mirrorURLs...); err != nil {
 			return fmt.Errorf("downloading artefact: %w", err)
 		}
 		return nil
@@ -211,7 +368,7 @@ func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
 			}
 		}()
 
-		if err := http.DownloadFile(ctx, url, path, writer); err != nil {
+		if err := http.DownloadFile(ctx, url, path, writer, mirrorURLs...); err != nil {
 			if closeErr := writer.CloseWithError(err); closeErr != nil {
 				zap.S().Warnf("Closing pipe writer with error failed unexpectedly: %v", closeErr)
 			}
@@ -222,7 +379,16 @@ func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
 	})
 
 	errGroup.Go(func() error {
-		if err := d.Cache.Put(cacheKey, reader); err != nil {
+		var cacheReader io.Reader = reader
+		if checksum != "" {
+			cacheReader = newVerifyingReader(reader, checksum)
+		}
+
+		if err := c.Put(cacheKey, url, cacheReader); err != nil {
+			// Unblocks the download, which would otherwise wait for the remaining data to be read
+			if closeErr := reader.CloseWithError(err); closeErr != nil {
+				zap.S().Warnf("Closing pipe reader with error failed unexpectedly: %v", closeErr)
+			}
 			return fmt.Errorf("caching artefact: %w", err)
 		}
 
@@ -232,6 +398,7 @@ func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
 	return errGroup.Wait()
 }
 
-func cacheIdentifier(version, artefact string) string {
+// ArtefactCacheIdentifier returns the identifier of the artefact of the given Kubernetes release in the cache.
+func ArtefactCacheIdentifier(version, artefact string) string {
 	return fmt.Sprintf("%s/%s", version, artefact)
 }
@@ -1,7 +1,18 @@
 package kubernetes
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -199,3 +210,207 @@ func TestK3sImageArtefacts(t *testing.T) {
 	armArtefacts := []string{"k3s-airgap-images-arm64.tar.zst"}
 	assert.Equal(t, armArtefacts, k3sImageArtefacts(image.ArchTypeARM))
 }
+
+// releaseServer serves every artefact with the same contents, along with a checksum file
+// listing the given checksums.
+func releaseServer(t *testing.T, sums map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "sha256sum-amd64.txt" {
+			_, _ = w.Write([]byte("artefact"))
+			return
+		}
+
+		for name, checksum := range sums {
+			_, _ = fmt.Fprintf(w, "%s  %s\n", checksum, name)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+type fakeReport struct {
+	mu       sync.Mutex
+	verified map[string]string
+}
+
+func (r *fakeReport) RecordVerifiedArtefact(name, _, digest string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.verified[name] = digest
+}
+
+func TestDownloadK3sArtefacts(t *testing.T) {
+	// Setup
+	server := releaseServer(t, map[string]string{
+		"k3s":                             artefactChecksum,
+		"k3s-airgap-images-amd64.tar.zst": artefactChecksum,
+	})
+
+	report := &fakeReport{verified: map[string]string{}}
+	downloader := ArtefactDownloader{
+		Cache:         &fakeCache{dir: t.TempDir(), entries: map[string]string{}},
+		Report:        report,
+		K3sReleaseURL: server.URL,
+	}
+
+	installPath := t.TempDir()
+	imagesPath := t.TempDir()
+
+	// Test
+	err := downloader.DownloadK3sArtefacts(image.ArchTypeX86, "v1.30.3+k3s1", installPath, imagesPath)
+	require.NoError(t, err)
+
+	// Verify
+	installed, err := os.ReadDir(installPath)
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+	assert.Equal(t, "k3s", installed[0].Name())
+	assert.FileExists(t, filepath.Join(imagesPath, "k3s-airgap-images-amd64.tar.zst"))
+
+	assert.Equal(t, map[string]string{
+		"k3s":                             "sha256:" + artefactChecksum,
+		"k3s-airgap-images-amd64.tar.zst": "sha256:" + artefactChecksum,
+	}, report.verified)
+}
+
+func TestDownloadRKE2Artefacts(t *testing.T) {
+	// Setup
+	server := releaseServer(t, map[string]string{
+		"rke2.linux-amd64.tar.gz":                artefactChecksum,
+		"rke2-images-core.linux-amd64.tar.zst":   artefactChecksum,
+		"rke2-images-canal.linux-amd64.tar.zst":  artefactChecksum,
+		"rke2-images-cilium.linux-amd64.tar.zst": strings.Repeat("0", 64),
+	})
+
+	downloader := ArtefactDownloader{
+		Cache:          &fakeCache{dir: t.TempDir(), entries: map[string]string{}},
+		Rke2ReleaseURL: server.URL,
+	}
+
+	installPath := t.TempDir()
+
+	// Test
+	err := downloader.DownloadRKE2Artefacts(image.ArchTypeX86, "v1.30.3+rke2r1", "canal", false, "", installPath, t.TempDir())
+	require.NoError(t, err)
+
+	// Verify
+	assert.FileExists(t, filepath.Join(installPath, "rke2.linux-amd64.tar.gz"))
+	assert.FileExists(t, filepath.Join(installPath, "sha256sum-amd64.txt"))
+}
+
+func TestDownloadRKE2Artefacts_ChecksumMismatch(t *testing.T) {
+	// Setup
+	server := releaseServer(t, map[string]string{
+		"rke2.linux-amd64.tar.gz":                artefactChecksum,
+		"rke2-images-core.linux-amd64.tar.zst":   artefactChecksum,
+		"rke2-images-cilium.linux-amd64.tar.zst": strings.Repeat("0", 64),
+	})
+
+	artefactCache := &fakeCache{dir: t.TempDir(), entries: map[string]string{}}
+	downloader := ArtefactDownloader{
+		Cache:          artefactCache,
+		Rke2ReleaseURL: server.URL,
+		// The core images are downloaded ahead of the mismatching CNI images
+		Parallelism: 1,
+	}
+
+	// Test
+	err := downloader.DownloadRKE2Artefacts(image.ArchTypeX86, "v1.30.3+rke2r1", "cilium", false, "", t.TempDir(), t.TempDir())
+
+	// Verify
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "fetching artefact 'rke2-images-cilium.linux-amd64.tar.zst'")
+
+	// Artefacts which do not match their checksum are not cached
+	assert.Contains(t, artefactCache.entries, "v1.30.3+rke2r1/rke2-images-core.linux-amd64.tar.zst")
+	assert.NotContains(t, artefactCache.entries, "v1.30.3+rke2r1/rke2-images-cilium.linux-amd64.tar.zst")
+}
+
+func TestDownloadRKE2Artefacts_MissingChecksum(t *testing.T) {
+	server := releaseServer(t, map[string]string{
+		"rke2.linux-amd64.tar.gz": artefactChecksum,
+	})
+
+	downloader := ArtefactDownloader{Rke2ReleaseURL: server.URL}
+
+	err := downloader.DownloadRKE2Artefacts(image.ArchTypeX86, "v1.30.3+rke2r1", "cilium", false, "", t.TempDir(), t.TempDir())
+	assert.ErrorContains(t, err, "verifying artefact 'rke2-images-core.linux-amd64.tar.zst': no checksum found")
+}
+
+func TestDownloadK3sArtefacts_CorruptCacheEntry(t *testing.T) {
+	// Setup
+	server := releaseServer(t, map[string]string{
+		"k3s":                             artefactChecksum,
+		"k3s-airgap-images-amd64.tar.zst": artefactChecksum,
+	})
+
+	artefactCache := &fakeCache{dir: t.TempDir(), entries: map[string]string{}}
+	require.NoError(t, artefactCache.Put("v1.30.3+k3s1/k3s", server.URL, strings.NewReader("tampered")))
+
+	downloader := ArtefactDownloader{
+		Cache:         artefactCache,
+		K3sReleaseURL: server.URL,
+	}
+
+	// Test
+	err := downloader.DownloadK3sArtefacts(image.ArchTypeX86, "v1.30.3+k3s1", t.TempDir(), t.TempDir())
+
+	// Verify
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "verifying cached artefact, remove it with 'eib cache remove v1.30.3+k3s1/k3s'")
+}
+
+// failingCache fails to store entries once the download is under way, leaving the rest of the contents unread.
+type failingCache struct {
+	fakeCache
+}
+
+func (c *failingCache) Put(_, _ string, reader io.Reader) error {
+	if _, err := reader.Read(make([]byte, 1)); err != nil {
+		return err
+	}
+
+	return errors.New("no space left on device")
+}
+
+func TestDownloadArtefact_CachingFails(t *testing.T) {
+	// Setup
+	server := releaseServer(t, map[string]string{})
+	artefactPath := filepath.Join(t.TempDir(), "k3s")
+
+	// Test
+	done := make(chan error)
+	go func() {
+		done <- downloadArtefact(&failingCache{}, server.URL+"/k3s", nil, artefactPath, "v1.30.3+k3s1/k3s", "")
+	}()
+
+	// Verify
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "caching artefact: no space left on device")
+	case <-time.After(10 * time.Second):
+		t.Fatal("download did not finish after caching failed")
+	}
+}
+
+func TestReleaseSourceArtefactURLs(t *testing.T) {
+	source := releaseSource{
+		url:     "https://github.com/rancher/rke2/releases/download/",
+		mirrors: []string{"https://mirror-1.example.com/rke2", "https://mirror-2.example.com/rke2/"},
+	}
+
+	url, mirrorURLs := source.artefactURLs("v1.30.3+rke2r1", "rke2.linux-amd64.tar.gz")
+
+	assert.Equal(t, "https://github.com/rancher/rke2/releases/download//v1.30.3%2Brke2r1/rke2.linux-amd64.tar.gz", url)
+	assert.Equal(t, []string{
+		"https://mirror-1.example.com/rke2/v1.30.3%2Brke2r1/rke2.linux-amd64.tar.gz",
+		"https://mirror-2.example.com/rke2//v1.30.3%2Brke2r1/rke2.linux-amd64.tar.gz",
+	}, mirrorURLs)
+
+	url, mirrorURLs = releaseSource{url: "https://github.com/k3s-io/k3s/releases/download/"}.artefactURLs("v1.30.3+k3s1", "k3s")
+	assert.Equal(t, "https://github.com/k3s-io/k3s/releases/download//v1.30.3%2Bk3s1/k3s", url)
+	assert.Empty(t, mirrorURLs)
+}
@@ -0,0 +1,114 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const releaseIndexFile = "%s-release-index.json"
+
+// ReleaseChannel is a release channel of RKE2 or K3s along with the latest version published to it.
+type ReleaseChannel struct {
+	ID     string `json:"id"`
+	Latest string `json:"latest"`
+}
+
+// ReleaseIndex lists the release channels of RKE2 or K3s in the format served by their channel servers,
+// e.g. https://update.rke2.io/v1-release/channels.
+type ReleaseIndex struct {
+	Data []ReleaseChannel `json:"data"`
+}
+
+// ParseVersionChannel splits a channel expression such as `rke2:stable` or `k3s:v1.30`
+// into the distribution and the channel. Returns false if the version is not a channel expression.
+func ParseVersionChannel(version string) (distro, channel string, ok bool) {
+	return strings.Cut(version, ":")
+}
+
+// ResolveVersionChannel resolves the channel expression to the latest version of the channel in the release index.
+//
+// The index is read from the given file if specified. Otherwise, it is fetched from the channels URL
+// of the distribution into the destination directory. Returns the resolved version along with the location of the index.
+func ResolveVersionChannel(expression, indexFile string, sources *image.ArtifactSources, destinationPath string) (version, index string, err error) {
+	distro, channel, ok := ParseVersionChannel(expression)
+	if !ok || channel == "" {
+		return "", "", fmt.Errorf("invalid channel expression '%s'", expression)
+	}
+
+	var channelsURL string
+
+	switch distro {
+	case image.KubernetesDistroRKE2:
+		channelsURL = sources.Kubernetes.Rke2.ChannelsURL
+	case image.KubernetesDistroK3S:
+		channelsURL = sources.Kubernetes.K3s.ChannelsURL
+	default:
+		return "", "", fmt.Errorf("unsupported kubernetes distribution '%s'", distro)
+	}
+
+	index = indexFile
+	if index == "" {
+		index = channelsURL
+		indexFile = filepath.Join(destinationPath, fmt.Sprintf(releaseIndexFile, distro))
+
+		if err = http.DownloadFile(context.Background(), channelsURL, indexFile, nil); err != nil {
+			return "", "", fmt.Errorf("fetching release index: %w", err)
+		}
+	}
+
+	releases, err := parseReleaseIndex(indexFile)
+	if err != nil {
+		return "", "", err
+	}
+
+	version, err = releases.latest(channel)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving '%s' against release index '%s': %w", expression, index, err)
+	}
+
+	if !strings.Contains(version, distro) {
+		return "", "", fmt.Errorf("release index '%s' lists '%s' for channel '%s', which is not a %s release", index, version, channel, distro)
+	}
+
+	return version, index, nil
+}
+
+func parseReleaseIndex(path string) (*ReleaseIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading release index: %w", err)
+	}
+
+	var index ReleaseIndex
+	if err = json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing release index '%s': %w", path, err)
+	}
+
+	return &index, nil
+}
+
+// latest returns the latest version published to the given channel.
+func (i *ReleaseIndex) latest(channel string) (string, error) {
+	var channels []string
+
+	for _, c := range i.Data {
+		if c.ID == channel {
+			if c.Latest == "" {
+				return "", fmt.Errorf("channel '%s' has no releases", channel)
+			}
+
+			return c.Latest, nil
+		}
+
+		channels = append(channels, c.ID)
+	}
+
+	return "", fmt.Errorf("channel '%s' not found, available channels: %s", channel, strings.Join(channels, ", "))
+}
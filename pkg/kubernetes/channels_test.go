@@ -0,0 +1,150 @@
+package kubernetes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const releaseIndex = `{
+  "type": "collection",
+  "data": [
+    {"id": "stable", "name": "stable", "latest": "v1.30.5+rke2r1"},
+    {"id": "latest", "name": "latest", "latest": "v1.31.1+rke2r1"},
+    {"id": "v1.30", "name": "v1.30", "latest": "v1.30.5+rke2r1"},
+    {"id": "v1.26", "name": "v1.26", "latest": ""}
+  ]
+}`
+
+func TestParseVersionChannel(t *testing.T) {
+	tests := map[string]struct {
+		version         string
+		expectedDistro  string
+		expectedChannel string
+		expectedOK      bool
+	}{
+		"Version": {
+			version: "v1.30.3+rke2r1",
+		},
+		"Channel": {
+			version:         "rke2:stable",
+			expectedDistro:  "rke2",
+			expectedChannel: "stable",
+			expectedOK:      true,
+		},
+		"Minor channel": {
+			version:         "k3s:v1.30",
+			expectedDistro:  "k3s",
+			expectedChannel: "v1.30",
+			expectedOK:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			distro, channel, ok := ParseVersionChannel(test.version)
+			assert.Equal(t, test.expectedOK, ok)
+			if ok {
+				assert.Equal(t, test.expectedDistro, distro)
+				assert.Equal(t, test.expectedChannel, channel)
+			}
+		})
+	}
+}
+
+func TestResolveVersionChannel_ChannelsURL(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(releaseIndex))
+	}))
+	defer server.Close()
+
+	sources := &image.ArtifactSources{}
+	sources.Kubernetes.Rke2.ChannelsURL = server.URL
+
+	dir := t.TempDir()
+
+	// Test
+	version, index, err := ResolveVersionChannel("rke2:v1.30", "", sources, dir)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, "v1.30.5+rke2r1", version)
+	assert.Equal(t, server.URL, index)
+	assert.FileExists(t, filepath.Join(dir, "rke2-release-index.json"))
+}
+
+func TestResolveVersionChannel_IndexFile(t *testing.T) {
+	indexFile := filepath.Join(t.TempDir(), "channels.json")
+	require.NoError(t, os.WriteFile(indexFile, []byte(releaseIndex), 0o600))
+
+	// The channels URL must not be used when the index is provided locally
+	sources := &image.ArtifactSources{}
+	sources.Kubernetes.Rke2.ChannelsURL = "http://localhost:0/channels"
+
+	tests := map[string]struct {
+		expression      string
+		expectedVersion string
+		expectedError   string
+	}{
+		"Stable": {
+			expression:      "rke2:stable",
+			expectedVersion: "v1.30.5+rke2r1",
+		},
+		"Latest": {
+			expression:      "rke2:latest",
+			expectedVersion: "v1.31.1+rke2r1",
+		},
+		"Unknown channel": {
+			expression:    "rke2:v1.99",
+			expectedError: "channel 'v1.99' not found, available channels: stable, latest, v1.30, v1.26",
+		},
+		"Empty channel": {
+			expression:    "rke2:v1.26",
+			expectedError: "channel 'v1.26' has no releases",
+		},
+		"Other distribution": {
+			expression:    "k3s:stable",
+			expectedError: "lists 'v1.30.5+rke2r1' for channel 'stable', which is not a k3s release",
+		},
+		"Unsupported distribution": {
+			expression:    "rancher:stable",
+			expectedError: "unsupported kubernetes distribution 'rancher'",
+		},
+		"Invalid expression": {
+			expression:    "rke2:",
+			expectedError: "invalid channel expression 'rke2:'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, index, err := ResolveVersionChannel(test.expression, indexFile, sources, t.TempDir())
+
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedVersion, version)
+			assert.Equal(t, indexFile, index)
+		})
+	}
+}
+
+func TestResolveVersionChannel_InvalidIndexFile(t *testing.T) {
+	indexFile := filepath.Join(t.TempDir(), "channels.json")
+	require.NoError(t, os.WriteFile(indexFile, []byte("<html></html>"), 0o600))
+
+	_, _, err := ResolveVersionChannel("rke2:stable", indexFile, &image.ArtifactSources{}, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing release index '"+indexFile+"'")
+}
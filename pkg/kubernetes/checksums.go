@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// ErrChecksumMismatch is returned when an artefact does not match the checksum published with its release.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+var checksumRegexp = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// checksums maps the names of the artefacts of a release to their SHA-256 checksums in hex.
+type checksums map[string]string
+
+// ChecksumsArtefact returns the name of the checksum file published with the releases of both RKE2 and K3s.
+func ChecksumsArtefact(arch image.Arch) string {
+	return fmt.Sprintf(checksumsFile, arch.Short())
+}
+
+// fetchChecksums retrieves the checksum file of the given release into the destination directory and parses it.
+func (d ArtefactDownloader) fetchChecksums(source releaseSource, version string, arch image.Arch, destinationPath string) (checksums, error) {
+	name := ChecksumsArtefact(arch)
+	path := filepath.Join(destinationPath, name)
+	url, mirrorURLs := source.artefactURLs(version, name)
+
+	if err := fetchArtefact(d.Cache, url, mirrorURLs, path, ArtefactCacheIdentifier(version, name), ""); err != nil {
+		return nil, fmt.Errorf("fetching checksum file '%s': %w", name, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading checksum file '%s': %w", name, err)
+	}
+
+	sums, err := parseChecksums(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing checksum file '%s': %w", name, err)
+	}
+
+	return sums, nil
+}
+
+// parseChecksums parses a checksum file in the format produced by sha256sum.
+func parseChecksums(data []byte) (checksums, error) {
+	sums := checksums{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		checksum, name, found := strings.Cut(line, " ")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*")
+
+		if !found || name == "" || !checksumRegexp.MatchString(checksum) {
+			return nil, fmt.Errorf("invalid line: %s", line)
+		}
+
+		sums[name] = checksum
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sums, nil
+}
+
+// lookup returns the checksum of the given artefact.
+func (c checksums) lookup(artefact string) (string, error) {
+	checksum, ok := c[artefact]
+	if !ok {
+		return "", fmt.Errorf("no checksum found for artefact '%s'", artefact)
+	}
+
+	return checksum, nil
+}
+
+// fileChecksum computes the SHA-256 checksum of the file under the given path in hex.
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("reading file: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func checksumMismatch(expected, actual string) error {
+	return fmt.Errorf("%w: expected sha256:%s, found sha256:%s", ErrChecksumMismatch, expected, actual)
+}
+
+// verifyingReader fails once the underlying reader is exhausted unless the contents match the expected checksum,
+// which prevents artefacts which do not match their release checksum from being stored in the cache.
+type verifyingReader struct {
+	reader   io.Reader
+	hash     hash.Hash
+	expected string
+}
+
+func newVerifyingReader(reader io.Reader, expected string) *verifyingReader {
+	return &verifyingReader{
+		reader:   reader,
+		hash:     sha256.New(),
+		expected: expected,
+	}
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.hash.Write(p[:n])
+
+	if errors.Is(err, io.EOF) {
+		if actual := hex.EncodeToString(r.hash.Sum(nil)); actual != r.expected {
+			return n, checksumMismatch(r.expected, actual)
+		}
+	}
+
+	return n, err
+}
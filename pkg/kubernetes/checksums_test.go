@@ -0,0 +1,85 @@
+package kubernetes
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// SHA-256 checksum of "artefact"
+	artefactChecksum = "4edfbd28d0483e702f24bd5247ed646f7e5752aec9d6b1461d837ccab6d27f7b"
+)
+
+func TestParseChecksums(t *testing.T) {
+	tests := map[string]struct {
+		data              string
+		expectedChecksums checksums
+		expectedError     string
+	}{
+		"Text mode": {
+			data: "0000000000000000000000000000000000000000000000000000000000000000  rke2.linux-amd64.tar.gz\n" +
+				"1111111111111111111111111111111111111111111111111111111111111111  rke2-images-core.linux-amd64.tar.zst\n",
+			expectedChecksums: checksums{
+				"rke2.linux-amd64.tar.gz":              "0000000000000000000000000000000000000000000000000000000000000000",
+				"rke2-images-core.linux-amd64.tar.zst": "1111111111111111111111111111111111111111111111111111111111111111",
+			},
+		},
+		"Binary mode and blank lines": {
+			data: "\n2222222222222222222222222222222222222222222222222222222222222222 *k3s\n\n",
+			expectedChecksums: checksums{
+				"k3s": "2222222222222222222222222222222222222222222222222222222222222222",
+			},
+		},
+		"Empty": {
+			data:              "",
+			expectedChecksums: checksums{},
+		},
+		"Invalid checksum": {
+			data:          "abc  k3s\n",
+			expectedError: "invalid line: abc  k3s",
+		},
+		"Missing name": {
+			data:          "2222222222222222222222222222222222222222222222222222222222222222\n",
+			expectedError: "invalid line: 2222222222222222222222222222222222222222222222222222222222222222",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sums, err := parseChecksums([]byte(test.data))
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				assert.Nil(t, sums)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedChecksums, sums)
+			}
+		})
+	}
+}
+
+func TestChecksumsLookup(t *testing.T) {
+	sums := checksums{"k3s": artefactChecksum}
+
+	checksum, err := sums.lookup("k3s")
+	require.NoError(t, err)
+	assert.Equal(t, artefactChecksum, checksum)
+
+	_, err = sums.lookup("k3s-arm64")
+	require.EqualError(t, err, "no checksum found for artefact 'k3s-arm64'")
+}
+
+func TestVerifyingReader(t *testing.T) {
+	data, err := io.ReadAll(newVerifyingReader(strings.NewReader("artefact"), artefactChecksum))
+	require.NoError(t, err)
+	assert.Equal(t, "artefact", string(data))
+
+	_, err = io.ReadAll(newVerifyingReader(strings.NewReader("tampered"), artefactChecksum))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "expected sha256:"+artefactChecksum)
+}
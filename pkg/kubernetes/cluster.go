@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"net/netip"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -29,8 +31,14 @@ const (
 	clusterInitKey  = "cluster-init"
 	selinuxKey      = "selinux"
 	ingressKey      = "ingress-controller"
+	nodeLabelKey    = "node-label"
+	nodeTaintKey    = "node-taint"
 )
 
+// ReservedNodeConfigKeys are the configuration options which must be the same on all nodes of a cluster,
+// and therefore cannot be overridden for individual nodes.
+var ReservedNodeConfigKeys = []string{tokenKey, serverKey, clusterInitKey, "agent-token"}
+
 type Cluster struct {
 	// InitialiserName is the hostname of the initialiser node.
 	// Defaults to the first configured server if not explicitly selected.
@@ -42,6 +50,9 @@ type Cluster struct {
 	ServerConfig map[string]any
 	// AgentConfig contains the agent configurations in multi node clusters.
 	AgentConfig map[string]any
+	// NodeConfigs contains the configurations of the nodes in multi node clusters which define labels,
+	// taints or configuration overrides, keyed by hostname. All other nodes use the configuration of their type.
+	NodeConfigs map[string]map[string]any
 }
 
 func NewCluster(kubernetes *image.Kubernetes, configPath string) (*Cluster, error) {
@@ -53,6 +64,12 @@ func NewCluster(kubernetes *image.Kubernetes, configPath string) (*Cluster, erro
 
 	if len(kubernetes.Nodes) < 2 {
 		setSingleNodeConfigDefaults(kubernetes, serverConfig)
+
+		// The node of a single node cluster, if defined, is the only one using the server configuration
+		if len(kubernetes.Nodes) == 1 && hasNodeConfig(&kubernetes.Nodes[0]) {
+			serverConfig = nodeConfig(serverConfig, &kubernetes.Nodes[0])
+		}
+
 		return &Cluster{ServerConfig: serverConfig}, nil
 	}
 
@@ -104,14 +121,82 @@ func NewCluster(kubernetes *image.Kubernetes, configPath string) (*Cluster, erro
 		initialiserConfig[clusterInitKey] = true
 	}
 
+	nodeConfigs := map[string]map[string]any{}
+	for i := range kubernetes.Nodes {
+		node := &kubernetes.Nodes[i]
+		if !hasNodeConfig(node) {
+			continue
+		}
+
+		switch {
+		case node.Hostname == initialiser:
+			nodeConfigs[node.Hostname] = nodeConfig(initialiserConfig, node)
+		case node.Type == image.KubernetesNodeTypeServer:
+			nodeConfigs[node.Hostname] = nodeConfig(serverConfig, node)
+		default:
+			nodeConfigs[node.Hostname] = nodeConfig(agentConfig, node)
+		}
+	}
+
 	return &Cluster{
 		InitialiserName:   initialiser,
 		InitialiserConfig: initialiserConfig,
 		ServerConfig:      serverConfig,
 		AgentConfig:       agentConfig,
+		NodeConfigs:       nodeConfigs,
 	}, nil
 }
 
+func hasNodeConfig(node *image.Node) bool {
+	return len(node.Labels) > 0 || len(node.Taints) > 0 || len(node.Config) > 0
+}
+
+// nodeConfig derives the configuration of the given node from the configuration of its type.
+// The configuration overrides of the node replace the shared options, while its labels and taints
+// are appended to the shared ones.
+func nodeConfig(typeConfig map[string]any, node *image.Node) map[string]any {
+	config := maps.Clone(typeConfig)
+	maps.Copy(config, node.Config)
+
+	var labels []string
+	for _, key := range slices.Sorted(maps.Keys(node.Labels)) {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, node.Labels[key]))
+	}
+
+	appendConfigValues(config, nodeLabelKey, labels)
+	appendConfigValues(config, nodeTaintKey, node.Taints)
+
+	return config
+}
+
+// appendConfigValues appends the values to the list option under the given key. The option is replaced
+// rather than modified in place, since its value may be shared with other configurations.
+func appendConfigValues(config map[string]any, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	var list []string
+
+	switch v := config[key].(type) {
+	case nil:
+	case string:
+		for _, value := range strings.Split(v, ",") {
+			list = append(list, strings.TrimSpace(value))
+		}
+	case []string:
+		list = append(list, v...)
+	case []any:
+		for _, value := range v {
+			list = append(list, fmt.Sprint(value))
+		}
+	default:
+		zap.S().Warnf("Ignoring invalid '%s' value: %v", key, v)
+	}
+
+	config[key] = append(list, values...)
+}
+
 func ParseKubernetesConfig(configFile string) (map[string]any, error) {
 	config := map[string]any{}
 
@@ -158,18 +243,16 @@ func setSingleNodeConfigDefaults(kubernetes *image.Kubernetes, config map[string
 	}
 	if kubernetes.Network.APIVIP4 != "" {
 		appendClusterTLSSAN(config, kubernetes.Network.APIVIP4)
-
-		if strings.Contains(kubernetes.Version, image.KubernetesDistroK3S) {
-			appendDisabledServices(config, "servicelb")
-		}
 	}
 
 	if kubernetes.Network.APIVIP6 != "" {
 		appendClusterTLSSAN(config, kubernetes.Network.APIVIP6)
+	}
 
-		if strings.Contains(kubernetes.Version, image.KubernetesDistroK3S) && kubernetes.Network.APIVIP4 == "" {
-			appendDisabledServices(config, "servicelb")
-		}
+	// The service load balancer of K3s conflicts with MetalLB serving the API VIPs
+	if strings.Contains(kubernetes.Version, image.KubernetesDistroK3S) &&
+		kubernetes.Network.APIVIPProvider() == image.VIPProviderMetalLB {
+		appendDisabledServices(config, "servicelb")
 	}
 
 	if kubernetes.Network.APIHost != "" {
@@ -189,7 +272,11 @@ func setMultiNodeConfigDefaults(kubernetes *image.Kubernetes, config map[string]
 		setClusterCNI(config)
 	} else {
 		setClusterAPIAddress(config, ip4, ip6, k3sServerPort, prioritizeIPv6)
-		appendDisabledServices(config, "servicelb")
+
+		// The service load balancer of K3s conflicts with MetalLB serving the API VIPs
+		if kubernetes.Network.APIVIPProvider() == image.VIPProviderMetalLB {
+			appendDisabledServices(config, "servicelb")
+		}
 	}
 
 	setClusterToken(config)
@@ -62,6 +62,23 @@ func TestNewCluster_SingleNodeK3s_MissingConfig(t *testing.T) {
 	assert.Nil(t, cluster.AgentConfig)
 }
 
+func TestNewCluster_SingleNodeK3s_KubeVIP(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.30.3+k3s1",
+		Network: image.Network{
+			APIVIP4:     "192.168.122.50",
+			VIPProvider: image.VIPProviderKubeVIP,
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	require.NotNil(t, cluster.ServerConfig)
+	assert.Equal(t, []string{"192.168.122.50"}, cluster.ServerConfig["tls-san"])
+	assert.Nil(t, cluster.ServerConfig["disable"])
+}
+
 func TestNewCluster_SingleNode_ExistingConfig(t *testing.T) {
 	kubernetes := &image.Kubernetes{
 		Network: image.Network{
@@ -182,6 +199,66 @@ func TestNewCluster_MultiNodeRKE2_MissingConfig(t *testing.T) {
 	assert.Nil(t, cluster.AgentConfig["debug"])
 }
 
+func TestNewCluster_MultiNodeK3s_MissingConfig(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.30.3+k3s1",
+		Network: image.Network{
+			APIVIP4: "192.168.122.50",
+		},
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+			},
+			{
+				Hostname: "node2.suse.com",
+				Type:     "server",
+			},
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	require.NotNil(t, cluster.InitialiserConfig)
+	assert.Equal(t, []string{"servicelb"}, cluster.InitialiserConfig["disable"])
+	assert.Nil(t, cluster.InitialiserConfig["cni"])
+
+	require.NotNil(t, cluster.ServerConfig)
+	assert.Equal(t, []string{"servicelb"}, cluster.ServerConfig["disable"])
+	assert.Equal(t, "https://192.168.122.50:6443", cluster.ServerConfig["server"])
+}
+
+func TestNewCluster_MultiNodeK3s_KubeVIP(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.30.3+k3s1",
+		Network: image.Network{
+			APIVIP4:     "192.168.122.50",
+			VIPProvider: image.VIPProviderKubeVIP,
+		},
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+			},
+			{
+				Hostname: "node2.suse.com",
+				Type:     "server",
+			},
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	require.NotNil(t, cluster.InitialiserConfig)
+	assert.Nil(t, cluster.InitialiserConfig["disable"])
+
+	require.NotNil(t, cluster.ServerConfig)
+	assert.Nil(t, cluster.ServerConfig["disable"])
+	assert.Equal(t, "https://192.168.122.50:6443", cluster.ServerConfig["server"])
+}
+
 func TestNewCluster_MultiNodeRKE2_ExistingConfig(t *testing.T) {
 	kubernetes := &image.Kubernetes{
 		Version: "v1.30.3+rke2r1",
@@ -400,6 +477,122 @@ func TestNewCluster_MultiNode_MissingInitialiser(t *testing.T) {
 	assert.Nil(t, cluster)
 }
 
+func TestNewCluster_MultiNode_NodeConfigs(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.30.3+rke2r1",
+		Network: image.Network{
+			APIVIP4: "192.168.122.50",
+		},
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+				Config: map[string]any{
+					"node-ip": "192.168.122.51",
+				},
+			},
+			{
+				Hostname: "node2.suse.com",
+				Type:     "server",
+				Taints:   []string{"CriticalAddonsOnly=true:NoExecute"},
+			},
+			{
+				Hostname: "node3.suse.com",
+				Type:     "agent",
+				Labels: map[string]string{
+					"node-role.kubernetes.io/ingress": "true",
+					"accelerator":                     "nvidia",
+				},
+				Config: map[string]any{
+					"node-label": []any{"zone=a"},
+				},
+			},
+			{
+				Hostname: "node4.suse.com",
+				Type:     "agent",
+			},
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	require.Len(t, cluster.NodeConfigs, 3)
+	assert.NotContains(t, cluster.NodeConfigs, "node4.suse.com")
+
+	initialiser := cluster.NodeConfigs["node1.suse.com"]
+	assert.Equal(t, "192.168.122.51", initialiser["node-ip"])
+	assert.Equal(t, cluster.InitialiserConfig["token"], initialiser["token"])
+	assert.Nil(t, initialiser["server"])
+
+	server := cluster.NodeConfigs["node2.suse.com"]
+	assert.Equal(t, []string{"CriticalAddonsOnly=true:NoExecute"}, server["node-taint"])
+	assert.Equal(t, "https://192.168.122.50:9345", server["server"])
+	assert.Nil(t, server["node-ip"])
+
+	agent := cluster.NodeConfigs["node3.suse.com"]
+	assert.Equal(t, []string{"zone=a", "accelerator=nvidia", "node-role.kubernetes.io/ingress=true"}, agent["node-label"])
+	assert.Equal(t, "https://192.168.122.50:9345", agent["server"])
+	assert.Nil(t, agent["tls-san"])
+
+	// The configurations shared by the node types remain unchanged
+	assert.Nil(t, cluster.InitialiserConfig["node-ip"])
+	assert.Nil(t, cluster.ServerConfig["node-taint"])
+	assert.Nil(t, cluster.AgentConfig["node-label"])
+}
+
+func TestNewCluster_SingleNode_NodeConfig(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.30.3+k3s1",
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+				Labels: map[string]string{
+					"site": "edge-01",
+				},
+			},
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"site=edge-01"}, cluster.ServerConfig["node-label"])
+	assert.Nil(t, cluster.NodeConfigs)
+}
+
+func TestAppendConfigValues(t *testing.T) {
+	tests := map[string]struct {
+		config   map[string]any
+		expected []string
+	}{
+		"Missing": {
+			config:   map[string]any{},
+			expected: []string{"b=2"},
+		},
+		"String": {
+			config:   map[string]any{"node-label": "a=1, c=3"},
+			expected: []string{"a=1", "c=3", "b=2"},
+		},
+		"List": {
+			config:   map[string]any{"node-label": []any{"a=1"}},
+			expected: []string{"a=1", "b=2"},
+		},
+		"Invalid": {
+			config:   map[string]any{"node-label": 5},
+			expected: []string{"b=2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			appendConfigValues(test.config, "node-label", []string{"b=2"})
+			assert.Equal(t, test.expected, test.config["node-label"])
+		})
+	}
+}
+
 func TestIdentifyInitialiserNode(t *testing.T) {
 	tests := []struct {
 		name         string
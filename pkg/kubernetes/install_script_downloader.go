@@ -1,13 +1,11 @@
 package kubernetes
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
-	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -16,28 +14,42 @@ const (
 	k3sInstallScriptURL  = "https://get.k3s.io"
 )
 
-type ScriptDownloader struct{}
-
-func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath string) (string, error) {
-	var scriptURL string
+type ScriptDownloader struct {
+	Cache cache
+}
 
+// InstallScript returns the name and the location of the install script of the given distribution.
+func InstallScript(distribution string) (name, scriptURL string, err error) {
 	switch distribution {
 	case image.KubernetesDistroRKE2:
 		scriptURL = rke2InstallScriptURL
 	case image.KubernetesDistroK3S:
 		scriptURL = k3sInstallScriptURL
 	default:
-		return "", fmt.Errorf("unsupported distribution: %s", distribution)
+		return "", "", fmt.Errorf("unsupported distribution: %s", distribution)
+	}
+
+	return fmt.Sprintf("%s_installer.sh", distribution), scriptURL, nil
+}
+
+// InstallScriptCacheIdentifier returns the identifier of the install script with the given name in the cache.
+func InstallScriptCacheIdentifier(installer string) string {
+	return fmt.Sprintf("scripts/%s", installer)
+}
+
+func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath string) (string, error) {
+	installer, scriptURL, err := InstallScript(distribution)
+	if err != nil {
+		return "", err
 	}
 
-	installer := fmt.Sprintf("%s_installer.sh", distribution)
 	destinationPath = filepath.Join(destinationPath, installer)
 
-	if err := http.DownloadFile(context.Background(), scriptURL, destinationPath, nil); err != nil {
+	if err = fetchArtefact(d.Cache, scriptURL, nil, destinationPath, InstallScriptCacheIdentifier(installer), ""); err != nil {
 		return "", fmt.Errorf("downloading script: %w", err)
 	}
 
-	if err := os.Chmod(destinationPath, fileio.ExecutablePerms); err != nil {
+	if err = os.Chmod(destinationPath, fileio.ExecutablePerms); err != nil {
 		return "", fmt.Errorf("modifying script permissions: %w", err)
 	}
 
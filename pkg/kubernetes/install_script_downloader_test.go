@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// fakeCache stores the entries in files under a temporary directory.
+type fakeCache struct {
+	dir     string
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func (c *fakeCache) Lock(string) (func(), error) {
+	return func() {}, nil
+}
+
+func (c *fakeCache) Get(identifier string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path, ok := c.entries[identifier]
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+
+	return path, nil
+}
+
+func (c *fakeCache) Put(identifier, _ string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(c.dir, strings.ReplaceAll(identifier, "/", "_"))
+
+	c.mu.Lock()
+	c.entries[identifier] = path
+	c.mu.Unlock()
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+func TestDownloadInstallScript_Offline(t *testing.T) {
+	// Setup
+	scriptCache := &fakeCache{dir: t.TempDir(), entries: map[string]string{}}
+	require.NoError(t, scriptCache.Put("scripts/rke2_installer.sh", rke2InstallScriptURL, strings.NewReader("#!/bin/sh")))
+
+	http.SetOffline(true)
+	defer http.SetOffline(false)
+
+	downloader := ScriptDownloader{Cache: scriptCache}
+	destDir := t.TempDir()
+
+	// Test
+	installer, err := downloader.DownloadInstallScript(image.KubernetesDistroRKE2, destDir)
+	require.NoError(t, err)
+
+	_, err = downloader.DownloadInstallScript(image.KubernetesDistroK3S, destDir)
+
+	// Verify
+	assert.ErrorIs(t, err, http.ErrOffline)
+
+	assert.Equal(t, "rke2_installer.sh", installer)
+
+	info, err := os.Stat(filepath.Join(destDir, installer))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o744), info.Mode().Perm())
+}
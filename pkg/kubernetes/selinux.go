@@ -1,12 +1,10 @@
 package kubernetes
 
 import (
-	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
 
-	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -44,9 +42,17 @@ func SELinuxRepository(version string, sources *image.ArtifactSources) (image.Ad
 	}, nil
 }
 
-func DownloadSELinuxRPMsSigningKey(gpgKeysDir string) error {
-	const rancherSigningKeyURL = "https://rpm.rancher.io/public.key"
-	var signingKeyPath = filepath.Join(gpgKeysDir, "rancher-public.key")
+const (
+	SELinuxRPMsSigningKeyURL  = "https://rpm.rancher.io/public.key"
+	SELinuxRPMsSigningKeyFile = "rancher-public.key"
 
-	return http.DownloadFile(context.Background(), rancherSigningKeyURL, signingKeyPath, nil)
+	// SELinuxRPMsSigningKeyCacheIdentifier is the identifier of the signing key in the cache.
+	SELinuxRPMsSigningKeyCacheIdentifier = "keys/" + SELinuxRPMsSigningKeyFile
+)
+
+// DownloadSELinuxRPMsSigningKey downloads the key the SELinux RPMs are signed with into the given directory.
+func (d ArtefactDownloader) DownloadSELinuxRPMsSigningKey(gpgKeysDir string) error {
+	signingKeyPath := filepath.Join(gpgKeysDir, SELinuxRPMsSigningKeyFile)
+
+	return fetchArtefact(d.Cache, SELinuxRPMsSigningKeyURL, nil, signingKeyPath, SELinuxRPMsSigningKeyCacheIdentifier, "")
 }
@@ -57,6 +57,8 @@ func AuditComponentFailed(component string) {
 }
 
 func doAudit(message string, logFunc func(args ...any)) {
+	message = Redact(message)
+
 	fmt.Println(message)
 	if logFunc != nil {
 		logFunc(message)
@@ -13,7 +13,9 @@ func ConfigureGlobalLogger(logFilename string) {
 	logConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	logConfig.OutputPaths = []string{logFilename}
 
-	logger := zap.Must(logConfig.Build())
+	logger := zap.Must(logConfig.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return redactingCore{core}
+	})))
 
 	// Set our configured logger to be accessed globally by zap.L()
 	zap.ReplaceGlobals(logger)
@@ -0,0 +1,191 @@
+package log
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const redactedValue = "[REDACTED]"
+
+var secrets = struct {
+	sync.RWMutex
+	values []string
+}{}
+
+// RegisterSecrets marks the given values as sensitive. Any occurrences of them are
+// redacted from the audit output, the global logger and the redacting writers.
+func RegisterSecrets(values ...string) {
+	secrets.Lock()
+	defer secrets.Unlock()
+
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		secrets.values = append(secrets.values, v)
+	}
+}
+
+// ResetSecrets forgets all registered secrets. Intended for tests registering secrets of their own.
+func ResetSecrets() {
+	secrets.Lock()
+	defer secrets.Unlock()
+
+	secrets.values = nil
+}
+
+// Redact replaces all occurrences of the registered secrets in the given string.
+func Redact(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+
+	for _, v := range secrets.values {
+		s = strings.ReplaceAll(s, v, redactedValue)
+	}
+
+	return s
+}
+
+// redactableLength returns the length of the prefix of the given string which can be redacted on its own.
+// The remainder may contain the beginning of a secret completed by subsequent output, hence it is held back
+// along with any secret straddling the end of the prefix.
+func redactableLength(s string) int {
+	secrets.RLock()
+	defer secrets.RUnlock()
+
+	if len(secrets.values) == 0 {
+		return len(s)
+	}
+
+	var longest int
+	for _, v := range secrets.values {
+		longest = max(longest, len(v))
+	}
+
+	length := len(s) - (longest - 1)
+	if length <= 0 {
+		return 0
+	}
+
+	for shortened := true; shortened; {
+		shortened = false
+
+		for _, v := range secrets.values {
+			for offset := 0; offset < length; {
+				i := strings.Index(s[offset:], v)
+				if i == -1 || offset+i >= length {
+					break
+				}
+
+				start := offset + i
+				if start+len(v) > length {
+					length = start
+					shortened = true
+					break
+				}
+
+				offset = start + 1
+			}
+		}
+	}
+
+	return length
+}
+
+type redactingWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+	// pending holds back the output which may contain part of a secret completed by a subsequent write.
+	pending []byte
+}
+
+// NewRedactingWriter wraps the given writer, redacting the registered secrets from everything written to it.
+// Intended for capturing the output of executed commands in log files.
+//
+// Secrets split across multiple writes are redacted as well, hence the end of the output is held back
+// until it cannot be part of a secret anymore. Closing the writer flushes it without closing the wrapped writer.
+func NewRedactingWriter(w io.Writer) io.WriteCloser {
+	return &redactingWriter{w: w}
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending = append(r.pending, p...)
+
+	length := redactableLength(string(r.pending))
+	if length == 0 {
+		return len(p), nil
+	}
+
+	if _, err := io.WriteString(r.w, Redact(string(r.pending[:length]))); err != nil {
+		return 0, err
+	}
+
+	r.pending = append(r.pending[:0], r.pending[length:]...)
+
+	return len(p), nil
+}
+
+func (r *redactingWriter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	if _, err := io.WriteString(r.w, Redact(string(r.pending))); err != nil {
+		return err
+	}
+
+	r.pending = nil
+
+	return nil
+}
+
+// redactingCore redacts the registered secrets from the entries of the wrapped logger core.
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactingCore{c.Core.With(redactFields(fields))}
+}
+
+func (c redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = Redact(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, 0, len(fields))
+
+	for _, f := range fields {
+		switch f.Type {
+		case zapcore.StringType:
+			f.String = Redact(f.String)
+		case zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok {
+				f = zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: Redact(err.Error())}
+			}
+		}
+
+		redacted = append(redacted, f)
+	}
+
+	return redacted
+}
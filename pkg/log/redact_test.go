@@ -0,0 +1,127 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRedact(t *testing.T) {
+	// Setup
+	RegisterSecrets("redact-alpha", "", "redact-beta")
+	t.Cleanup(ResetSecrets)
+
+	// Test
+	redacted := Redact("--password redact-alpha --token redact-beta --username redact")
+
+	// Verify
+	assert.Equal(t, "--password [REDACTED] --token [REDACTED] --username redact", redacted)
+}
+
+func TestRedactingWriter(t *testing.T) {
+	// Setup
+	RegisterSecrets("writer-secret")
+	t.Cleanup(ResetSecrets)
+
+	var buf bytes.Buffer
+	w := NewRedactingWriter(&buf)
+
+	// Test
+	n, err := w.Write([]byte("login succeeded using writer-secret\n"))
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, len("login succeeded using writer-secret\n"), n)
+
+	require.NoError(t, w.Close())
+	assert.Equal(t, "login succeeded using [REDACTED]\n", buf.String())
+}
+
+func TestRedactingWriter_SplitSecret(t *testing.T) {
+	// Setup
+	RegisterSecrets("split-secret", "other-secret-value")
+	t.Cleanup(ResetSecrets)
+
+	var buf bytes.Buffer
+	w := NewRedactingWriter(&buf)
+
+	// Test
+	for _, chunk := range []string{"login using spl", "it-sec", "ret succeeded\n", "token split-", "s", "ecret"} {
+		_, err := w.Write([]byte(chunk))
+		require.NoError(t, err)
+	}
+
+	// Verify
+	assert.NotContains(t, buf.String(), "split-secret")
+	assert.NotContains(t, buf.String(), "spl")
+
+	require.NoError(t, w.Close())
+	assert.Equal(t, "login using [REDACTED] succeeded\ntoken [REDACTED]", buf.String())
+}
+
+func TestRedactableLength_NoSecrets(t *testing.T) {
+	assert.Equal(t, len("password secret"), redactableLength("password secret"))
+}
+
+func TestRedactableLength(t *testing.T) {
+	RegisterSecrets("secret")
+	t.Cleanup(ResetSecrets)
+
+	tests := map[string]struct {
+		output         string
+		expectedLength int
+	}{
+		"Shorter than a secret": {
+			output:         "abc",
+			expectedLength: 0,
+		},
+		"Complete secret": {
+			output:         "password secret and more",
+			expectedLength: len("password secret and more") - 5,
+		},
+		"Secret straddling the held back output": {
+			output:         "password secret!",
+			expectedLength: len("password "),
+		},
+		"Partial secret": {
+			output:         "password sec",
+			expectedLength: len("password sec") - 5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedLength, redactableLength(test.output))
+		})
+	}
+}
+
+func TestConfigureGlobalLogger_Redacts(t *testing.T) {
+	// Setup
+	RegisterSecrets("logger-secret")
+	t.Cleanup(ResetSecrets)
+
+	logFilename := filepath.Join(t.TempDir(), "eib-build.log")
+	ConfigureGlobalLogger(logFilename)
+	defer zap.ReplaceGlobals(zap.NewNop())
+
+	// Test
+	zap.S().Infof("Logging in using password %s", "logger-secret")
+	zap.L().Error("Login failed", zap.Error(errors.New("invalid password logger-secret")), zap.String("password", "logger-secret"))
+	zap.L().With(zap.String("password", "logger-secret")).Info("Logged in")
+	require.NoError(t, zap.L().Sync())
+
+	// Verify
+	contents, err := os.ReadFile(logFilename)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(contents), "Logging in using password [REDACTED]")
+	assert.Contains(t, string(contents), "invalid password [REDACTED]")
+	assert.NotContains(t, string(contents), "logger-secret")
+}
@@ -0,0 +1,123 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"go.uber.org/zap"
+)
+
+const (
+	KindKubernetesArtefact = "kubernetes artefact"
+	KindInstallScript      = "install script"
+	KindSigningKey         = "signing key"
+	KindManifest           = "manifest"
+	KindHelmChart          = "helm chart"
+	KindContainerImage     = "container image"
+	KindPackages           = "packages"
+
+	// UnknownSize indicates that the size of an artefact cannot be estimated without downloading it.
+	UnknownSize int64 = -1
+
+	sizeRequestTimeout = 10 * time.Second
+)
+
+// Artefact describes a single artefact which a build would download.
+type Artefact struct {
+	Kind string
+	// Source is the location the artefact would be retrieved from.
+	Source string
+	// Destination is the path the artefact would be stored under, if known.
+	Destination string
+	// Size is the estimated size of the artefact in bytes or UnknownSize.
+	Size int64
+	// Identifier is the identifier the artefact is stored under in the cache.
+	// Empty for artefacts which are not cached or whose identifier is only known at build time.
+	Identifier string
+}
+
+// SizeEstimator estimates the size of the file under the given URL.
+type SizeEstimator func(url string) (int64, error)
+
+// Recorder collects the artefacts which the planning stubs are requested to retrieve.
+type Recorder struct {
+	estimateSize SizeEstimator
+
+	mu         sync.Mutex
+	artefacts  []Artefact
+	unresolved []string
+}
+
+// NewRecorder creates a recorder estimating the size of remote artefacts using the given estimator.
+// If no estimator is provided, the sizes are queried from the remote servers.
+func NewRecorder(estimateSize SizeEstimator) *Recorder {
+	if estimateSize == nil {
+		estimateSize = remoteSize
+	}
+
+	return &Recorder{estimateSize: estimateSize}
+}
+
+func remoteSize(url string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sizeRequestTimeout)
+	defer cancel()
+
+	return http.ContentLength(ctx, url)
+}
+
+func (r *Recorder) record(kind, source, destination, identifier string) {
+	size := UnknownSize
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		s, err := r.estimateSize(source)
+		if err != nil {
+			zap.S().Warnf("Estimating size of '%s' failed: %v", source, err)
+		} else {
+			size = s
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.artefacts = append(r.artefacts, Artefact{
+		Kind:        kind,
+		Source:      source,
+		Destination: destination,
+		Size:        size,
+		Identifier:  identifier,
+	})
+}
+
+// Artefacts returns the recorded artefacts in the order they were requested in.
+func (r *Recorder) Artefacts() []Artefact {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Artefact(nil), r.artefacts...)
+}
+
+func (r *Recorder) recordUnresolved(entry string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.unresolved = append(r.unresolved, entry)
+}
+
+// Unresolved returns the descriptions of the build outputs which cannot be planned
+// without retrieving the manifests and Helm charts, in the order they were recorded in.
+func (r *Recorder) Unresolved() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.unresolved...)
+}
+
+// RecordSigningKey records the download of the SELinux RPMs signing key.
+func (r *Recorder) RecordSigningKey(url, destination string) {
+	r.record(KindSigningKey, url, destination, kubernetes.SELinuxRPMsSigningKeyCacheIdentifier)
+}
@@ -0,0 +1,260 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+)
+
+const unresolvedDigest = "unresolved"
+
+// writePlaceholder creates an empty file in place of an artefact which has not been retrieved,
+// so that components inspecting their artefacts directories behave as they would during a build.
+func writePlaceholder(path string, perms os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, nil, perms); err != nil {
+		return fmt.Errorf("writing placeholder %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// KubernetesDownloader records the Kubernetes install scripts and artefacts instead of downloading them.
+type KubernetesDownloader struct {
+	Recorder       *Recorder
+	RKE2ReleaseURL string
+	K3sReleaseURL  string
+}
+
+func (d KubernetesDownloader) DownloadInstallScript(distribution, destinationPath string) (string, error) {
+	installer, scriptURL, err := kubernetes.InstallScript(distribution)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(destinationPath, installer)
+	d.Recorder.record(KindInstallScript, scriptURL, path, kubernetes.InstallScriptCacheIdentifier(installer))
+
+	if err = writePlaceholder(path, fileio.ExecutablePerms); err != nil {
+		return "", err
+	}
+
+	return installer, nil
+}
+
+func (d KubernetesDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, ingressController string, installPath, imagesPath string) error {
+	if !strings.Contains(version, image.KubernetesDistroRKE2) {
+		return fmt.Errorf("invalid RKE2 version: '%s'", version)
+	}
+
+	images, installer, err := kubernetes.RKE2Artefacts(arch, cni, multusEnabled, ingressController)
+	if err != nil {
+		return fmt.Errorf("gathering RKE2 artefacts: %w", err)
+	}
+
+	if err = d.recordArtefacts(images, d.RKE2ReleaseURL, version, imagesPath); err != nil {
+		return err
+	}
+
+	return d.recordArtefacts(installer, d.RKE2ReleaseURL, version, installPath)
+}
+
+func (d KubernetesDownloader) DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error {
+	if !strings.Contains(version, image.KubernetesDistroK3S) {
+		return fmt.Errorf("invalid k3s version: '%s'", version)
+	}
+
+	images, installer := kubernetes.K3sArtefacts(arch)
+
+	// The checksum file is only used for verifying the artefacts and is not part of the image
+	checksums := kubernetes.ChecksumsArtefact(arch)
+	url := kubernetes.ArtefactURL(d.K3sReleaseURL, version, checksums)
+	d.Recorder.record(KindKubernetesArtefact, url, "", kubernetes.ArtefactCacheIdentifier(version, checksums))
+
+	if err := d.recordArtefacts(images, d.K3sReleaseURL, version, imagesPath); err != nil {
+		return err
+	}
+
+	return d.recordArtefacts(installer, d.K3sReleaseURL, version, installPath)
+}
+
+func (d KubernetesDownloader) recordArtefacts(artefacts []string, releaseURL, version, destinationPath string) error {
+	for _, artefact := range artefacts {
+		path := filepath.Join(destinationPath, artefact)
+		url := kubernetes.ArtefactURL(releaseURL, version, artefact)
+		d.Recorder.record(KindKubernetesArtefact, url, path, kubernetes.ArtefactCacheIdentifier(version, artefact))
+
+		if err := writePlaceholder(path, fileio.NonExecutablePerms); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RPMResolver records the packages which would be resolved instead of resolving them.
+type RPMResolver struct {
+	Recorder *Recorder
+}
+
+func (r RPMResolver) Resolve(packages *image.Packages, localRPMConfig *image.LocalRPMConfig, outputDir string) (string, []string, error) {
+	const rpmRepoName = "rpm-repo"
+
+	repoPath := filepath.Join(outputDir, rpmRepoName)
+	if err := os.MkdirAll(repoPath, os.ModePerm); err != nil {
+		return "", nil, fmt.Errorf("creating rpm repo dir: %w", err)
+	}
+
+	pkgList := append([]string{}, packages.PKGList...)
+
+	if localRPMConfig != nil {
+		entries, err := os.ReadDir(localRPMConfig.RPMPath)
+		if err != nil {
+			return "", nil, fmt.Errorf("reading side-loaded RPMs: %w", err)
+		}
+
+		for _, entry := range entries {
+			if filepath.Ext(entry.Name()) == ".rpm" {
+				pkgList = append(pkgList, strings.TrimSuffix(entry.Name(), ".rpm"))
+			}
+		}
+	}
+
+	var sources []string
+	for _, repo := range packages.AdditionalRepos {
+		sources = append(sources, repo.URL)
+	}
+	if packages.RegCode != "" {
+		sources = append(sources, "SUSE Customer Center")
+	}
+
+	source := strings.Join(packages.PKGList, ", ")
+	if len(sources) != 0 {
+		source = fmt.Sprintf("%s (from %s)", source, strings.Join(sources, ", "))
+	}
+
+	if len(packages.PKGList) != 0 {
+		r.Recorder.record(KindPackages, source, repoPath, "")
+	}
+
+	return repoPath, pkgList, nil
+}
+
+// RPMRepoCreator skips the creation of the RPM repository metadata.
+type RPMRepoCreator struct{}
+
+func (RPMRepoCreator) Create(string) error {
+	return nil
+}
+
+// Registry records the manifests and Helm charts which would be pulled instead of pulling them.
+// Only the container images explicitly listed in the definition are reported since the images
+// referenced in manifests and charts are not known until those are retrieved. Likewise, no HelmChart
+// manifests are generated, those are recorded as unresolved instead.
+type Registry struct {
+	manifestsDir    string
+	containerImages []string
+}
+
+func NewRegistry(ctx *image.Context, localManifestsDir string, recorder *Recorder) *Registry {
+	for _, manifestURL := range ctx.ImageDefinition.Kubernetes.Manifests.URLs {
+		recorder.record(KindManifest, manifestURL, "", registry.ManifestCacheIdentifier(manifestURL))
+	}
+
+	helm := &ctx.ImageDefinition.Kubernetes.Helm
+	for _, chart := range helm.Charts {
+		source := fmt.Sprintf("%s/%s", chart.RepositoryName, chart.Name)
+		if chart.Version != "" {
+			source = fmt.Sprintf("%s:%s", source, chart.Version)
+		}
+
+		var identifier, repositoryURL string
+		for _, repo := range helm.Repositories {
+			if repo.Name == chart.RepositoryName {
+				identifier = registry.ChartCacheIdentifier(repo.URL, chart.Name, chart.Version)
+				repositoryURL = repo.URL
+				break
+			}
+		}
+
+		recorder.record(KindHelmChart, source, "", identifier)
+
+		manifestName := registry.NewHelmCRD(&chart, "", "", repositoryURL).Metadata.Name
+		recorder.recordUnresolved(fmt.Sprintf("HelmChart manifest '%s.yaml' of chart '%s'", manifestName, source))
+
+		if chart.CRDHandling == image.CRDHandlingSeparate {
+			recorder.recordUnresolved(fmt.Sprintf("CRDs manifest '%s-crds.yaml' of chart '%s'", manifestName, source))
+		}
+	}
+
+	r := &Registry{}
+
+	if _, err := os.Stat(localManifestsDir); err == nil {
+		r.manifestsDir = localManifestsDir
+	}
+
+	if r.manifestsDir != "" || len(ctx.ImageDefinition.Kubernetes.Manifests.URLs) != 0 || len(helm.Charts) != 0 {
+		recorder.recordUnresolved("container images referenced by manifests and Helm charts")
+	}
+
+	for _, img := range ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages {
+		r.containerImages = append(r.containerImages, img.Name)
+	}
+
+	return r
+}
+
+func (r *Registry) ManifestsPath() string {
+	return r.manifestsDir
+}
+
+func (r *Registry) ContainerImages() ([]string, error) {
+	return r.containerImages, nil
+}
+
+// HelmCharts returns no charts since the HelmChart manifests embed the chart archives,
+// which are not pulled. Those manifests are recorded as unresolved instead.
+func (r *Registry) HelmCharts() ([]*registry.HelmCRD, error) {
+	return nil, nil
+}
+
+// RegistryStore records the container images which would be stored in the embedded artifact registry.
+type RegistryStore struct {
+	Recorder *Recorder
+}
+
+func (RegistryStore) Login(image.Registry, io.Writer) error {
+	return nil
+}
+
+func (s RegistryStore) AddImage(containerImage, _ string, _ io.Writer) error {
+	identifier, _ := combustion.ContainerImageCacheIdentifier(containerImage)
+	s.Recorder.record(KindContainerImage, containerImage, "", identifier)
+	return nil
+}
+
+func (RegistryStore) Save(imageTarDest string, _ io.Writer) error {
+	return writePlaceholder(imageTarDest, fileio.NonExecutablePerms)
+}
+
+func (RegistryStore) InstallBinary(destinationPath string) error {
+	return writePlaceholder(destinationPath, fileio.ExecutablePerms)
+}
+
+// ImageDigester skips querying the digests of container images.
+type ImageDigester struct{}
+
+func (ImageDigester) ImageDigest(string, string) (string, error) {
+	return unresolvedDigest, nil
+}
@@ -0,0 +1,215 @@
+package plan
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestKubernetesDownloader_K3s(t *testing.T) {
+	// Setup
+	recorder := NewRecorder(func(string) (int64, error) {
+		return 2048, nil
+	})
+
+	downloader := KubernetesDownloader{
+		Recorder:      recorder,
+		K3sReleaseURL: "https://k3s.suse.com/releases",
+	}
+
+	installPath := t.TempDir()
+	imagesPath := t.TempDir()
+
+	// Test
+	err := downloader.DownloadK3sArtefacts(image.ArchTypeX86, "v1.30.3+k3s1", installPath, imagesPath)
+
+	// Verify
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(installPath, "k3s"))
+	assert.FileExists(t, filepath.Join(imagesPath, "k3s-airgap-images-amd64.tar.zst"))
+
+	assert.Equal(t, []Artefact{
+		{
+			Kind:       KindKubernetesArtefact,
+			Source:     "https://k3s.suse.com/releases/v1.30.3%2Bk3s1/sha256sum-amd64.txt",
+			Size:       2048,
+			Identifier: "v1.30.3+k3s1/sha256sum-amd64.txt",
+		},
+		{
+			Kind:        KindKubernetesArtefact,
+			Source:      "https://k3s.suse.com/releases/v1.30.3%2Bk3s1/k3s-airgap-images-amd64.tar.zst",
+			Destination: filepath.Join(imagesPath, "k3s-airgap-images-amd64.tar.zst"),
+			Size:        2048,
+			Identifier:  "v1.30.3+k3s1/k3s-airgap-images-amd64.tar.zst",
+		},
+		{
+			Kind:        KindKubernetesArtefact,
+			Source:      "https://k3s.suse.com/releases/v1.30.3%2Bk3s1/k3s",
+			Destination: filepath.Join(installPath, "k3s"),
+			Size:        2048,
+			Identifier:  "v1.30.3+k3s1/k3s",
+		},
+	}, recorder.Artefacts())
+}
+
+func TestKubernetesDownloader_InvalidVersion(t *testing.T) {
+	downloader := KubernetesDownloader{Recorder: NewRecorder(nil)}
+
+	err := downloader.DownloadRKE2Artefacts(image.ArchTypeX86, "v1.30.3+k3s1", "canal", false, "", t.TempDir(), t.TempDir())
+	require.EqualError(t, err, "invalid RKE2 version: 'v1.30.3+k3s1'")
+}
+
+func TestRecorder_UnknownSize(t *testing.T) {
+	// Setup
+	recorder := NewRecorder(func(string) (int64, error) {
+		return 0, errors.New("unreachable")
+	})
+
+	// Test
+	recorder.record(KindManifest, "https://manifests.suse.com/manifest.yaml", "", "")
+	recorder.record(KindContainerImage, "nginx:1.25", "", "")
+
+	// Verify
+	artefacts := recorder.Artefacts()
+	require.Len(t, artefacts, 2)
+	assert.Equal(t, UnknownSize, artefacts[0].Size)
+	assert.Equal(t, UnknownSize, artefacts[1].Size)
+}
+
+func TestRPMResolver(t *testing.T) {
+	// Setup
+	recorder := NewRecorder(nil)
+	resolver := RPMResolver{Recorder: recorder}
+
+	rpmsDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rpmsDir, "local.rpm"), nil, 0o600))
+
+	packages := &image.Packages{
+		PKGList: []string{"vim", "htop"},
+		AdditionalRepos: []image.AddRepo{
+			{URL: "https://repo.suse.com"},
+		},
+	}
+
+	outputDir := t.TempDir()
+
+	// Test
+	repoPath, pkgList, err := resolver.Resolve(packages, &image.LocalRPMConfig{RPMPath: rpmsDir}, outputDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(outputDir, "rpm-repo"), repoPath)
+	assert.DirExists(t, repoPath)
+	assert.Equal(t, []string{"vim", "htop", "local"}, pkgList)
+
+	assert.Equal(t, []Artefact{
+		{
+			Kind:        KindPackages,
+			Source:      "vim, htop (from https://repo.suse.com)",
+			Destination: repoPath,
+			Size:        UnknownSize,
+		},
+	}, recorder.Artefacts())
+}
+
+func TestRegistry(t *testing.T) {
+	// Setup
+	recorder := NewRecorder(func(string) (int64, error) {
+		return 512, nil
+	})
+
+	ctx := &image.Context{
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{
+				Manifests: image.Manifests{
+					URLs: []string{"https://manifests.suse.com/manifest.yaml"},
+				},
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{Name: "metallb", RepositoryName: "suse-edge", Version: "0.14.3", CRDHandling: image.CRDHandlingSeparate},
+						{Name: "rancher-chart", RepositoryName: "suse-edge", Version: "2.9.1", ReleaseName: "rancher"},
+					},
+					Repositories: []image.HelmRepository{
+						{Name: "suse-edge", URL: "https://suse-edge.github.io/charts"},
+					},
+				},
+			},
+			EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{
+					{Name: "nginx:1.25"},
+				},
+			},
+		},
+	}
+
+	// Test
+	registry := NewRegistry(ctx, "does-not-exist", recorder)
+
+	// Verify
+	assert.Empty(t, registry.ManifestsPath())
+
+	images, err := registry.ContainerImages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nginx:1.25"}, images)
+
+	charts, err := registry.HelmCharts()
+	require.NoError(t, err)
+	assert.Empty(t, charts)
+
+	assert.Equal(t, []Artefact{
+		{
+			Kind:       KindManifest,
+			Source:     "https://manifests.suse.com/manifest.yaml",
+			Size:       512,
+			Identifier: "manifests/manifests.suse.com/manifest.yaml",
+		},
+		{
+			Kind:       KindHelmChart,
+			Source:     "suse-edge/metallb:0.14.3",
+			Size:       UnknownSize,
+			Identifier: "charts/suse-edge.github.io/charts/metallb-0.14.3.tgz",
+		},
+		{
+			Kind:       KindHelmChart,
+			Source:     "suse-edge/rancher-chart:2.9.1",
+			Size:       UnknownSize,
+			Identifier: "charts/suse-edge.github.io/charts/rancher-chart-2.9.1.tgz",
+		},
+	}, recorder.Artefacts())
+
+	assert.Equal(t, []string{
+		"HelmChart manifest 'metallb.yaml' of chart 'suse-edge/metallb:0.14.3'",
+		"CRDs manifest 'metallb-crds.yaml' of chart 'suse-edge/metallb:0.14.3'",
+		"HelmChart manifest 'rancher.yaml' of chart 'suse-edge/rancher-chart:2.9.1'",
+		"container images referenced by manifests and Helm charts",
+	}, recorder.Unresolved())
+}
+
+func TestRegistry_ContainerImagesOnly(t *testing.T) {
+	// Setup
+	recorder := NewRecorder(nil)
+
+	ctx := &image.Context{
+		ImageDefinition: &image.Definition{
+			EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{
+					{Name: "nginx:1.25"},
+				},
+			},
+		},
+	}
+
+	// Test
+	NewRegistry(ctx, "does-not-exist", recorder)
+
+	// Verify
+	assert.Empty(t, recorder.Artefacts())
+	assert.Empty(t, recorder.Unresolved())
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"go.uber.org/zap"
 )
@@ -22,7 +23,7 @@ const (
 
 // creates a listening service that answers API calls for Podman (https://docs.podman.io/en/v4.8.3/markdown/podman-system-service.1.html)
 // only way to start the service from within a container - https://github.com/containers/podman/tree/v4.8.3/pkg/bindings#starting-the-service-manually
-func setupAPIListener(out string) error {
+func setupAPIListener(out string, secretEnv []string) error {
 	log.AuditInfo("Setting up Podman API listener...")
 
 	logFile, err := os.Create(filepath.Join(out, podmanListenerLogFile))
@@ -32,7 +33,7 @@ func setupAPIListener(out string) error {
 
 	defer logFile.Close()
 
-	cmd := preparePodmanCommand(logFile)
+	cmd := preparePodmanCommand(logFile, secretEnv)
 	err = cmd.Start()
 	if err != nil {
 		return fmt.Errorf("error running podman system service: %w", err)
@@ -41,9 +42,20 @@ func setupAPIListener(out string) error {
 	return waitForPodmanSock()
 }
 
-func preparePodmanCommand(out io.Writer) *exec.Cmd {
+func preparePodmanCommand(out io.Writer, secretEnv []string) *exec.Cmd {
 	args := strings.Split(podmanArgsBase, " ")
 	cmd := exec.Command(podmanExec, args...)
+	// Image pulls are performed by the service, which must trust the configured certificate authorities
+	cmd.Env = http.CommandEnv()
+
+	// Image build secrets are read from the environment of the service
+	if len(secretEnv) != 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+
+		cmd.Env = append(cmd.Env, secretEnv...)
+	}
 	cmd.Stdout = out
 	cmd.Stderr = out
 
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/containers/buildah/define"
@@ -19,6 +20,7 @@ import (
 	"github.com/containers/podman/v4/pkg/domain/entities"
 	"github.com/containers/podman/v4/pkg/specgen"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"go.uber.org/zap"
 )
 
@@ -27,19 +29,35 @@ const (
 	dockerfile         = "Dockerfile"
 	podmanDirName      = "podman"
 	podmanBuildLogFile = "podman-image-build.log"
+	secretEnvPrefix    = "EIB_BUILD_SECRET_"
 )
 
 type Podman struct {
 	context context.Context
 	out     string
+	// IDs of the secrets available to image builds
+	secretIDs []string
 }
 
 // New setups a podman listening service and returns a connected podman client.
 //
 // Parameters:
 //   - out - location for podman to output any logs created as a result of podman commands
-func New(out string) (*Podman, error) {
-	if err := setupAPIListener(out); err != nil {
+//   - secrets - values made available to the RUN instructions of image builds as secrets, keyed by their IDs
+func New(out string, secrets map[string]string) (*Podman, error) {
+	// The secrets are handed over to the service via its environment, so that they are never stored on disk
+	var secretIDs, secretEnv []string
+	for id, value := range secrets {
+		if value == "" {
+			continue
+		}
+
+		secretIDs = append(secretIDs, id)
+		secretEnv = append(secretEnv, fmt.Sprintf("%s=%s", secretEnvName(id), value))
+	}
+	slices.Sort(secretIDs)
+
+	if err := setupAPIListener(out, secretEnv); err != nil {
 		return nil, fmt.Errorf("creating new podman instance: %w", err)
 	}
 
@@ -49,11 +67,16 @@ func New(out string) (*Podman, error) {
 	}
 
 	return &Podman{
-		context: conn,
-		out:     out,
+		context:   conn,
+		out:       out,
+		secretIDs: secretIDs,
 	}, nil
 }
 
+func secretEnvName(id string) string {
+	return secretEnvPrefix + strings.ToUpper(strings.ReplaceAll(id, "-", "_"))
+}
+
 // Import imports a tarball and saves it as a filesystem image
 //
 // Parameters:
@@ -74,7 +97,7 @@ func (p *Podman) Import(tarball, ref string) error {
 }
 
 // Build looks for a 'Dockerfile' in the given context and build a podman image
-// from it.
+// from it. The secrets provided when creating the client can be mounted by its RUN instructions.
 func (p *Podman) Build(imageContext, imageName string) error {
 	zap.S().Infof("Building image %s...", imageName)
 
@@ -84,18 +107,29 @@ func (p *Podman) Build(imageContext, imageName string) error {
 	}
 	defer logFile.Close()
 
+	output := log.NewRedactingWriter(logFile)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Failed to flush podman build log file properly: %s", err)
+		}
+	}()
+
 	eOpts := entities.BuildOptions{
 		BuildOptions: define.BuildOptions{
 			ContextDirectory: imageContext,
 			Output:           imageName,
-			Out:              logFile,
-			Err:              logFile,
+			Out:              output,
+			Err:              output,
 			CommonBuildOpts: &define.CommonBuildOptions{
 				HTTPProxy: true,
 			},
 		},
 	}
 
+	for _, id := range p.secretIDs {
+		eOpts.CommonBuildOpts.Secrets = append(eOpts.CommonBuildOpts.Secrets, fmt.Sprintf("id=%s,env=%s", id, secretEnvName(id)))
+	}
+
 	_, err = images.Build(p.context, []string{dockerfile}, eOpts)
 	if err != nil {
 		return fmt.Errorf("building image from context %s: %w", imageContext, err)
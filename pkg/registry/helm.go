@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+const customResourceDefinitionKind = "CustomResourceDefinition"
+
 func (r *Registry) HelmCharts() ([]*HelmCRD, error) {
 	var crds []*HelmCRD
 
@@ -30,6 +33,13 @@ func (r *Registry) HelmCharts() ([]*HelmCRD, error) {
 		}
 
 		crd := NewHelmCRD(&chart.HelmChart, chartContent, string(valuesContent), chart.repositoryURL)
+
+		if chart.CRDHandling == image.CRDHandlingSeparate {
+			if crd.CRDs, err = r.chartDirectoryCRDs(chart); err != nil {
+				return nil, fmt.Errorf("extracting CRDs: %w", err)
+			}
+		}
+
 		crds = append(crds, crd)
 	}
 
@@ -37,40 +47,119 @@ func (r *Registry) HelmCharts() ([]*HelmCRD, error) {
 }
 
 func (r *Registry) helmChartImages() ([]string, error) {
-	var containerImages []string
+	refs, err := r.helmChartImageReferences()
+	if err != nil {
+		return nil, err
+	}
 
-	for _, chart := range r.helmCharts {
-		var valuesPath string
-		if chart.ValuesFile != "" {
-			valuesPath = filepath.Join(r.helmValuesDir, chart.ValuesFile)
-		}
+	return refs.names(), nil
+}
+
+func (r *Registry) helmChartImageReferences() (imageReferences, error) {
+	refs := imageReferences{}
 
-		images, err := r.getChartContainerImages(&chart.HelmChart, chart.localPath, valuesPath, r.kubeVersion)
+	for _, chart := range r.helmCharts {
+		chartRefs, err := r.getChartContainerImages(chart)
 		if err != nil {
 			return nil, err
 		}
 
-		containerImages = append(containerImages, images...)
+		refs.merge(chartRefs)
 	}
 
-	return containerImages, nil
+	return refs, nil
 }
 
-func (r *Registry) getChartContainerImages(chart *image.HelmChart, chartPath, valuesPath, kubeVersion string) ([]string, error) {
-	chartResources, err := r.helmClient.Template(chart.Name, chartPath, chart.Version, valuesPath, kubeVersion, chart.TargetNamespace, chart.APIVersions)
+func (r *Registry) getChartContainerImages(chart *helmChart) (imageReferences, error) {
+	chartResources, err := r.templateChart(chart)
 	if err != nil {
 		return nil, fmt.Errorf("templating chart: %w", err)
 	}
 
-	containerImages := map[string]bool{}
+	var imagePathRules []image.ImageRule
+	for _, imagePath := range chart.ImagePaths {
+		imagePathRules = append(imagePathRules, image.ImageRule{Kind: imagePath.Kind, Selector: imagePath.Path})
+	}
+
+	chartRules, err := compileImageRules(imagePathRules, "image path")
+	if err != nil {
+		return nil, err
+	}
+
+	rules := append(slices.Clone(r.imageRules), chartRules...)
+	source := fmt.Sprintf("chart '%s'", chart.Name)
+
+	refs := imageReferences{}
 	for _, resource := range chartResources {
-		extractManifestImages(resource, containerImages)
+		extractResourceImages(resource, rules, source, refs)
+	}
+
+	return refs, nil
+}
+
+// chartDirectoryCRDs returns the CRDs in the `crds` directories of the chart and its subcharts.
+// CRDs rendered from the templates remain part of the Helm release, hence the ones found in a render
+// skipping the `crds` directories are excluded.
+func (r *Registry) chartDirectoryCRDs(chart *helmChart) ([]map[string]any, error) {
+	resources, err := r.templateChart(chart)
+	if err != nil {
+		return nil, fmt.Errorf("templating chart: %w", err)
 	}
 
-	var images []string
-	for i := range containerImages {
-		images = append(images, i)
+	templatedResources, err := r.helmClient.Template(chart.Name, chart.localPath, chart.Version, r.chartValuesPath(chart),
+		r.kubeVersion, chart.TargetNamespace, chart.APIVersions, false)
+	if err != nil {
+		return nil, fmt.Errorf("templating chart without CRDs: %w", err)
+	}
+
+	templatedCRDs := map[string]bool{}
+	for _, resource := range templatedResources {
+		if name, ok := crdName(resource); ok {
+			templatedCRDs[name] = true
+		}
+	}
+
+	var crds []map[string]any
+	for _, resource := range resources {
+		if name, ok := crdName(resource); ok && !templatedCRDs[name] {
+			crds = append(crds, resource)
+		}
+	}
+
+	return crds, nil
+}
+
+func crdName(resource map[string]any) (string, bool) {
+	if kind, _ := resource["kind"].(string); kind != customResourceDefinitionKind {
+		return "", false
+	}
+
+	metadata, _ := resource["metadata"].(map[string]any)
+	name, _ := metadata["name"].(string)
+
+	return name, true
+}
+
+func (r *Registry) chartValuesPath(chart *helmChart) string {
+	if chart.ValuesFile == "" {
+		return ""
+	}
+
+	return filepath.Join(r.helmValuesDir, chart.ValuesFile)
+}
+
+// templateChart renders the resources of the chart once and reuses them afterwards.
+func (r *Registry) templateChart(chart *helmChart) ([]map[string]any, error) {
+	if chart.resources != nil {
+		return chart.resources, nil
+	}
+
+	resources, err := r.helmClient.Template(chart.Name, chart.localPath, chart.Version, r.chartValuesPath(chart), r.kubeVersion,
+		chart.TargetNamespace, chart.APIVersions, chart.IncludeCRDs())
+	if err != nil {
+		return nil, err
 	}
 
-	return images, nil
+	chart.resources = resources
+	return resources, nil
 }
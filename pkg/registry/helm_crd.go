@@ -29,6 +29,9 @@ type HelmCRD struct {
 		CreateNamespace bool   `yaml:"createNamespace,omitempty"`
 		BackOffLimit    int    `yaml:"backOffLimit"`
 	} `yaml:"spec"`
+
+	// CRDs of the chart which are installed separately ahead of the chart.
+	CRDs []map[string]any `yaml:"-"`
 }
 
 func NewHelmCRD(chart *image.HelmChart, chartContent, valuesContent, repositoryURL string) *HelmCRD {
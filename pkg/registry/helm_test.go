@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -16,7 +17,7 @@ type mockHelmClient struct {
 	addRepoFunc       func(repository *image.HelmRepository) error
 	registryLoginFunc func(repository *image.HelmRepository) error
 	pullFunc          func(chart string, repository *image.HelmRepository, version, destDir string) (string, error)
-	templateFunc      func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string) ([]map[string]any, error)
+	templateFunc      func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error)
 }
 
 func (m mockHelmClient) AddRepo(repository *image.HelmRepository) error {
@@ -40,9 +41,9 @@ func (m mockHelmClient) Pull(chart string, repository *image.HelmRepository, ver
 	panic("not implemented")
 }
 
-func (m mockHelmClient) Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string) ([]map[string]any, error) {
+func (m mockHelmClient) Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
 	if m.templateFunc != nil {
-		return m.templateFunc(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace, apiVersions)
+		return m.templateFunc(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace, apiVersions, includeCRDs)
 	}
 	panic("not implemented")
 }
@@ -68,7 +69,7 @@ func TestRegistry_HelmChartImages_TemplateError(t *testing.T) {
 			},
 		},
 		helmClient: mockHelmClient{
-			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string) ([]map[string]any, error) {
+			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
 				return nil, fmt.Errorf("failed templating")
 			},
 		},
@@ -92,7 +93,7 @@ func TestRegistry_HelmChartImages(t *testing.T) {
 			},
 		},
 		helmClient: mockHelmClient{
-			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string) ([]map[string]any, error) {
+			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
 				return []map[string]any{
 					{
 						"kind":  "Deployment",
@@ -119,6 +120,72 @@ func TestRegistry_HelmChartImages(t *testing.T) {
 	assert.ElementsMatch(t, images, []string{"apache-image:1.1.1", "apache-image:1.2.3"})
 }
 
+func TestRegistry_HelmChartImages_ImagePaths(t *testing.T) {
+	registry := Registry{
+		helmCharts: []*helmChart{
+			{
+				HelmChart: image.HelmChart{
+					Name:           "operator",
+					RepositoryName: "operator-repo",
+					Version:        "1.0.0",
+					CRDHandling:    image.CRDHandlingInclude,
+					ImagePaths: []image.ImagePath{
+						{Kind: "Cluster", Path: "spec.image"},
+						{Kind: "Cluster", Path: "spec.components[*].image"},
+						{Kind: "Backup", Path: "spec.images[*]"},
+					},
+				},
+			},
+		},
+		helmClient: mockHelmClient{
+			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
+				assert.True(t, includeCRDs)
+
+				return []map[string]any{
+					{
+						"kind": "CustomResourceDefinition",
+						"spec": map[string]any{"image": "not-an-image"},
+					},
+					{
+						"kind": "Deployment",
+						"spec": map[string]any{"image": "operator:1.0.0"},
+					},
+					{
+						"kind": "Cluster",
+						"spec": map[string]any{
+							"image": "cluster:1.0.0",
+							"components": []any{
+								map[string]any{"image": "component-a:1.0.0"},
+								map[string]any{"name": "component-b"},
+							},
+						},
+					},
+					{
+						"kind": "Backup",
+						"spec": map[string]any{
+							"images": []any{"backup:1.0.0", "restore:1.0.0"},
+						},
+					},
+					{
+						"kind": "Cluster",
+						"spec": map[string]any{"components": "invalid"},
+					},
+				}, nil
+			},
+		},
+	}
+
+	images, err := registry.helmChartImages()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"operator:1.0.0",
+		"cluster:1.0.0",
+		"component-a:1.0.0",
+		"backup:1.0.0",
+		"restore:1.0.0",
+	}, images)
+}
+
 func TestDownloadChart_FailedAddingRepo(t *testing.T) {
 	helmChart := &image.HelmChart{}
 	helmRepo := &image.HelmRepository{
@@ -229,6 +296,64 @@ func TestDownloadChart(t *testing.T) {
 	assert.Equal(t, "apache-chart.tgz", chartPath)
 }
 
+func TestFetchChart_Cache(t *testing.T) {
+	// Setup
+	chartCache, err := cache.New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	helmChart := &image.HelmChart{
+		Name:           "apache",
+		RepositoryName: "apache-repo",
+		Version:        "10.7.0",
+	}
+	helmRepo := &image.HelmRepository{
+		Name: "apache-repo",
+		URL:  "https://charts.bitnami.com/bitnami",
+	}
+
+	helmClient := mockHelmClient{
+		addRepoFunc: func(repository *image.HelmRepository) error {
+			return nil
+		},
+		pullFunc: func(chart string, repository *image.HelmRepository, version, destDir string) (string, error) {
+			chartPath := filepath.Join(destDir, chart, fmt.Sprintf("%s-%s.tgz", chart, version))
+			require.NoError(t, os.MkdirAll(filepath.Dir(chartPath), os.ModePerm))
+			require.NoError(t, os.WriteFile(chartPath, []byte("apache chart"), 0o600))
+
+			return chartPath, nil
+		},
+	}
+
+	// Test
+	chartPath, err := fetchChart(helmClient, chartCache, helmChart, helmRepo, t.TempDir())
+	require.NoError(t, err)
+
+	// The chart is neither pulled again nor is its repository added
+	cachedChartPath, err := fetchChart(mockHelmClient{}, chartCache, helmChart, helmRepo, t.TempDir())
+	require.NoError(t, err)
+
+	// Verify
+	assert.NotEqual(t, chartPath, cachedChartPath)
+	assert.Equal(t, "apache-10.7.0.tgz", filepath.Base(cachedChartPath))
+
+	contents, err := os.ReadFile(cachedChartPath)
+	require.NoError(t, err)
+	assert.Equal(t, "apache chart", string(contents))
+
+	entries, err := chartCache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "charts/charts.bitnami.com/bitnami/apache-10.7.0.tgz", entries[0].Identifier)
+	assert.Equal(t, "https://charts.bitnami.com/bitnami/apache", entries[0].Source)
+}
+
+func TestChartCacheIdentifier(t *testing.T) {
+	assert.Equal(t, "charts/suse-edge.github.io/charts/metallb-0.14.9.tgz",
+		ChartCacheIdentifier("https://suse-edge.github.io/charts/", "metallb", "0.14.9"))
+	assert.Equal(t, "charts/registry.suse.com/edge/charts/metallb-303.0.0+up0.14.9.tgz",
+		ChartCacheIdentifier("oci://registry.suse.com/edge/charts", "metallb", "303.0.0+up0.14.9"))
+}
+
 func TestRegistry_HelmCharts(t *testing.T) {
 	helmDir, err := os.MkdirTemp("", "helm-charts-")
 	require.NoError(t, err)
@@ -280,6 +405,84 @@ func TestRegistry_HelmCharts(t *testing.T) {
 	assert.Equal(t, "abcd", charts[0].Spec.ValuesContent)
 }
 
+func TestRegistry_HelmCharts_SeparateCRDs(t *testing.T) {
+	chartFile := filepath.Join(t.TempDir(), "operator-chart.tgz")
+	require.NoError(t, os.WriteFile(chartFile, []byte("abc"), 0o600))
+
+	crd := map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "clusters.example.com"},
+	}
+
+	templatedCRD := map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "backups.example.com"},
+	}
+
+	var templateCalls int
+
+	registry := Registry{
+		helmCharts: []*helmChart{
+			{
+				HelmChart: image.HelmChart{
+					Name:           "operator",
+					RepositoryName: "operator-repo",
+					Version:        "1.0.0",
+					CRDHandling:    image.CRDHandlingSeparate,
+				},
+				localPath: chartFile,
+			},
+			{
+				HelmChart: image.HelmChart{
+					Name:           "apache",
+					RepositoryName: "apache-repo",
+					Version:        "10.7.0",
+					CRDHandling:    image.CRDHandlingInclude,
+				},
+				localPath: chartFile,
+			},
+		},
+		helmClient: mockHelmClient{
+			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
+				templateCalls++
+
+				// CRDs rendered from the chart templates are part of either render
+				resources := []map[string]any{
+					templatedCRD,
+					{
+						"kind": "Deployment",
+						"spec": map[string]any{"image": "operator:1.0.0"},
+					},
+				}
+
+				if includeCRDs {
+					resources = append(resources, crd)
+				}
+
+				return resources, nil
+			},
+		},
+	}
+
+	_, err := registry.helmChartImages()
+	require.NoError(t, err)
+
+	charts, err := registry.HelmCharts()
+	require.NoError(t, err)
+	require.Len(t, charts, 2)
+
+	assert.Equal(t, "operator", charts[0].Metadata.Name)
+	assert.Equal(t, []map[string]any{crd}, charts[0].CRDs)
+
+	assert.Equal(t, "apache", charts[1].Metadata.Name)
+	assert.Empty(t, charts[1].CRDs)
+
+	// Charts are only rendered once, in addition to the render without CRDs of the separate mode
+	assert.Equal(t, 3, templateCalls)
+}
+
 func TestRegistry_HelmCharts_NonExistingChart(t *testing.T) {
 	registry := Registry{
 		helmCharts: []*helmChart{
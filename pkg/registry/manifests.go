@@ -13,12 +13,21 @@ import (
 )
 
 func (r *Registry) manifestImages() ([]string, error) {
-	containerImages := make(map[string]bool)
+	refs, err := r.manifestImageReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	return refs.names(), nil
+}
+
+func (r *Registry) manifestImageReferences() (imageReferences, error) {
+	refs := imageReferences{}
 
 	entries, err := os.ReadDir(r.manifestsDir)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
-			return nil, nil
+			return refs, nil
 		}
 		return nil, fmt.Errorf("reading manifest dir: %w", err)
 	}
@@ -31,18 +40,13 @@ func (r *Registry) manifestImages() ([]string, error) {
 			return nil, fmt.Errorf("reading manifest '%s': %w", path, err)
 		}
 
+		source := fmt.Sprintf("manifest '%s'", entry.Name())
 		for _, resource := range resources {
-			extractManifestImages(resource, containerImages)
+			extractResourceImages(resource, r.imageRules, source, refs)
 		}
 	}
 
-	var images []string
-
-	for imageName := range containerImages {
-		images = append(images, imageName)
-	}
-
-	return images, nil
+	return refs, nil
 }
 
 func readManifest(manifestPath string) ([]map[string]any, error) {
@@ -113,3 +117,26 @@ func extractManifestImages(resource map[string]any, images map[string]bool) {
 
 	findImages(resource)
 }
+
+// extractResourceImages records the container images referenced by the containers of workloads and the ones
+// extracted by the given rules, along with the resource referencing them, e.g. `manifest 'app.yaml', Deployment 'nginx'`.
+func extractResourceImages(resource map[string]any, rules []*imageRule, source string, refs imageReferences) {
+	kind, _ := resource["kind"].(string)
+	metadata, _ := resource["metadata"].(map[string]any)
+	name, _ := metadata["name"].(string)
+
+	reason := fmt.Sprintf("%s, %s '%s'", source, kind, name)
+
+	workloadImages := map[string]bool{}
+	extractManifestImages(resource, workloadImages)
+
+	for imageName := range workloadImages {
+		refs.add(imageName, reason)
+	}
+
+	for _, rule := range rules {
+		for _, imageName := range rule.extract(resource) {
+			refs.add(imageName, fmt.Sprintf("%s (%s)", reason, rule.description))
+		}
+	}
+}
@@ -44,7 +44,7 @@ func TestManifestImages(t *testing.T) {
 		},
 	}
 
-	registry, err := New(ctx, localManifestsDir, nil, "")
+	registry, err := New(ctx, localManifestsDir, nil, nil, "")
 	require.NoError(t, err)
 
 	// Test
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/schollz/progressbar/v3"
@@ -20,13 +22,20 @@ type helmClient interface {
 	AddRepo(repository *image.HelmRepository) error
 	RegistryLogin(repository *image.HelmRepository) error
 	Pull(chart string, repository *image.HelmRepository, version, destDir string) (string, error)
-	Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string) ([]map[string]any, error)
+	Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error)
+}
+
+type artefactCache interface {
+	Lock(identifier string) (unlock func(), err error)
+	Get(identifier string) (path string, err error)
+	Put(identifier, source string, reader io.Reader) error
 }
 
 type helmChart struct {
 	image.HelmChart
 	localPath     string
 	repositoryURL string
+	resources     []map[string]any
 }
 
 type Registry struct {
@@ -36,19 +45,65 @@ type Registry struct {
 	helmCharts     []*helmChart
 	helmValuesDir  string
 	kubeVersion    string
+	imageRules     []*imageRule
+}
+
+// ImageReference is a container image of the embedded artifact registry along with the reasons it is embedded.
+type ImageReference struct {
+	Name    string   `json:"name"`
+	Reasons []string `json:"reasons"`
+}
+
+// imageReferences maps the container images to the reasons they are embedded.
+type imageReferences map[string][]string
+
+func (refs imageReferences) add(name, reason string) {
+	if !slices.Contains(refs[name], reason) {
+		refs[name] = append(refs[name], reason)
+	}
+}
+
+func (refs imageReferences) merge(other imageReferences) {
+	for name, reasons := range other {
+		for _, reason := range reasons {
+			refs.add(name, reason)
+		}
+	}
+}
+
+func (refs imageReferences) names() []string {
+	var names []string
+
+	for name := range refs {
+		names = append(names, name)
+	}
+
+	return names
 }
 
-func New(ctx *image.Context, localManifestsDir string, helmClient helmClient, helmValuesDir string) (*Registry, error) {
-	manifestsDir, err := storeManifests(ctx, localManifestsDir)
+// New pulls the configured Helm charts and prepares the manifests whose container images are embedded.
+// Pulled charts and downloaded manifests are stored in the given cache, if any, and reused by subsequent builds.
+func New(ctx *image.Context, localManifestsDir string, helmClient helmClient, artefactCache artefactCache, helmValuesDir string) (*Registry, error) {
+	manifestsDir, err := storeManifests(ctx, localManifestsDir, artefactCache)
 	if err != nil {
 		return nil, fmt.Errorf("storing manifests: %w", err)
 	}
 
-	charts, err := storeHelmCharts(ctx, helmClient)
+	charts, err := storeHelmCharts(ctx, helmClient, artefactCache)
 	if err != nil {
 		return nil, fmt.Errorf("storing helm charts: %w", err)
 	}
 
+	builtInRules, err := compileImageRules(BuiltInImageRules, "built-in")
+	if err != nil {
+		return nil, err
+	}
+
+	definitionRules, err := compileImageRules(ctx.ImageDefinition.EmbeddedArtifactRegistry.ImageRules, "image definition")
+	if err != nil {
+		return nil, err
+	}
+
 	return &Registry{
 		embeddedImages: ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages,
 		manifestsDir:   manifestsDir,
@@ -56,6 +111,7 @@ func New(ctx *image.Context, localManifestsDir string, helmClient helmClient, he
 		helmCharts:     charts,
 		helmValuesDir:  helmValuesDir,
 		kubeVersion:    ctx.ImageDefinition.Kubernetes.Version,
+		imageRules:     append(builtInRules, definitionRules...),
 	}, nil
 }
 
@@ -63,7 +119,7 @@ func (r *Registry) ManifestsPath() string {
 	return r.manifestsDir
 }
 
-func storeManifests(ctx *image.Context, localManifestsDir string) (string, error) {
+func storeManifests(ctx *image.Context, localManifestsDir string, manifestCache artefactCache) (string, error) {
 	const manifestsDir = "manifests"
 
 	var manifestsPathPopulated bool
@@ -76,12 +132,22 @@ func storeManifests(ctx *image.Context, localManifestsDir string) (string, error
 			return "", fmt.Errorf("creating manifests dir: %w", err)
 		}
 
+		scheduler := http.NewScheduler(ctx.DownloadParallelism)
+
 		for index, manifestURL := range manifestURLs {
 			filePath := filepath.Join(manifestsDestDir, fmt.Sprintf("dl-manifest-%d.yaml", index+1))
 
-			if err := http.DownloadFile(context.Background(), manifestURL, filePath, nil); err != nil {
-				return "", fmt.Errorf("downloading manifest '%s': %w", manifestURL, err)
-			}
+			scheduler.Go(func() error {
+				if err := fetchManifest(manifestCache, manifestURL, filePath); err != nil {
+					return fmt.Errorf("downloading manifest '%s': %w", manifestURL, err)
+				}
+
+				return nil
+			})
+		}
+
+		if err := scheduler.Wait(); err != nil {
+			return "", err
 		}
 
 		manifestsPathPopulated = true
@@ -104,7 +170,65 @@ func storeManifests(ctx *image.Context, localManifestsDir string) (string, error
 	return manifestsDestDir, nil
 }
 
-func storeHelmCharts(ctx *image.Context, helmClient helmClient) ([]*helmChart, error) {
+// fetchManifest copies the manifest from the cache, downloading it first if necessary. The cache entry is locked
+// throughout, so that concurrent builds sharing the cache wait for an in-flight download instead of duplicating it.
+func fetchManifest(manifestCache artefactCache, manifestURL, destPath string) error {
+	if manifestCache == nil {
+		return http.DownloadFile(context.Background(), manifestURL, destPath, nil)
+	}
+
+	identifier := ManifestCacheIdentifier(manifestURL)
+
+	unlock, err := manifestCache.Lock(identifier)
+	if err != nil {
+		return fmt.Errorf("locking manifest in cache: %w", err)
+	}
+	defer unlock()
+
+	cachedPath, err := manifestCache.Get(identifier)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("retrieving manifest from cache: %w", err)
+	}
+
+	if cachedPath != "" {
+		zap.S().Infof("Copying manifest with identifier '%s' from cache", identifier)
+
+		if err = fileio.CopyFile(cachedPath, destPath, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying manifest from cache: %w", err)
+		}
+
+		return nil
+	}
+
+	if err = http.DownloadFile(context.Background(), manifestURL, destPath, nil); err != nil {
+		return err
+	}
+
+	file, err := os.Open(destPath)
+	if err != nil {
+		return fmt.Errorf("opening manifest: %w", err)
+	}
+	defer file.Close()
+
+	if err = manifestCache.Put(identifier, manifestURL, file); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("storing manifest in cache: %w", err)
+	}
+
+	return nil
+}
+
+// ManifestCacheIdentifier returns the identifier of the manifest downloaded from the given URL in the cache,
+// e.g. `manifests/k8s.io/examples/application/nginx-app.yaml`.
+func ManifestCacheIdentifier(manifestURL string) string {
+	location := manifestURL
+	if _, after, found := strings.Cut(location, "://"); found {
+		location = after
+	}
+
+	return fmt.Sprintf("manifests/%s", strings.Trim(location, "/"))
+}
+
+func storeHelmCharts(ctx *image.Context, helmClient helmClient, chartCache artefactCache) ([]*helmChart, error) {
 	helm := &ctx.ImageDefinition.Kubernetes.Helm
 
 	if len(helm.Charts) == 0 {
@@ -133,7 +257,7 @@ func storeHelmCharts(ctx *image.Context, helmClient helmClient) ([]*helmChart, e
 		}
 
 		if _, exists := helmChartPaths[chartID]; !exists {
-			localPath, err := downloadChart(helmClient, &helm.Charts[i], repository, helmDir)
+			localPath, err := fetchChart(helmClient, chartCache, &helm.Charts[i], repository, helmDir)
 			if err != nil {
 				return nil, fmt.Errorf("downloading chart: %w", err)
 			}
@@ -167,6 +291,71 @@ func mapChartsToRepos(helm *image.Helm) map[string]*image.HelmRepository {
 	return chartRepoMap
 }
 
+// fetchChart copies the chart from the cache, pulling it first if necessary. The cache entry is locked
+// throughout, so that concurrent builds sharing the cache wait for an in-flight pull instead of duplicating it.
+func fetchChart(helmClient helmClient, chartCache artefactCache, chart *image.HelmChart, repo *image.HelmRepository, destDir string) (string, error) {
+	if chartCache == nil {
+		return downloadChart(helmClient, chart, repo, destDir)
+	}
+
+	identifier := ChartCacheIdentifier(repo.URL, chart.Name, chart.Version)
+
+	unlock, err := chartCache.Lock(identifier)
+	if err != nil {
+		return "", fmt.Errorf("locking chart in cache: %w", err)
+	}
+	defer unlock()
+
+	cachedPath, err := chartCache.Get(identifier)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("retrieving chart from cache: %w", err)
+	}
+
+	if cachedPath != "" {
+		zap.S().Infof("Copying chart with identifier '%s' from cache", identifier)
+
+		chartPath := filepath.Join(destDir, chart.Name, fmt.Sprintf("%s-%s.tgz", chart.Name, chart.Version))
+		if err = os.MkdirAll(filepath.Dir(chartPath), os.ModePerm); err != nil {
+			return "", fmt.Errorf("creating chart dir: %w", err)
+		}
+
+		if err = fileio.CopyFile(cachedPath, chartPath, fileio.NonExecutablePerms); err != nil {
+			return "", fmt.Errorf("copying chart from cache: %w", err)
+		}
+
+		return chartPath, nil
+	}
+
+	chartPath, err := downloadChart(helmClient, chart, repo, destDir)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(chartPath)
+	if err != nil {
+		return "", fmt.Errorf("opening chart: %w", err)
+	}
+	defer file.Close()
+
+	source := fmt.Sprintf("%s/%s", strings.TrimSuffix(repo.URL, "/"), chart.Name)
+	if err = chartCache.Put(identifier, source, file); err != nil && !errors.Is(err, fs.ErrExist) {
+		return "", fmt.Errorf("storing chart in cache: %w", err)
+	}
+
+	return chartPath, nil
+}
+
+// ChartCacheIdentifier returns the identifier of the Helm chart in the cache,
+// e.g. `charts/suse-edge.github.io/charts/metallb-0.14.9.tgz`.
+func ChartCacheIdentifier(repositoryURL, chart, version string) string {
+	location := repositoryURL
+	if _, after, found := strings.Cut(location, "://"); found {
+		location = after
+	}
+
+	return fmt.Sprintf("charts/%s/%s-%s.tgz", strings.Trim(location, "/"), chart, version)
+}
+
 func downloadChart(helmClient helmClient, chart *image.HelmChart, repo *image.HelmRepository, destDir string) (string, error) {
 	if strings.HasPrefix(repo.URL, "http") {
 		if err := helmClient.AddRepo(repo); err != nil {
@@ -200,6 +389,39 @@ func (r *Registry) ContainerImages() ([]string, error) {
 	return deduplicateContainerImages(r.embeddedImages, manifestImages, chartImages), nil
 }
 
+// ImageReferences lists the container images of the embedded artifact registry, ordered by name,
+// along with the resources referencing them and the rules extracting them.
+func (r *Registry) ImageReferences() ([]ImageReference, error) {
+	refs := imageReferences{}
+
+	for _, img := range r.embeddedImages {
+		refs.add(img.Name, "embeddedArtifactRegistry.images")
+	}
+
+	manifestRefs, err := r.manifestImageReferences()
+	if err != nil {
+		return nil, fmt.Errorf("getting container images from manifests: %w", err)
+	}
+
+	chartRefs, err := r.helmChartImageReferences()
+	if err != nil {
+		return nil, fmt.Errorf("getting container images from helm charts: %w", err)
+	}
+
+	refs.merge(manifestRefs)
+	refs.merge(chartRefs)
+
+	names := refs.names()
+	slices.Sort(names)
+
+	references := make([]ImageReference, 0, len(names))
+	for _, name := range names {
+		references = append(references, ImageReference{Name: name, Reasons: refs[name]})
+	}
+
+	return references, nil
+}
+
 func deduplicateContainerImages(embeddedImages []image.ContainerImage, manifestImages, chartImages []string) []string {
 	imageSet := map[string]bool{}
 
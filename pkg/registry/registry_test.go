@@ -1,12 +1,18 @@
 package registry
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
@@ -28,13 +34,87 @@ func TestRegistry_New_InvalidManifestURL(t *testing.T) {
 		},
 	}
 
-	_, err := New(ctx, "", nil, "")
+	_, err := New(ctx, "", nil, nil, "")
 	require.Error(t, err)
 
 	assert.ErrorContains(t, err, "downloading manifest 'k8s.io/examples/application/nginx-app.yaml'")
 	assert.ErrorContains(t, err, "unsupported protocol scheme")
 }
 
+func TestFetchManifest_Cache(t *testing.T) {
+	// Setup
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("kind: Deployment"))
+	}))
+	defer server.Close()
+
+	manifestCache, err := cache.New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	manifestURL := server.URL + "/manifests/app.yaml"
+	destDir := t.TempDir()
+
+	// Test
+	require.NoError(t, fetchManifest(manifestCache, manifestURL, filepath.Join(destDir, "first.yaml")))
+	require.NoError(t, fetchManifest(manifestCache, manifestURL, filepath.Join(destDir, "second.yaml")))
+
+	// Verify
+	assert.EqualValues(t, 1, requests.Load(), "the manifest must only be downloaded once")
+
+	contents, err := os.ReadFile(filepath.Join(destDir, "second.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Deployment", string(contents))
+
+	entries, err := manifestCache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ManifestCacheIdentifier(manifestURL), entries[0].Identifier)
+	assert.Equal(t, manifestURL, entries[0].Source)
+}
+
+func TestStoreManifests(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("name: " + path.Base(r.URL.Path)))
+	}))
+	defer server.Close()
+
+	var manifestURLs []string
+	for i := 1; i <= 5; i++ {
+		manifestURLs = append(manifestURLs, fmt.Sprintf("%s/manifest-%d.yaml", server.URL, i))
+	}
+
+	ctx := &image.Context{
+		BuildDir:            t.TempDir(),
+		DownloadParallelism: 2,
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{
+				Manifests: image.Manifests{
+					URLs: manifestURLs,
+				},
+			},
+		},
+	}
+
+	// Test
+	manifestsDir, err := storeManifests(ctx, "", nil)
+	require.NoError(t, err)
+
+	// Verify
+	for i := 1; i <= 5; i++ {
+		contents, err := os.ReadFile(filepath.Join(manifestsDir, fmt.Sprintf("dl-manifest-%d.yaml", i)))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("name: manifest-%d.yaml", i), string(contents))
+	}
+}
+
+func TestManifestCacheIdentifier(t *testing.T) {
+	assert.Equal(t, "manifests/k8s.io/examples/application/nginx-app.yaml",
+		ManifestCacheIdentifier("https://k8s.io/examples/application/nginx-app.yaml"))
+}
+
 func TestRegistry_ContainerImages(t *testing.T) {
 	manifestsDir := filepath.Join(os.TempDir(), "_manifests")
 	require.NoError(t, os.MkdirAll(manifestsDir, os.ModePerm))
@@ -62,7 +142,7 @@ func TestRegistry_ContainerImages(t *testing.T) {
 			},
 		},
 		helmClient: mockHelmClient{
-			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string) ([]map[string]any, error) {
+			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
 				return []map[string]any{
 					{
 						"kind":  "Deployment",
@@ -94,6 +174,93 @@ func TestRegistry_ContainerImages(t *testing.T) {
 	})
 }
 
+func TestRegistry_ImageReferences(t *testing.T) {
+	manifestsDir := t.TempDir()
+	require.NoError(t, fileio.CopyFile("testdata/sample-crd.yaml", filepath.Join(manifestsDir, "sample-crd.yaml"), fileio.NonExecutablePerms))
+
+	rules, err := compileImageRules([]image.ImageRule{
+		{Kind: "Database", Selector: "spec", Template: "{{ .repository }}:{{ .version }}"},
+	}, "image definition")
+	require.NoError(t, err)
+
+	registry := Registry{
+		embeddedImages: []image.ContainerImage{
+			{
+				Name: "nginx:1.14.2",
+			},
+		},
+		manifestsDir: manifestsDir,
+		helmCharts: []*helmChart{
+			{
+				HelmChart: image.HelmChart{
+					Name: "db-operator",
+					ImagePaths: []image.ImagePath{
+						{Kind: "Backup", Path: "spec.image"},
+					},
+				},
+			},
+		},
+		helmClient: mockHelmClient{
+			templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, includeCRDs bool) ([]map[string]any, error) {
+				return []map[string]any{
+					{
+						"kind":     "Database",
+						"metadata": map[string]any{"name": "db"},
+						"spec":     map[string]any{"repository": "registry.example.com/db", "version": "1.0.0"},
+					},
+					{
+						"kind":     "Backup",
+						"metadata": map[string]any{"name": "db-backup"},
+						"spec":     map[string]any{"image": "registry.example.com/backup:1.0.0"},
+					},
+				}, nil
+			},
+		},
+		imageRules: rules,
+	}
+
+	references, err := registry.ImageReferences()
+	require.NoError(t, err)
+
+	assert.Equal(t, []ImageReference{
+		{
+			Name:    "custom-api:1.2.3",
+			Reasons: []string{"manifest 'sample-crd.yaml', Deployment 'my-complex-app'"},
+		},
+		{
+			Name:    "mysql:5.7",
+			Reasons: []string{"manifest 'sample-crd.yaml', Deployment 'my-complex-app'"},
+		},
+		{
+			Name: "nginx:1.14.2",
+			Reasons: []string{
+				"embeddedArtifactRegistry.images",
+				"manifest 'sample-crd.yaml', Deployment 'my-nginx'",
+			},
+		},
+		{
+			Name:    "nginx:latest",
+			Reasons: []string{"manifest 'sample-crd.yaml', Deployment 'my-complex-app'"},
+		},
+		{
+			Name:    "node:14",
+			Reasons: []string{"manifest 'sample-crd.yaml', Deployment 'my-complex-app'"},
+		},
+		{
+			Name:    "redis:6.0",
+			Reasons: []string{"manifest 'sample-crd.yaml', Deployment 'my-complex-app'"},
+		},
+		{
+			Name:    "registry.example.com/backup:1.0.0",
+			Reasons: []string{"chart 'db-operator', Backup 'db-backup' (image path rule 'spec.image' of Backup)"},
+		},
+		{
+			Name:    "registry.example.com/db:1.0.0",
+			Reasons: []string{"chart 'db-operator', Database 'db' (image definition rule 'spec' of Database)"},
+		},
+	}, references)
+}
+
 func TestDeduplicateContainerImages(t *testing.T) {
 	embeddedImages := []image.ContainerImage{
 		{
@@ -0,0 +1,138 @@
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// BuiltInImageRules extract the container images referenced by the resources of common operators,
+// which are applied to the manifests and Helm charts along with the rules of the image definition.
+var BuiltInImageRules = []image.ImageRule{
+	// Operators following the Operator Lifecycle Manager conventions list their operand images
+	// in environment variables prefixed with `RELATED_IMAGE_`.
+	{
+		Selector: "..env[*]",
+		Template: `{{ if hasPrefix .name "RELATED_IMAGE_" }}{{ .value }}{{ end }}`,
+	},
+	// Prometheus Operator
+	{Kind: "Prometheus", Selector: "spec.image"},
+	{Kind: "Alertmanager", Selector: "spec.image"},
+	{Kind: "ThanosRuler", Selector: "spec.image"},
+	// CloudNativePG
+	{Kind: "Cluster", Selector: "spec.imageName"},
+	{Kind: "Pooler", Selector: "spec.template.spec.containers[*].image"},
+	{Kind: "ImageCatalog", Selector: "spec.images[*].image"},
+	{Kind: "ClusterImageCatalog", Selector: "spec.images[*].image"},
+	// Rook
+	{Kind: "CephCluster", Selector: "spec.cephVersion.image"},
+	// MinIO Operator
+	{Kind: "Tenant", Selector: "spec.image"},
+	// Elastic Cloud on Kubernetes defaults to the official image of the requested version
+	{
+		Kind:     "Elasticsearch",
+		Selector: "spec",
+		Template: `{{ with index . "image" }}{{ . }}{{ else }}docker.elastic.co/elasticsearch/elasticsearch:{{ .version }}{{ end }}`,
+	},
+	{
+		Kind:     "Kibana",
+		Selector: "spec",
+		Template: `{{ with index . "image" }}{{ . }}{{ else }}docker.elastic.co/kibana/kibana:{{ .version }}{{ end }}`,
+	},
+}
+
+var imageRuleFuncs = template.FuncMap{
+	"hasPrefix":  strings.HasPrefix,
+	"hasSuffix":  strings.HasSuffix,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+}
+
+type imageRule struct {
+	kind     string
+	selector selector
+	template *template.Template
+	// description identifies the rule as the reason for embedding the images it extracts.
+	description string
+}
+
+// ValidateImageRule reports whether the selector and the template of the rule can be parsed.
+func ValidateImageRule(rule *image.ImageRule) error {
+	_, err := compileImageRule(rule, "")
+	return err
+}
+
+func compileImageRule(rule *image.ImageRule, origin string) (*imageRule, error) {
+	s, err := parseSelector(rule.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("parsing selector: %w", err)
+	}
+
+	compiled := &imageRule{
+		kind:        rule.Kind,
+		selector:    s,
+		description: fmt.Sprintf("%s rule '%s'", origin, rule.Selector),
+	}
+
+	if rule.Kind != "" {
+		compiled.description = fmt.Sprintf("%s rule '%s' of %s", origin, rule.Selector, rule.Kind)
+	}
+
+	if rule.Template != "" {
+		// Templates referencing fields which a selected value lacks do not extract an image from it
+		compiled.template, err = template.New(rule.Selector).Funcs(imageRuleFuncs).Option("missingkey=error").Parse(rule.Template)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template: %w", err)
+		}
+	}
+
+	return compiled, nil
+}
+
+func compileImageRules(rules []image.ImageRule, origin string) ([]*imageRule, error) {
+	var compiled []*imageRule
+
+	for i := range rules {
+		rule, err := compileImageRule(&rules[i], origin)
+		if err != nil {
+			return nil, fmt.Errorf("compiling %s image rule '%s': %w", origin, rules[i].Selector, err)
+		}
+
+		compiled = append(compiled, rule)
+	}
+
+	return compiled, nil
+}
+
+// extract returns the container images referenced by the resource, if it is of the kind the rule applies to.
+func (r *imageRule) extract(resource map[string]any) []string {
+	if kind, _ := resource["kind"].(string); r.kind != "" && r.kind != kind {
+		return nil
+	}
+
+	var images []string
+
+	for _, value := range r.selector.evaluate(resource) {
+		if r.template == nil {
+			if imageName, ok := value.(string); ok && imageName != "" {
+				images = append(images, imageName)
+			}
+
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := r.template.Execute(&buf, value); err != nil {
+			continue
+		}
+
+		if imageName := strings.TrimSpace(buf.String()); imageName != "" {
+			images = append(images, imageName)
+		}
+	}
+
+	return images
+}
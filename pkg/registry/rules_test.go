@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestBuiltInImageRules(t *testing.T) {
+	rules, err := compileImageRules(BuiltInImageRules, "built-in")
+	require.NoError(t, err)
+
+	resources := []map[string]any{
+		{
+			"kind":     "Deployment",
+			"metadata": map[string]any{"name": "operator"},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{
+								"image": "operator:1.0.0",
+								"env": []any{
+									map[string]any{"name": "RELATED_IMAGE_OPERAND", "value": "operand:1.0.0"},
+									map[string]any{"name": "LOG_LEVEL", "value": "debug"},
+									map[string]any{"name": "RELATED_IMAGE_SECRET", "valueFrom": map[string]any{}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			"kind": "Cluster",
+			"spec": map[string]any{"imageName": "ghcr.io/cloudnative-pg/postgresql:16.4"},
+		},
+		{
+			"kind": "Elasticsearch",
+			"spec": map[string]any{"version": "8.15.0"},
+		},
+		{
+			"kind": "Kibana",
+			"spec": map[string]any{"version": "8.15.0", "image": "registry.example.com/kibana:8.15.0"},
+		},
+		{
+			"kind": "CephCluster",
+			"spec": map[string]any{"cephVersion": map[string]any{"image": "quay.io/ceph/ceph:v18.2.4"}},
+		},
+	}
+
+	var images []string
+	for _, resource := range resources {
+		for _, rule := range rules {
+			images = append(images, rule.extract(resource)...)
+		}
+	}
+
+	assert.ElementsMatch(t, []string{
+		"operand:1.0.0",
+		"ghcr.io/cloudnative-pg/postgresql:16.4",
+		"docker.elastic.co/elasticsearch/elasticsearch:8.15.0",
+		"registry.example.com/kibana:8.15.0",
+		"quay.io/ceph/ceph:v18.2.4",
+	}, images)
+}
+
+func TestImageRuleExtract(t *testing.T) {
+	resource := map[string]any{
+		"kind": "Database",
+		"spec": map[string]any{
+			"repository": "registry.example.com/db",
+			"version":    "1.2.3",
+			"replicas": []any{
+				map[string]any{"repository": "registry.example.com/replica", "version": "1.2.3"},
+				map[string]any{"repository": "registry.example.com/incomplete"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		rule           image.ImageRule
+		expectedImages []string
+	}{
+		"Template": {
+			rule: image.ImageRule{
+				Kind:     "Database",
+				Selector: "spec",
+				Template: "{{ .repository }}:{{ .version }}",
+			},
+			expectedImages: []string{"registry.example.com/db:1.2.3"},
+		},
+		"Template missing fields": {
+			rule: image.ImageRule{
+				Selector: "spec.replicas[*]",
+				Template: "{{ .repository }}:{{ .version }}",
+			},
+			expectedImages: []string{"registry.example.com/replica:1.2.3"},
+		},
+		"Other kind": {
+			rule: image.ImageRule{
+				Kind:     "Cluster",
+				Selector: "spec.repository",
+			},
+		},
+		"Non-string values": {
+			rule: image.ImageRule{
+				Kind:     "Database",
+				Selector: "spec.replicas",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule, err := compileImageRule(&test.rule, "test")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedImages, rule.extract(resource))
+		})
+	}
+}
+
+func TestValidateImageRule(t *testing.T) {
+	assert.NoError(t, ValidateImageRule(&image.ImageRule{Selector: "spec.image"}))
+
+	err := ValidateImageRule(&image.ImageRule{Selector: "spec."})
+	require.Error(t, err)
+	assert.EqualError(t, err, "parsing selector: missing field after '.' in 'spec.'")
+
+	err = ValidateImageRule(&image.ImageRule{Selector: "spec", Template: "{{ .repository"})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "parsing template: ")
+}
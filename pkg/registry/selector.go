@@ -0,0 +1,193 @@
+package registry
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type selectorStepType int
+
+const (
+	fieldStep selectorStepType = iota
+	wildcardStep
+	indexStep
+	descendantStep
+)
+
+type selectorStep struct {
+	stepType selectorStepType
+	field    string
+	index    int
+}
+
+// selector locates values in a resource with a subset of the JSONPath syntax:
+//
+//   - `spec.image` selects the field of a map, the leading `$.` is optional
+//   - `spec.containers[*]` or `spec.*` select every item of a list or every value of a map
+//   - `spec.containers[0]` selects a single item of a list
+//   - `..imageName` selects the field at any depth
+type selector []selectorStep
+
+func parseSelector(expression string) (selector, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(expression, "$"), ".")
+	if strings.HasPrefix(expression, "..") || strings.HasPrefix(expression, "$..") {
+		rest = "." + rest
+	}
+
+	if rest == "" {
+		return nil, fmt.Errorf("empty selector")
+	}
+
+	var steps selector
+
+	for rest != "" {
+		var step selectorStep
+		var err error
+
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			var field string
+			if field, rest = cutField(rest[2:]); field == "" || field == "*" {
+				return nil, fmt.Errorf("missing field after '..' in '%s'", expression)
+			}
+
+			step = selectorStep{stepType: descendantStep, field: field}
+		case strings.HasPrefix(rest, "."):
+			if rest = rest[1:]; rest == "" || rest[0] == '[' {
+				return nil, fmt.Errorf("missing field after '.' in '%s'", expression)
+			}
+
+			continue
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated '[' in '%s'", expression)
+			}
+
+			if step, err = parseBracket(rest[1:end]); err != nil {
+				return nil, fmt.Errorf("%w in '%s'", err, expression)
+			}
+
+			rest = rest[end+1:]
+		default:
+			var field string
+			field, rest = cutField(rest)
+
+			step = selectorStep{stepType: fieldStep, field: field}
+			if field == "*" {
+				step = selectorStep{stepType: wildcardStep}
+			}
+		}
+
+		if rest != "" && rest[0] != '.' && rest[0] != '[' {
+			return nil, fmt.Errorf("unexpected '%c' in '%s'", rest[0], expression)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// cutField splits the leading field name from the remainder of the selector.
+func cutField(s string) (field, rest string) {
+	end := strings.IndexAny(s, ".[] \t")
+	if end == -1 {
+		return s, ""
+	}
+
+	return s[:end], s[end:]
+}
+
+func parseBracket(content string) (selectorStep, error) {
+	if content == "*" {
+		return selectorStep{stepType: wildcardStep}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil || index < 0 {
+		return selectorStep{}, fmt.Errorf("invalid index '[%s]'", content)
+	}
+
+	return selectorStep{stepType: indexStep, index: index}, nil
+}
+
+// evaluate returns the values selected in the given data. Missing fields select nothing.
+func (s selector) evaluate(data any) []any {
+	values := []any{data}
+
+	for _, step := range s {
+		var next []any
+
+		for _, value := range values {
+			next = append(next, step.evaluate(value)...)
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+func (step selectorStep) evaluate(value any) []any {
+	switch step.stepType {
+	case fieldStep:
+		if fields, ok := value.(map[string]any); ok {
+			if v, ok := fields[step.field]; ok {
+				return []any{v}
+			}
+		}
+	case wildcardStep:
+		return children(value)
+	case indexStep:
+		if items, ok := value.([]any); ok && step.index < len(items) {
+			return []any{items[step.index]}
+		}
+	case descendantStep:
+		return descendants(value, step.field)
+	}
+
+	return nil
+}
+
+// children returns the items of a list or the values of a map ordered by their keys.
+func children(value any) []any {
+	switch t := value.(type) {
+	case []any:
+		return t
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		values := make([]any, 0, len(keys))
+		for _, k := range keys {
+			values = append(values, t[k])
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+// descendants returns the values of the field in the given value and all values nested in it.
+func descendants(value any, field string) []any {
+	var values []any
+
+	if fields, ok := value.(map[string]any); ok {
+		if v, ok := fields[field]; ok {
+			values = append(values, v)
+		}
+	}
+
+	for _, child := range children(value) {
+		values = append(values, descendants(child, field)...)
+	}
+
+	return values
+}
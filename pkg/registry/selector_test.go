@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := map[string]struct {
+		expression    string
+		expectedSteps selector
+		expectedError string
+	}{
+		"Fields": {
+			expression: "spec.image",
+			expectedSteps: selector{
+				{stepType: fieldStep, field: "spec"},
+				{stepType: fieldStep, field: "image"},
+			},
+		},
+		"Root": {
+			expression: "$.spec",
+			expectedSteps: selector{
+				{stepType: fieldStep, field: "spec"},
+			},
+		},
+		"Wildcards and index": {
+			expression: "spec.containers[*].env[0].*",
+			expectedSteps: selector{
+				{stepType: fieldStep, field: "spec"},
+				{stepType: fieldStep, field: "containers"},
+				{stepType: wildcardStep},
+				{stepType: fieldStep, field: "env"},
+				{stepType: indexStep, index: 0},
+				{stepType: wildcardStep},
+			},
+		},
+		"Descendants": {
+			expression: "$..imageName",
+			expectedSteps: selector{
+				{stepType: descendantStep, field: "imageName"},
+			},
+		},
+		"Nested descendants": {
+			expression: "spec..env[*]",
+			expectedSteps: selector{
+				{stepType: fieldStep, field: "spec"},
+				{stepType: descendantStep, field: "env"},
+				{stepType: wildcardStep},
+			},
+		},
+		"Empty": {
+			expression:    "$",
+			expectedError: "empty selector",
+		},
+		"Trailing dot": {
+			expression:    "spec.",
+			expectedError: "missing field after '.' in 'spec.'",
+		},
+		"Descendant wildcard": {
+			expression:    "spec..*",
+			expectedError: "missing field after '..' in 'spec..*'",
+		},
+		"Unterminated bracket": {
+			expression:    "spec.containers[*",
+			expectedError: "unterminated '[' in 'spec.containers[*'",
+		},
+		"Invalid index": {
+			expression:    "spec.containers[-1]",
+			expectedError: "invalid index '[-1]' in 'spec.containers[-1]'",
+		},
+		"Filter": {
+			expression:    "spec.containers[?(@.name)]",
+			expectedError: "invalid index '[?(@.name)]'",
+		},
+		"Whitespace": {
+			expression:    "spec image",
+			expectedError: "unexpected ' ' in 'spec image'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			steps, err := parseSelector(test.expression)
+
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedSteps, steps)
+		})
+	}
+}
+
+func TestSelectorEvaluate(t *testing.T) {
+	resource := map[string]any{
+		"kind": "Cluster",
+		"spec": map[string]any{
+			"imageName": "postgres:16",
+			"containers": []any{
+				map[string]any{"name": "a", "image": "a:1"},
+				map[string]any{"name": "b", "image": "b:1"},
+			},
+			"backup": map[string]any{
+				"imageName": "backup:1",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		expression     string
+		expectedValues []any
+	}{
+		"Field": {
+			expression:     "spec.imageName",
+			expectedValues: []any{"postgres:16"},
+		},
+		"Missing field": {
+			expression: "spec.image",
+		},
+		"List wildcard": {
+			expression:     "spec.containers[*].image",
+			expectedValues: []any{"a:1", "b:1"},
+		},
+		"List index": {
+			expression:     "spec.containers[1].name",
+			expectedValues: []any{"b"},
+		},
+		"Index out of range": {
+			expression: "spec.containers[2].name",
+		},
+		"Map wildcard": {
+			expression:     "spec.backup.*",
+			expectedValues: []any{"backup:1"},
+		},
+		"Descendants": {
+			expression:     "..imageName",
+			expectedValues: []any{"postgres:16", "backup:1"},
+		},
+		"Field of a list": {
+			expression: "spec.containers.image",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := parseSelector(test.expression)
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, test.expectedValues, s.evaluate(resource))
+		})
+	}
+}
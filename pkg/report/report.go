@@ -0,0 +1,77 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// FileName is the name of the build report in the build directory.
+const FileName = "build-report.json"
+
+// VerifiedArtefact describes an artefact which has been verified against the checksum published with its release.
+type VerifiedArtefact struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	// Digest is the SHA-256 digest of the artefact in the `sha256:<hex>` format.
+	Digest string `json:"digest"`
+}
+
+// Report collects the details of a build which are not apparent from the built image.
+type Report struct {
+	mu sync.Mutex
+
+	EIBVersion string `json:"eibVersion"`
+	// ArtifactSources are the effective artifact sources of the build, including any overlay.
+	ArtifactSources *image.ArtifactSources `json:"artifactSources,omitempty"`
+	// KubernetesRelease pins the installed Kubernetes version, along with the channel it was resolved from, if any.
+	KubernetesRelease *image.KubernetesRelease `json:"kubernetesRelease,omitempty"`
+	VerifiedArtefacts []VerifiedArtefact       `json:"verifiedArtefacts"`
+}
+
+func New(eibVersion string, artifactSources *image.ArtifactSources) *Report {
+	return &Report{
+		EIBVersion:        eibVersion,
+		ArtifactSources:   artifactSources,
+		VerifiedArtefacts: []VerifiedArtefact{},
+	}
+}
+
+// RecordVerifiedArtefact is safe for concurrent use.
+func (r *Report) RecordVerifiedArtefact(name, source, digest string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.VerifiedArtefacts = append(r.VerifiedArtefacts, VerifiedArtefact{
+		Name:   name,
+		Source: source,
+		Digest: digest,
+	})
+}
+
+// Write stores the report as JSON under the given path, listing the verified artefacts by source.
+func (r *Report) Write(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slices.SortStableFunc(r.VerifiedArtefacts, func(a, b VerifiedArtefact) int {
+		return strings.Compare(a.Source, b.Source)
+	})
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding build report: %w", err)
+	}
+
+	if err = os.WriteFile(path, append(data, '\n'), fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing build report: %w", err)
+	}
+
+	return nil
+}
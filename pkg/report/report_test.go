@@ -0,0 +1,74 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestReport_Write(t *testing.T) {
+	// Setup
+	sources := &image.ArtifactSources{}
+	sources.Kubernetes.Rke2.ReleaseURL = "https://mirror.example.com/rke2/"
+
+	r := New("v1.2.0", sources)
+	r.KubernetesRelease = &image.KubernetesRelease{
+		Version:      "v1.30.3+rke2r1",
+		Channel:      "rke2:stable",
+		ReleaseIndex: "https://update.rke2.io/v1-release/channels",
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"rke2.linux-amd64.tar.gz", "rke2-images-core.linux-amd64.tar.zst"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.RecordVerifiedArtefact(name, "https://example.com/"+name, "sha256:abc")
+		}()
+	}
+	wg.Wait()
+
+	path := filepath.Join(t.TempDir(), FileName)
+
+	// Test
+	require.NoError(t, r.Write(path))
+
+	// Verify
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var written Report
+	require.NoError(t, json.Unmarshal(data, &written))
+
+	assert.Equal(t, "v1.2.0", written.EIBVersion)
+	assert.Equal(t, sources, written.ArtifactSources)
+	assert.Equal(t, r.KubernetesRelease, written.KubernetesRelease)
+	assert.Equal(t, []VerifiedArtefact{
+		{
+			Name:   "rke2-images-core.linux-amd64.tar.zst",
+			Source: "https://example.com/rke2-images-core.linux-amd64.tar.zst",
+			Digest: "sha256:abc",
+		},
+		{
+			Name:   "rke2.linux-amd64.tar.gz",
+			Source: "https://example.com/rke2.linux-amd64.tar.gz",
+			Digest: "sha256:abc",
+		},
+	}, written.VerifiedArtefacts)
+}
+
+func TestReport_WriteEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	require.NoError(t, New("v1.2.0", nil).Write(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"verifiedArtefacts": []`)
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -18,6 +19,7 @@ const (
 	prepareTarballScriptLog  = "prepare-resolver-base-tarball-image.log"
 	tarballName              = "resolver-base-tarball-image.tar.gz"
 	tarballImgRef            = "resolver-base-tarball-image"
+	luksKeyEnv               = "LUKS_KEY"
 )
 
 //go:embed templates/prepare-tarball.sh.tpl
@@ -101,14 +103,16 @@ func (t *TarballImageBuilder) writeTarballImageScript() error {
 		ArchiveName string
 		ImgType     string
 		Arch        string
-		LUKSKey     string
+		Encrypted   bool
+		LUKSKeyEnv  string
 	}{
 		WorkDir:     t.getTarballImgDir(),
 		ImgPath:     t.getBaseISOCopyPath(),
 		ArchiveName: tarballName,
 		ImgType:     t.imgType,
 		Arch:        t.arch,
-		LUKSKey:     t.luksKey,
+		Encrypted:   t.luksKey != "",
+		LUKSKeyEnv:  luksKeyEnv,
 	}
 
 	data, err := template.Parse(prepareTarballScriptName, prepareTraballTemplate, &values)
@@ -131,7 +135,14 @@ func (t *TarballImageBuilder) runTarballImageScript() error {
 	}
 	defer logFile.Close()
 
-	cmd := t.prepareTarballImageCmd(logFile)
+	output := log.NewRedactingWriter(logFile)
+	defer func() {
+		if err = output.Close(); err != nil {
+			zap.S().Warnf("Failed to flush prepare tarball image log file properly: %s", err)
+		}
+	}()
+
+	cmd := t.prepareTarballImageCmd(output)
 	if err = cmd.Run(); err != nil {
 		return fmt.Errorf("run script failure: %w", err)
 	}
@@ -139,11 +150,17 @@ func (t *TarballImageBuilder) runTarballImageScript() error {
 	return nil
 }
 
-func (t *TarballImageBuilder) prepareTarballImageCmd(log io.Writer) *exec.Cmd {
+func (t *TarballImageBuilder) prepareTarballImageCmd(output io.Writer) *exec.Cmd {
 	scriptPath := filepath.Join(t.dir, prepareTarballScriptName)
 	cmd := exec.Command(scriptPath)
-	cmd.Stdout = log
-	cmd.Stderr = log
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	// The LUKS key is passed via the environment, so that it is not stored in the script
+	if t.luksKey != "" {
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", luksKeyEnv, t.luksKey))
+	}
+
 	return cmd
 }
 
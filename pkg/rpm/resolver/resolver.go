@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/mount"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
@@ -20,8 +21,15 @@ const (
 	rpmResolutionScriptName = "rpm-resolution.sh"
 	rpmRepoName             = "rpm-repo"
 	gpgDirName              = "gpg-keys"
+	caCertificatesName      = "eib-ca-certificates.pem"
+	caAnchorsDir            = "/etc/pki/trust/anchors"
+	regCodeSecretPath       = "/run/secrets/" + RegCodeSecretID
 )
 
+// RegCodeSecretID is the ID of the image build secret holding the registration code.
+// The Podman client must be created with the registration code under this ID.
+const RegCodeSecretID = "regcode"
+
 //go:embed templates/Dockerfile.tpl
 var dockerfileTemplate string
 
@@ -136,11 +144,19 @@ func (r *Resolver) prepare(localRPMConfig *image.LocalRPMConfig, packages *image
 		}
 	}
 
+	caCertificates := http.CertificatesFile()
+	if caCertificates != "" {
+		dest := filepath.Join(buildContext, caCertificatesName)
+		if err := fileio.CopyFile(caCertificates, dest, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying CA certificates to %s: %w", dest, err)
+		}
+	}
+
 	if err := r.writeRPMResolutionScript(localRPMConfig, packages); err != nil {
 		return fmt.Errorf("writing rpm resolution script: %w", err)
 	}
 
-	if err := r.writeDockerfile(localRPMConfig); err != nil {
+	if err := r.writeDockerfile(localRPMConfig, caCertificates != "", packages.RegCode != ""); err != nil {
 		return fmt.Errorf("writing dockerfile: %w", err)
 	}
 
@@ -180,8 +196,9 @@ func (r *Resolver) prepareLocalRPMs(localRPMConfig *image.LocalRPMConfig) error
 }
 
 func (r *Resolver) writeRPMResolutionScript(localRPMConfig *image.LocalRPMConfig, packages *image.Packages) error {
+	// The registration code is mounted as a build secret, so that it is not stored in the build context
 	values := struct {
-		RegCode      string
+		RegCodeFile  string
 		AddRepo      []image.AddRepo
 		CacheDir     string
 		PKGList      string
@@ -191,7 +208,6 @@ func (r *Resolver) writeRPMResolutionScript(localRPMConfig *image.LocalRPMConfig
 		Arch         string
 		EnableExtras bool
 	}{
-		RegCode:      packages.RegCode,
 		AddRepo:      packages.AdditionalRepos,
 		CacheDir:     r.generateResolverImgRPMRepoPath(),
 		NoGPGCheck:   packages.NoGPGCheck,
@@ -199,6 +215,10 @@ func (r *Resolver) writeRPMResolutionScript(localRPMConfig *image.LocalRPMConfig
 		EnableExtras: packages.EnableExtras,
 	}
 
+	if packages.RegCode != "" {
+		values.RegCodeFile = regCodeSecretPath
+	}
+
 	if len(packages.PKGList) > 0 {
 		values.PKGList = strings.Join(packages.PKGList, " ")
 	}
@@ -220,7 +240,7 @@ func (r *Resolver) writeRPMResolutionScript(localRPMConfig *image.LocalRPMConfig
 	return os.WriteFile(filename, []byte(data), fileio.ExecutablePerms)
 }
 
-func (r *Resolver) writeDockerfile(localRPMConfig *image.LocalRPMConfig) error {
+func (r *Resolver) writeDockerfile(localRPMConfig *image.LocalRPMConfig, trustCACertificates, register bool) error {
 	values := struct {
 		BaseImage               string
 		FromRPMPath             string
@@ -228,11 +248,23 @@ func (r *Resolver) writeDockerfile(localRPMConfig *image.LocalRPMConfig) error {
 		FromGPGPath             string
 		ToGPGPath               string
 		RPMResolutionScriptName string
+		CACertificatesName      string
+		CAAnchorsDir            string
+		RegCodeSecretID         string
 	}{
 		BaseImage:               r.baseImageRef,
 		RPMResolutionScriptName: rpmResolutionScriptName,
 	}
 
+	if register {
+		values.RegCodeSecretID = RegCodeSecretID
+	}
+
+	if trustCACertificates {
+		values.CACertificatesName = caCertificatesName
+		values.CAAnchorsDir = caAnchorsDir
+	}
+
 	if localRPMConfig != nil {
 		values.FromRPMPath = filepath.Base(r.generateRPMPathInBuildContext())
 		values.ToRPMPath = r.generateResolverImgLocalRPMDirPath()
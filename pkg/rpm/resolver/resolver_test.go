@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+)
+
+type fakePodman struct {
+	buildContext string
+}
+
+func (p *fakePodman) Import(string, string) error {
+	return nil
+}
+
+func (p *fakePodman) Build(context, _ string) error {
+	p.buildContext = context
+	return nil
+}
+
+func (p *fakePodman) Create(string) (string, error) {
+	return "resolver", nil
+}
+
+func (p *fakePodman) Copy(string, string, string) error {
+	return nil
+}
+
+func TestResolve_NoSecretsInBuildDir(t *testing.T) {
+	// Setup
+	const (
+		luksKey = "luks-s3cr3t-key"
+		regCode = "scc-s3cr3t-code"
+	)
+	log.RegisterSecrets(luksKey, regCode)
+	t.Cleanup(log.ResetSecrets)
+
+	// Stub out the image tools with scripts which echo back the arguments they were provided with
+	binDir := t.TempDir()
+	fakeTool := "#!/bin/sh\necho \"args: $*\"\n"
+	for _, tool := range []string{"guestfish", "virt-tar-out"} {
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, tool), []byte(fakeTool), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "pigz"), []byte("#!/bin/sh\ncat\n"), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	imgPath := filepath.Join(t.TempDir(), "base.raw")
+	require.NoError(t, os.WriteFile(imgPath, []byte("raw"), 0o600))
+
+	buildDir := t.TempDir()
+	podman := &fakePodman{}
+	baseBuilder := NewTarballBuilder(buildDir, imgPath, image.TypeRAW, string(image.ArchTypeX86), luksKey, podman)
+	resolver := New(buildDir, podman, baseBuilder, filepath.Join(t.TempDir(), "mounts.conf"), string(image.ArchTypeX86))
+
+	packages := &image.Packages{
+		PKGList: []string{"vim"},
+		RegCode: regCode,
+	}
+
+	// Test
+	_, _, err := resolver.Resolve(packages, nil, t.TempDir())
+
+	// Verify
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(buildDir, prepareTarballScriptLog))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "--key all:key:[REDACTED]")
+
+	contents, err = os.ReadFile(filepath.Join(podman.buildContext, dockerfileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "RUN --mount=type=secret,id=regcode ./rpm-resolution.sh")
+
+	contents, err = os.ReadFile(filepath.Join(podman.buildContext, rpmResolutionScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `suseconnect -r "$(cat /run/secrets/regcode)"`)
+
+	err = filepath.WalkDir(buildDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		assert.NotContains(t, string(contents), luksKey, "file %s contains the LUKS key", path)
+		assert.NotContains(t, string(contents), regCode, "file %s contains the registration code", path)
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -5,8 +5,16 @@
 #  FromGPGPath             - path to the directory holding the GPG keys for the custom RPMs relative to the resolver image build context in the EIB container
 #  ToGPGPath               - path to the directory holding the GPG keys for the custom RPMs relative to the resolver image
 #  RPMResolutionScriptName - name of the RPM resolution script
+#  CACertificatesName      - name of the file holding additional CA certificates in the resolver image build context
+#  CAAnchorsDir            - directory of the trust anchors of the resolver image
+#  RegCodeSecretID         - ID of the build secret holding the scc.suse.com registration code, if any
 FROM {{ .BaseImage }}
 
+{{ if .CACertificatesName -}}
+COPY {{ .CACertificatesName }} {{ .CAAnchorsDir }}/{{ .CACertificatesName }}
+RUN update-ca-certificates
+{{ end -}}
+
 COPY {{ .RPMResolutionScriptName }} {{ .RPMResolutionScriptName }}
 
 {{ if and .FromRPMPath .ToRPMPath -}}
@@ -16,6 +24,6 @@ COPY {{ .FromGPGPath }} {{ .ToGPGPath }}
 {{ end -}}
 {{ end }}
 
-RUN ./{{ .RPMResolutionScriptName }}
+RUN {{ if .RegCodeSecretID }}--mount=type=secret,id={{ .RegCodeSecretID }} {{ end }}./{{ .RPMResolutionScriptName }}
 
 CMD ["/bin/bash"]
\ No newline at end of file
@@ -6,15 +6,16 @@ set -euo pipefail
 #  ImgPath     - path to the image that will be prepared
 #  ImgType     - type of the image (either .iso, or .raw)
 #  ArchiveName - name of the virtual disk archive that will be created from this image
-#  LUKSKey     - The key necessary for modifying encrypted raw images
+#  Encrypted   - If true, the image is encrypted and accessed using the LUKS key
+#  LUKSKeyEnv  - Name of the environment variable holding the LUKS key of encrypted images
 
 WORK_DIR={{.WorkDir}}
 IMG_PATH={{.ImgPath}}
 
 # Set the LUKS key flag for encrypted images
 LUKSFLAG=""
-{{ if .LUKSKey }}
-LUKSFLAG="--key all:key:{{ .LUKSKey }}"
+{{ if .Encrypted }}
+LUKSFLAG="--key all:key:${{ .LUKSKeyEnv }}"
 {{ end }}
 
 # Make the necessarry adaptations for aarch64
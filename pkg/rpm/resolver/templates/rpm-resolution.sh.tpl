@@ -2,7 +2,7 @@
 set -euo pipefail
 
 #  Template Fields
-#  RegCode      - scc.suse.com registration code
+#  RegCodeFile  - path to the file holding the scc.suse.com registration code, if any
 #  AddRepo      - additional third-party repositories that will be used in the resolution process
 #  CacheDir     - zypper cache directory where all rpm dependencies will be downloaded to
 #  PKGList      - list of packages for which to do the dependency resolution
@@ -12,8 +12,8 @@ set -euo pipefail
 #  Arch         - sets the architecture of the rpm packages to pull
 #  EnableExtras - registers the SL-Micro-Extras repo for use in resolution
 
-{{ if ne .RegCode "" }}
-suseconnect -r {{ .RegCode }}
+{{ if ne .RegCodeFile "" }}
+suseconnect -r "$(cat {{ .RegCodeFile }})"
 {{ if $.EnableExtras -}}
 VERSION=$(awk '/VERSION=/' /etc/os-release | cut -d'"' -f2)
 suseconnect -p SL-Micro-Extras/$VERSION/{{ .Arch }}
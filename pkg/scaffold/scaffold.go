@@ -0,0 +1,159 @@
+package scaffold
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	TemplateOSOnly         = "os-only"
+	TemplateRKE2SingleNode = "rke2-single-node"
+	TemplateRKE2HA         = "rke2-ha"
+	TemplateK3sEdge        = "k3s-edge"
+
+	baseImagesDir       = "base-images"
+	kubernetesConfigDir = "kubernetes/config"
+	helmValuesDir       = "kubernetes/helm/values"
+	serverConfigFile    = "server.yaml"
+
+	rke2Version = "v1.30.3+rke2r1"
+	k3sVersion  = "v1.30.3+k3s1"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+type definitionTemplate struct {
+	// distro is the Kubernetes distribution installed by the template, if any.
+	distro string
+}
+
+var templates = map[string]definitionTemplate{
+	TemplateOSOnly:         {},
+	TemplateRKE2SingleNode: {distro: image.KubernetesDistroRKE2},
+	TemplateRKE2HA:         {distro: image.KubernetesDistroRKE2},
+	TemplateK3sEdge:        {distro: image.KubernetesDistroK3S},
+}
+
+// Templates returns the names of the available definition templates.
+func Templates() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// DefaultBaseImage returns the name of the SL Micro self install ISO for the given architecture.
+func DefaultBaseImage(arch image.Arch) string {
+	return fmt.Sprintf("SL-Micro.%s-6.0-Default-SelfInstall-GM2.install.iso", arch)
+}
+
+// Scaffold writes a commented image definition based on the given template, at the latest
+// supported API version, along with the directories and configuration files it relies on.
+//
+// Optional component directories (e.g. `network` or `os-files`) are not created since they
+// are considered configured whenever present, and fail the build when left empty. Instead,
+// they are described in the generated definition.
+//
+// Existing files are never overwritten. Returns the paths of the created files and directories,
+// relative to the configuration directory.
+func Scaffold(configDir, definitionFile, templateName string, arch image.Arch, baseImage string) ([]string, error) {
+	tmpl, ok := templates[templateName]
+	if !ok {
+		return nil, fmt.Errorf("unknown template: %s", templateName)
+	}
+
+	files := map[string]string{
+		definitionFile: templateName + ".yaml.tpl",
+	}
+	dirs := []string{baseImagesDir}
+
+	if tmpl.distro != "" {
+		files[filepath.Join(kubernetesConfigDir, serverConfigFile)] = tmpl.distro + "-server.yaml.tpl"
+		dirs = append(dirs, helmValuesDir)
+	}
+
+	for file := range files {
+		_, err := os.Stat(filepath.Join(configDir, file))
+		if err == nil {
+			return nil, fmt.Errorf("%w: %s", fs.ErrExist, file)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("checking for existing file '%s': %w", file, err)
+		}
+	}
+
+	header, err := templatesFS.ReadFile("templates/header.yaml.tpl")
+	if err != nil {
+		return nil, fmt.Errorf("reading definition header template: %w", err)
+	}
+
+	values := map[string]any{
+		"Template":    templateName,
+		"APIVersion":  version.SupportedSchemaVersions[len(version.SupportedSchemaVersions)-1],
+		"Arch":        arch,
+		"BaseImage":   baseImage,
+		"Kubernetes":  tmpl.distro != "",
+		"RKE2Version": rke2Version,
+		"K3sVersion":  k3sVersion,
+	}
+
+	var created []string
+
+	for _, dir := range dirs {
+		if err = os.MkdirAll(filepath.Join(configDir, dir), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("creating directory '%s': %w", dir, err)
+		}
+
+		created = append(created, dir+string(filepath.Separator))
+	}
+
+	fileNames := make([]string, 0, len(files))
+	for file := range files {
+		fileNames = append(fileNames, file)
+	}
+	slices.Sort(fileNames)
+
+	for _, file := range fileNames {
+		contents, err := templatesFS.ReadFile(path.Join("templates", files[file]))
+		if err != nil {
+			return nil, fmt.Errorf("reading template '%s': %w", files[file], err)
+		}
+
+		// Only the definition itself shares the common header
+		if file == definitionFile {
+			contents = slices.Concat(header, contents)
+		}
+
+		data, err := template.Parse(files[file], string(contents), values)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template '%s': %w", files[file], err)
+		}
+
+		filePath := filepath.Join(configDir, file)
+		if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("creating directory for '%s': %w", file, err)
+		}
+
+		if err = os.WriteFile(filePath, []byte(data), fileio.NonExecutablePerms); err != nil {
+			return nil, fmt.Errorf("writing file '%s': %w", file, err)
+		}
+
+		created = append(created, file)
+	}
+
+	return created, nil
+}
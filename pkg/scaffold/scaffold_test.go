@@ -0,0 +1,107 @@
+package scaffold
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+func TestScaffold(t *testing.T) {
+	tests := map[string]struct {
+		expectedFiles []string
+		expectedNodes int
+		expectedK8s   string
+	}{
+		TemplateOSOnly: {
+			expectedFiles: []string{"base-images/", "definition.yaml"},
+		},
+		TemplateRKE2SingleNode: {
+			expectedFiles: []string{"base-images/", "kubernetes/helm/values/", "definition.yaml", "kubernetes/config/server.yaml"},
+			expectedK8s:   rke2Version,
+		},
+		TemplateRKE2HA: {
+			expectedFiles: []string{"base-images/", "kubernetes/helm/values/", "definition.yaml", "kubernetes/config/server.yaml"},
+			expectedNodes: 3,
+			expectedK8s:   rke2Version,
+		},
+		TemplateK3sEdge: {
+			expectedFiles: []string{"base-images/", "kubernetes/helm/values/", "definition.yaml", "kubernetes/config/server.yaml"},
+			expectedK8s:   k3sVersion,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			configDir := t.TempDir()
+			baseImage := DefaultBaseImage(image.ArchTypeX86)
+
+			// Test
+			created, err := Scaffold(configDir, "definition.yaml", name, image.ArchTypeX86, baseImage)
+			require.NoError(t, err)
+
+			// Verify
+			assert.Equal(t, test.expectedFiles, created)
+
+			data, err := os.ReadFile(filepath.Join(configDir, "definition.yaml"))
+			require.NoError(t, err)
+
+			definition, err := image.ParseDefinition(data, configDir)
+			require.NoError(t, err)
+
+			latestVersion := version.SupportedSchemaVersions[len(version.SupportedSchemaVersions)-1]
+			assert.Equal(t, latestVersion, definition.APIVersion)
+			assert.Equal(t, image.ArchTypeX86, definition.Image.Arch)
+			assert.Equal(t, baseImage, definition.Image.BaseImage)
+			assert.Equal(t, test.expectedK8s, definition.Kubernetes.Version)
+			assert.Len(t, definition.Kubernetes.Nodes, test.expectedNodes)
+
+			// The scaffolded directory only lacks the base image to be valid
+			require.NoError(t, os.WriteFile(filepath.Join(configDir, "base-images", baseImage), nil, 0o600))
+
+			ctx := &image.Context{
+				ImageConfigDir:  configDir,
+				ImageDefinition: definition,
+			}
+
+			failures := validation.ValidateDefinition(ctx)
+			for component, componentFailures := range failures {
+				for _, failure := range componentFailures {
+					assert.NotEqual(t, validation.SeverityError, failure.Level(), "%s: %s", component, failure.UserMessage)
+				}
+			}
+		})
+	}
+}
+
+func TestScaffoldExistingDefinition(t *testing.T) {
+	// Setup
+	configDir := t.TempDir()
+	definitionPath := filepath.Join(configDir, "definition.yaml")
+	require.NoError(t, os.WriteFile(definitionPath, []byte("apiVersion: 1.0"), 0o600))
+
+	// Test
+	_, err := Scaffold(configDir, "definition.yaml", TemplateOSOnly, image.ArchTypeX86, "base.iso")
+
+	// Verify
+	require.ErrorIs(t, err, fs.ErrExist)
+	assert.EqualError(t, err, "file already exists: definition.yaml")
+
+	data, err := os.ReadFile(definitionPath)
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: 1.0", string(data))
+
+	assert.NoDirExists(t, filepath.Join(configDir, "base-images"))
+}
+
+func TestScaffoldUnknownTemplate(t *testing.T) {
+	_, err := Scaffold(t.TempDir(), "definition.yaml", "rke2-edge", image.ArchTypeX86, "base.iso")
+	assert.EqualError(t, err, "unknown template: rke2-edge")
+}
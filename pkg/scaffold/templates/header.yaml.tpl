@@ -0,0 +1,24 @@
+{{ define "header" -}}
+# Image definition generated by `eib init` from the '{{ .Template }}' template.
+#
+# The image configuration directory may contain the following optional directories,
+# which are only processed when present and must not be left empty:
+#   network/               - nmstate network configurations, one file per node hostname
+#   custom/scripts/        - custom combustion scripts executed on first boot
+#   custom/files/          - files made available to the custom scripts
+#   os-files/              - files copied onto the operating system filesystem
+#   certificates/          - CA certificates (*.pem, *.crt) to trust
+#   elemental/             - Elemental registration configuration
+#   rpms/                  - RPM packages and their GPG keys (rpms/gpg-keys/)
+{{- if .Kubernetes }}
+#   kubernetes/manifests/  - Kubernetes manifests applied after the cluster is installed
+#   kubernetes/helm/certs/ - CA certificates of the Helm repositories
+{{- end }}
+apiVersion: "{{ .APIVersion }}"
+image:
+  imageType: iso
+  arch: {{ .Arch }}
+  # Base image file, relative to the base-images/ directory
+  baseImage: {{ .BaseImage }}
+  outputImageName: eib-image.iso
+{{- end -}}
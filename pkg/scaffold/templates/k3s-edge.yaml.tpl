@@ -0,0 +1,15 @@
+{{ template "header" . }}
+operatingSystem:
+  # Block device the image is installed to
+  # isoConfiguration:
+  #   installDevice: /dev/sda
+  # Users require either an encrypted password (`openssl passwd -6`) or at least one SSH key
+  # users:
+  #   - username: root
+  #     encryptedPassword: <encrypted password>
+kubernetes:
+  # K3s release to install; the server configuration is read from kubernetes/config/server.yaml
+  version: {{ .K3sVersion }}
+  # manifests:
+  #   urls:
+  #     - <manifest URL>
@@ -0,0 +1,2 @@
+# K3s server configuration, see https://docs.k3s.io/cli/server
+write-kubeconfig-mode: "0644"
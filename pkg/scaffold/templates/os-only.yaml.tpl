@@ -0,0 +1,15 @@
+{{ template "header" . }}
+operatingSystem:
+  # Block device the image is installed to
+  # isoConfiguration:
+  #   installDevice: /dev/sda
+  time:
+    timezone: Europe/London
+  # Users require either an encrypted password (`openssl passwd -6`) or at least one SSH key
+  # users:
+  #   - username: root
+  #     encryptedPassword: <encrypted password>
+  # packages:
+  #   packageList:
+  #     - vim
+  #   sccRegistrationCode: <registration code>
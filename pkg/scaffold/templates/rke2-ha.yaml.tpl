@@ -0,0 +1,25 @@
+{{ template "header" . }}
+operatingSystem:
+  # Block device the image is installed to
+  # isoConfiguration:
+  #   installDevice: /dev/sda
+  # Users require either an encrypted password (`openssl passwd -6`) or at least one SSH key
+  # users:
+  #   - username: root
+  #     encryptedPassword: <encrypted password>
+kubernetes:
+  # RKE2 release to install; the server configuration is read from kubernetes/config/server.yaml
+  version: {{ .RKE2Version }}
+  network:
+    # Virtual IP address of the cluster API, which must be available on the node network
+    apiVIP: 192.168.122.100
+    apiHost: api.cluster.example.com
+  # Nodes are identified by their hostname, usually assigned through the network/ configurations
+  nodes:
+    - hostname: node1.example.com
+      type: server
+      initializer: true
+    - hostname: node2.example.com
+      type: server
+    - hostname: node3.example.com
+      type: server
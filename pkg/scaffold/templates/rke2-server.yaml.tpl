@@ -0,0 +1,4 @@
+# RKE2 server configuration, see https://docs.rke2.io/reference/server_config
+# Supported CNIs: none, cilium, canal, calico
+cni: cilium
+write-kubeconfig-mode: "0644"
@@ -0,0 +1,24 @@
+{{ template "header" . }}
+operatingSystem:
+  # Block device the image is installed to
+  # isoConfiguration:
+  #   installDevice: /dev/sda
+  # Users require either an encrypted password (`openssl passwd -6`) or at least one SSH key
+  # users:
+  #   - username: root
+  #     encryptedPassword: <encrypted password>
+kubernetes:
+  # RKE2 release to install; the server configuration is read from kubernetes/config/server.yaml
+  version: {{ .RKE2Version }}
+  # helm:
+  #   charts:
+  #     - name: <chart>
+  #       version: <chart version>
+  #       repositoryName: <repository>
+  #       targetNamespace: <namespace>
+  #       createNamespace: true
+  #       # Values file, relative to the kubernetes/helm/values/ directory
+  #       valuesFile: <chart>-values.yaml
+  #   repositories:
+  #     - name: <repository>
+  #       url: <repository URL>
@@ -12,7 +12,10 @@ func Parse(name string, contents string, templateData any) (string, error) {
 		return "", fmt.Errorf("template data not provided")
 	}
 
-	funcs := template.FuncMap{"join": strings.Join}
+	funcs := template.FuncMap{
+		"join":       strings.Join,
+		"shellQuote": shellQuote,
+	}
 
 	tmpl, err := template.New(name).Funcs(funcs).Parse(contents)
 	if err != nil {
@@ -26,3 +29,8 @@ func Parse(name string, contents string, templateData any) (string, error) {
 
 	return buff.String(), nil
 }
+
+// shellQuote quotes the value as a single shell word, preserving any special characters.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
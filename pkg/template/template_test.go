@@ -29,6 +29,13 @@ func TestParse(t *testing.T) {
 			},
 			expectedOutput: "ooF and raB",
 		},
+		{
+			name:           "Values are quoted for the shell",
+			templateName:   "shell-quote",
+			contents:       "{{ range . }}{{ shellQuote . }} {{ end }}",
+			templateData:   []string{"root=/dev/sda", "a&b", "it's", `c:\d`},
+			expectedOutput: `'root=/dev/sda' 'a&b' 'it'\''s' 'c:\d' `,
+		},
 		{
 			name:         "Templating fails due to missing data",
 			templateName: "missing-data",